@@ -18,6 +18,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	infrav1beta2 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
@@ -26,13 +27,28 @@ import (
 // ConvertTo converts this v1beta1 AzureStackHCICluster to the Hub version (v1beta2).
 func (src *AzureStackHCICluster) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*infrav1beta2.AzureStackHCICluster)
-	return Convert_v1beta1_AzureStackHCICluster_To_v1beta2_AzureStackHCICluster(src, dst, nil)
+	if err := Convert_v1beta1_AzureStackHCICluster_To_v1beta2_AzureStackHCICluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Restore the v1beta2 fields that v1beta1 can't represent.
+	restored := &infrav1beta2.AzureStackHCICluster{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	restoreNetworkSpec(&dst.Spec.NetworkSpec, &restored.Spec.NetworkSpec)
+	return nil
 }
 
 // ConvertFrom converts from the Hub version (v1beta2) to this v1beta1 AzureStackHCICluster.
 func (dst *AzureStackHCICluster) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*infrav1beta2.AzureStackHCICluster)
-	return Convert_v1beta2_AzureStackHCICluster_To_v1beta1_AzureStackHCICluster(src, dst, nil)
+	if err := Convert_v1beta2_AzureStackHCICluster_To_v1beta1_AzureStackHCICluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve the v1beta2 object so that the fields dropped by the conversion are restored when converting back.
+	return utilconversion.MarshalData(src, dst)
 }
 
 // ConvertTo converts this v1beta1 AzureStackHCIClusterList to the Hub version (v1beta2).
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	infrav1beta2 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+// ConvertTo converts this v1beta1 AzureStackHCIClusterTemplate to the Hub version (v1beta2).
+func (src *AzureStackHCIClusterTemplate) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1beta2.AzureStackHCIClusterTemplate)
+	if err := Convert_v1beta1_AzureStackHCIClusterTemplate_To_v1beta2_AzureStackHCIClusterTemplate(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Restore the v1beta2 fields that v1beta1 can't represent.
+	restored := &infrav1beta2.AzureStackHCIClusterTemplate{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	restoreNetworkSpec(&dst.Spec.Template.Spec.NetworkSpec, &restored.Spec.Template.Spec.NetworkSpec)
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta2) to this v1beta1 AzureStackHCIClusterTemplate.
+func (dst *AzureStackHCIClusterTemplate) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1beta2.AzureStackHCIClusterTemplate)
+	if err := Convert_v1beta2_AzureStackHCIClusterTemplate_To_v1beta1_AzureStackHCIClusterTemplate(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve the v1beta2 object so that the fields dropped by the conversion are restored when converting back.
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertTo converts this v1beta1 AzureStackHCIClusterTemplateList to the Hub version (v1beta2).
+func (src *AzureStackHCIClusterTemplateList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*infrav1beta2.AzureStackHCIClusterTemplateList)
+	return Convert_v1beta1_AzureStackHCIClusterTemplateList_To_v1beta2_AzureStackHCIClusterTemplateList(src, dst, nil)
+}
+
+// ConvertFrom converts from the Hub version (v1beta2) to this v1beta1 AzureStackHCIClusterTemplateList.
+func (dst *AzureStackHCIClusterTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*infrav1beta2.AzureStackHCIClusterTemplateList)
+	return Convert_v1beta2_AzureStackHCIClusterTemplateList_To_v1beta1_AzureStackHCIClusterTemplateList(src, dst, nil)
+}
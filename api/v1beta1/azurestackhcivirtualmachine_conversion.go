@@ -18,6 +18,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	infrav1beta2 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
@@ -26,13 +27,29 @@ import (
 // ConvertTo converts this v1beta1 AzureStackHCIVirtualMachine to the Hub version (v1beta2).
 func (src *AzureStackHCIVirtualMachine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*infrav1beta2.AzureStackHCIVirtualMachine)
-	return Convert_v1beta1_AzureStackHCIVirtualMachine_To_v1beta2_AzureStackHCIVirtualMachine(src, dst, nil)
+	if err := Convert_v1beta1_AzureStackHCIVirtualMachine_To_v1beta2_AzureStackHCIVirtualMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Restore the v1beta2 fields that v1beta1 can't represent.
+	restored := &infrav1beta2.AzureStackHCIVirtualMachine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	restoreNetworkInterfaces(dst.Spec.NetworkInterfaces, restored.Spec.NetworkInterfaces)
+	dst.Spec.CustomSize = restored.Spec.CustomSize
+	return nil
 }
 
 // ConvertFrom converts from the Hub version (v1beta2) to this v1beta1 AzureStackHCIVirtualMachine.
 func (dst *AzureStackHCIVirtualMachine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*infrav1beta2.AzureStackHCIVirtualMachine)
-	return Convert_v1beta2_AzureStackHCIVirtualMachine_To_v1beta1_AzureStackHCIVirtualMachine(src, dst, nil)
+	if err := Convert_v1beta2_AzureStackHCIVirtualMachine_To_v1beta1_AzureStackHCIVirtualMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve the v1beta2 object so that the fields dropped by the conversion are restored when converting back.
+	return utilconversion.MarshalData(src, dst)
 }
 
 // ConvertTo converts this v1beta1 AzureStackHCIVirtualMachineList to the Hub version (v1beta2).
@@ -53,6 +53,9 @@ func Convert_v1beta2_AzureStackHCIClusterStatus_To_v1beta1_AzureStackHCIClusterS
 		return err
 	}
 
+	// v1beta1 doesn't have FailureDomains, AvailabilitySets, PlacementGroups, StorageContainers, Capacity or
+	// CapacityRetries, they are dropped
+
 	// Set Ready field based on Ready condition in v1beta2
 	// Look for "Ready" condition type
 	out.Ready = false
@@ -66,6 +69,13 @@ func Convert_v1beta2_AzureStackHCIClusterStatus_To_v1beta1_AzureStackHCIClusterS
 	return nil
 }
 
+// Convert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCIClusterSpec converts v1beta2 ClusterSpec to v1beta1.
+func Convert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCIClusterSpec(in *v1beta2.AzureStackHCIClusterSpec, out *AzureStackHCIClusterSpec, s conversion.Scope) error {
+	// v1beta1 doesn't have FailureDomains, AvailabilitySets, PlacementGroups, StorageContainers or
+	// StorageContainerPolicy, they are dropped
+	return autoConvert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCIClusterSpec(in, out, s)
+}
+
 // Convert_v1beta1_AzureStackHCIMachineStatus_To_v1beta2_AzureStackHCIMachineStatus converts v1beta1 MachineStatus to v1beta2.
 func Convert_v1beta1_AzureStackHCIMachineStatus_To_v1beta2_AzureStackHCIMachineStatus(in *AzureStackHCIMachineStatus, out *v1beta2.AzureStackHCIMachineStatus, s conversion.Scope) error {
 	// Convert all common fields using auto-generated function
@@ -91,9 +101,183 @@ func Convert_v1beta2_AzureStackHCIMachineStatus_To_v1beta1_AzureStackHCIMachineS
 		return err
 	}
 
-	// v1beta2 doesn't have FailureReason/FailureMessage fields
-	// Leave them nil as they should be derived from conditions by the controller
+	// v1beta1 doesn't have FailureDomain, it is dropped
+
+	return nil
+}
+
+// Convert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHCILoadBalancerStatus converts v1beta2 LoadBalancerStatus to v1beta1.
+func Convert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHCILoadBalancerStatus(in *v1beta2.AzureStackHCILoadBalancerStatus, out *AzureStackHCILoadBalancerStatus, s conversion.Scope) error {
+	// v1beta1 doesn't have Listeners, IPv6Address, Upgrade, Autoscaling, CapacityRetries or the health check counters,
+	// they are dropped
+	return autoConvert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHCILoadBalancerStatus(in, out, s)
+}
+
+// Convert_v1beta2_VM_To_v1beta1_VM converts v1beta2 VM to v1beta1.
+func Convert_v1beta2_VM_To_v1beta1_VM(in *v1beta2.VM, out *VM, s conversion.Scope) error {
+	// v1beta1 doesn't have Host or PowerState, they are dropped
+	return autoConvert_v1beta2_VM_To_v1beta1_VM(in, out, s)
+}
+
+// Convert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStackHCIVirtualMachineStatus converts v1beta2 VirtualMachineStatus to v1beta1.
+func Convert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStackHCIVirtualMachineStatus(in *v1beta2.AzureStackHCIVirtualMachineStatus, out *AzureStackHCIVirtualMachineStatus, s conversion.Scope) error {
+	// v1beta1 doesn't have Host, PowerState, CapacityRetries, CapacityChecks or NextCapacityCheckTime, they are dropped
+	return autoConvert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStackHCIVirtualMachineStatus(in, out, s)
+}
+
+// Convert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMachineTemplate converts v1beta2 MachineTemplate to v1beta1.
+func Convert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMachineTemplate(in *v1beta2.AzureStackHCIMachineTemplate, out *AzureStackHCIMachineTemplate, s conversion.Scope) error {
+	// v1beta1 doesn't have a Status, it is dropped
+	return autoConvert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMachineTemplate(in, out, s)
+}
+
+// Convert_v1beta2_VnetSpec_To_v1beta1_VnetSpec converts v1beta2 VnetSpec to v1beta1.
+func Convert_v1beta2_VnetSpec_To_v1beta1_VnetSpec(in *v1beta2.VnetSpec, out *VnetSpec, s conversion.Scope) error {
+	if err := autoConvert_v1beta2_VnetSpec_To_v1beta1_VnetSpec(in, out, s); err != nil {
+		return err
+	}
+
+	// v1beta1 only has a single CidrBlock, keep the first of CidrBlocks. The other blocks are restored from
+	// the conversion data annotation.
+	if out.CidrBlock == "" && len(in.CidrBlocks) > 0 {
+		out.CidrBlock = in.CidrBlocks[0]
+	}
+
+	return nil
+}
+
+// Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec converts v1beta2 SubnetSpec to v1beta1.
+func Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(in *v1beta2.SubnetSpec, out *SubnetSpec, s conversion.Scope) error {
+	if err := autoConvert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(in, out, s); err != nil {
+		return err
+	}
+
+	// v1beta1 only has a single CidrBlock, keep the first of CidrBlocks. The other blocks are restored from
+	// the conversion data annotation.
+	if out.CidrBlock == "" && len(in.CidrBlocks) > 0 {
+		out.CidrBlock = in.CidrBlocks[0]
+	}
+
+	return nil
+}
+
+// Convert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec converts v1beta2 IpConfigurationSpec to v1beta1.
+func Convert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec(in *v1beta2.IpConfigurationSpec, out *IpConfigurationSpec, s conversion.Scope) error {
+	// v1beta1 doesn't have IPVersion, it is restored from the conversion data annotation
+	return autoConvert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec(in, out, s)
+}
 
+// Convert_v1beta1_NetworkInterfaces_To_v1beta2_NetworkInterfaces converts a slice of NetworkInterfaceSpec pointers.
+func Convert_v1beta1_NetworkInterfaces_To_v1beta2_NetworkInterfaces(in *NetworkInterfaces, out *v1beta2.NetworkInterfaces, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = make(v1beta2.NetworkInterfaces, len(*in))
+	for i := range *in {
+		if (*in)[i] == nil {
+			continue
+		}
+		(*out)[i] = new(v1beta2.NetworkInterfaceSpec)
+		if err := Convert_v1beta1_NetworkInterfaceSpec_To_v1beta2_NetworkInterfaceSpec((*in)[i], (*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert_v1beta2_NetworkInterfaces_To_v1beta1_NetworkInterfaces converts a slice of NetworkInterfaceSpec pointers.
+func Convert_v1beta2_NetworkInterfaces_To_v1beta1_NetworkInterfaces(in *v1beta2.NetworkInterfaces, out *NetworkInterfaces, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = make(NetworkInterfaces, len(*in))
+	for i := range *in {
+		if (*in)[i] == nil {
+			continue
+		}
+		(*out)[i] = new(NetworkInterfaceSpec)
+		if err := Convert_v1beta2_NetworkInterfaceSpec_To_v1beta1_NetworkInterfaceSpec((*in)[i], (*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert_v1beta1_IpConfigurations_To_v1beta2_IpConfigurations converts a slice of IpConfigurationSpec pointers.
+func Convert_v1beta1_IpConfigurations_To_v1beta2_IpConfigurations(in *IpConfigurations, out *v1beta2.IpConfigurations, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = make(v1beta2.IpConfigurations, len(*in))
+	for i := range *in {
+		if (*in)[i] == nil {
+			continue
+		}
+		(*out)[i] = new(v1beta2.IpConfigurationSpec)
+		if err := Convert_v1beta1_IpConfigurationSpec_To_v1beta2_IpConfigurationSpec((*in)[i], (*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert_v1beta2_IpConfigurations_To_v1beta1_IpConfigurations converts a slice of IpConfigurationSpec pointers.
+func Convert_v1beta2_IpConfigurations_To_v1beta1_IpConfigurations(in *v1beta2.IpConfigurations, out *IpConfigurations, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = make(IpConfigurations, len(*in))
+	for i := range *in {
+		if (*in)[i] == nil {
+			continue
+		}
+		(*out)[i] = new(IpConfigurationSpec)
+		if err := Convert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec((*in)[i], (*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert_v1beta1_Subnets_To_v1beta2_Subnets converts a slice of SubnetSpec pointers.
+func Convert_v1beta1_Subnets_To_v1beta2_Subnets(in *Subnets, out *v1beta2.Subnets, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = make(v1beta2.Subnets, len(*in))
+	for i := range *in {
+		if (*in)[i] == nil {
+			continue
+		}
+		(*out)[i] = new(v1beta2.SubnetSpec)
+		if err := Convert_v1beta1_SubnetSpec_To_v1beta2_SubnetSpec((*in)[i], (*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert_v1beta2_Subnets_To_v1beta1_Subnets converts a slice of SubnetSpec pointers.
+func Convert_v1beta2_Subnets_To_v1beta1_Subnets(in *v1beta2.Subnets, out *Subnets, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = make(Subnets, len(*in))
+	for i := range *in {
+		if (*in)[i] == nil {
+			continue
+		}
+		(*out)[i] = new(SubnetSpec)
+		if err := Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec((*in)[i], (*out)[i], s); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -333,6 +517,7 @@ func Convert_v1beta2_AzureStackHCIMachineSpec_To_v1beta1_AzureStackHCIMachineSpe
 	out.AdditionalSSHKeys = in.AdditionalSSHKeys
 	out.AvailabilitySetName = in.AvailabilitySetName
 	out.PlacementGroupName = in.PlacementGroupName
+	// v1beta1 doesn't have CustomSize, it is dropped
 
 	// Convert NetworkInterfaces
 	if in.NetworkInterfaces != nil {
@@ -470,6 +655,7 @@ func Convert_v1beta2_AzureStackHCIVirtualMachineSpec_To_v1beta1_AzureStackHCIVir
 	out.AdditionalSSHKeys = in.AdditionalSSHKeys
 	out.AvailabilitySetName = in.AvailabilitySetName
 	out.PlacementGroupName = in.PlacementGroupName
+	// v1beta1 doesn't have CustomSize or PowerState, they are dropped
 
 	// Convert NetworkInterfaces
 	if in.NetworkInterfaces != nil {
@@ -554,6 +740,59 @@ func Convert_v1beta2_AzureStackHCILoadBalancerSpec_To_v1beta1_AzureStackHCILoadB
 	return nil
 }
 
+// restoreNetworkSpec restores the CIDR blocks of the vnet and subnets dropped by the down-conversion to v1beta1.
+func restoreNetworkSpec(dst, restored *v1beta2.NetworkSpec) {
+	restoreCidrBlocks(&dst.Vnet.CidrBlock, &dst.Vnet.CidrBlocks, restored.Vnet.CidrBlock, restored.Vnet.CidrBlocks)
+	for _, subnet := range dst.Subnets {
+		if subnet == nil {
+			continue
+		}
+		for _, restoredSubnet := range restored.Subnets {
+			if restoredSubnet != nil && restoredSubnet.Name == subnet.Name {
+				restoreCidrBlocks(&subnet.CidrBlock, &subnet.CidrBlocks, restoredSubnet.CidrBlock, restoredSubnet.CidrBlocks)
+				break
+			}
+		}
+	}
+}
+
+// restoreCidrBlocks restores the CIDR blocks unless the CIDR block was changed through v1beta1, in which case
+// the v1beta1 CIDR block is kept.
+func restoreCidrBlocks(cidrBlock *string, cidrBlocks *[]string, restoredCidrBlock string, restoredCidrBlocks []string) {
+	downConverted := restoredCidrBlock
+	if downConverted == "" && len(restoredCidrBlocks) > 0 {
+		downConverted = restoredCidrBlocks[0]
+	}
+	if *cidrBlock != downConverted {
+		return
+	}
+	*cidrBlock = restoredCidrBlock
+	*cidrBlocks = restoredCidrBlocks
+}
+
+// restoreNetworkInterfaces restores the ip version of the ip configurations dropped by the down-conversion to v1beta1.
+func restoreNetworkInterfaces(dst, restored v1beta2.NetworkInterfaces) {
+	for _, nic := range dst {
+		if nic == nil {
+			continue
+		}
+		for _, restoredNic := range restored {
+			if restoredNic == nil || restoredNic.Name != nic.Name {
+				continue
+			}
+			for _, ipConfig := range nic.IPConfigurations {
+				for _, restoredIPConfig := range restoredNic.IPConfigurations {
+					if ipConfig != nil && restoredIPConfig != nil && restoredIPConfig.Name == ipConfig.Name {
+						ipConfig.IPVersion = restoredIPConfig.IPVersion
+						break
+					}
+				}
+			}
+			break
+		}
+	}
+}
+
 // Helper function to create a pointer to a bool value
 func boolPtr(b bool) *bool {
 	return &b
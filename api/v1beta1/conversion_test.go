@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+
+	infrav1beta2 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+// TestAzureStackHCIClusterConversionRestoresCidrBlocks verifies that the CIDR blocks v1beta1 can't represent
+// survive a round trip through v1beta1.
+func TestAzureStackHCIClusterConversionRestoresCidrBlocks(t *testing.T) {
+	hub := func() *infrav1beta2.AzureStackHCICluster {
+		return &infrav1beta2.AzureStackHCICluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: infrav1beta2.AzureStackHCIClusterSpec{
+				NetworkSpec: infrav1beta2.NetworkSpec{
+					Vnet: infrav1beta2.VnetSpec{Name: "vnet", CidrBlocks: []string{"10.0.0.0/16", "fd00::/64"}},
+					Subnets: infrav1beta2.Subnets{
+						{Name: "subnet", CidrBlocks: []string{"10.0.1.0/24", "fd00::/80"}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(*AzureStackHCICluster)
+		wantVnetBlock  string
+		wantVnetBlocks []string
+	}{
+		{
+			name:           "unchanged spoke restores all blocks",
+			modify:         func(*AzureStackHCICluster) {},
+			wantVnetBlocks: []string{"10.0.0.0/16", "fd00::/64"},
+		},
+		{
+			name: "cidr block changed through v1beta1 wins",
+			modify: func(c *AzureStackHCICluster) {
+				c.Spec.NetworkSpec.Vnet.CidrBlock = "192.168.0.0/16"
+			},
+			wantVnetBlock: "192.168.0.0/16",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			spoke := &AzureStackHCICluster{}
+			g.Expect(spoke.ConvertFrom(hub())).To(Succeed())
+			g.Expect(spoke.Spec.NetworkSpec.Vnet.CidrBlock).To(Equal("10.0.0.0/16"))
+			g.Expect(spoke.GetAnnotations()).To(HaveKey(utilconversion.DataAnnotation))
+			tc.modify(spoke)
+
+			restored := &infrav1beta2.AzureStackHCICluster{}
+			g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+			g.Expect(restored.GetAnnotations()).NotTo(HaveKey(utilconversion.DataAnnotation))
+			g.Expect(restored.Spec.NetworkSpec.Vnet.CidrBlock).To(Equal(tc.wantVnetBlock))
+			g.Expect(restored.Spec.NetworkSpec.Vnet.CidrBlocks).To(Equal(tc.wantVnetBlocks))
+			g.Expect(restored.Spec.NetworkSpec.Subnets[0].CidrBlocks).To(Equal([]string{"10.0.1.0/24", "fd00::/80"}))
+		})
+	}
+}
+
+// TestAzureStackHCIMachineConversionRestoresIPVersion verifies that the ip version of the ip configurations and the
+// custom VM size survive a round trip through v1beta1.
+func TestAzureStackHCIMachineConversionRestoresIPVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	hub := &infrav1beta2.AzureStackHCIMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine"},
+		Spec: infrav1beta2.AzureStackHCIMachineSpec{
+			VMSize:     "Custom",
+			CustomSize: &infrav1beta2.VMCustomSize{CpuCount: 6, MemoryMB: 12288},
+			NetworkInterfaces: infrav1beta2.NetworkInterfaces{
+				{
+					Name: "nic",
+					IPConfigurations: infrav1beta2.IpConfigurations{
+						{Name: "ipv4", Primary: true, IPVersion: infrav1beta2.IPv4},
+						{Name: "ipv6", IPVersion: infrav1beta2.IPv6},
+					},
+				},
+			},
+		},
+	}
+
+	spoke := &AzureStackHCIMachine{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+	restored := &infrav1beta2.AzureStackHCIMachine{}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	ipConfigs := restored.Spec.NetworkInterfaces[0].IPConfigurations
+	g.Expect(ipConfigs[0].IPVersion).To(Equal(infrav1beta2.IPv4))
+	g.Expect(ipConfigs[1].IPVersion).To(Equal(infrav1beta2.IPv6))
+	g.Expect(restored.Spec.CustomSize).To(Equal(hub.Spec.CustomSize))
+}
+
+// TestConversionWithoutData verifies that objects created through v1beta1 convert without conversion data.
+func TestConversionWithoutData(t *testing.T) {
+	g := NewWithT(t)
+
+	spoke := &AzureStackHCICluster{
+		Spec: AzureStackHCIClusterSpec{
+			NetworkSpec: NetworkSpec{Vnet: VnetSpec{CidrBlock: "10.0.0.0/16"}},
+		},
+	}
+	hub := &infrav1beta2.AzureStackHCICluster{}
+	g.Expect(spoke.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub.Spec.NetworkSpec.Vnet.CidrBlock).To(Equal("10.0.0.0/16"))
+	g.Expect(hub.Spec.NetworkSpec.Vnet.CidrBlocks).To(BeEmpty())
+}
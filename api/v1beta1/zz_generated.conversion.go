@@ -62,11 +62,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.AzureStackHCIClusterSpec)(nil), (*AzureStackHCIClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCIClusterSpec(a.(*v1beta2.AzureStackHCIClusterSpec), b.(*AzureStackHCIClusterSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AzureStackHCIClusterSpec)(nil), (*v1beta2.AzureStackHCIClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AzureStackHCIClusterSpec_To_v1beta2_AzureStackHCIClusterSpec(a.(*AzureStackHCIClusterSpec), b.(*v1beta2.AzureStackHCIClusterSpec), scope)
 	}); err != nil {
@@ -132,11 +127,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.AzureStackHCILoadBalancerStatus)(nil), (*AzureStackHCILoadBalancerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHCILoadBalancerStatus(a.(*v1beta2.AzureStackHCILoadBalancerStatus), b.(*AzureStackHCILoadBalancerStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AzureStackHCILoadBalancerStatus)(nil), (*v1beta2.AzureStackHCILoadBalancerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AzureStackHCILoadBalancerStatus_To_v1beta2_AzureStackHCILoadBalancerStatus(a.(*AzureStackHCILoadBalancerStatus), b.(*v1beta2.AzureStackHCILoadBalancerStatus), scope)
 	}); err != nil {
@@ -172,11 +162,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.AzureStackHCIMachineTemplate)(nil), (*AzureStackHCIMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMachineTemplate(a.(*v1beta2.AzureStackHCIMachineTemplate), b.(*AzureStackHCIMachineTemplate), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AzureStackHCIMachineTemplate)(nil), (*v1beta2.AzureStackHCIMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AzureStackHCIMachineTemplate_To_v1beta2_AzureStackHCIMachineTemplate(a.(*AzureStackHCIMachineTemplate), b.(*v1beta2.AzureStackHCIMachineTemplate), scope)
 	}); err != nil {
@@ -242,21 +227,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.AzureStackHCIVirtualMachineStatus)(nil), (*AzureStackHCIVirtualMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStackHCIVirtualMachineStatus(a.(*v1beta2.AzureStackHCIVirtualMachineStatus), b.(*AzureStackHCIVirtualMachineStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AzureStackHCIVirtualMachineStatus)(nil), (*v1beta2.AzureStackHCIVirtualMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AzureStackHCIVirtualMachineStatus_To_v1beta2_AzureStackHCIVirtualMachineStatus(a.(*AzureStackHCIVirtualMachineStatus), b.(*v1beta2.AzureStackHCIVirtualMachineStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.IpConfigurationSpec)(nil), (*IpConfigurationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec(a.(*v1beta2.IpConfigurationSpec), b.(*IpConfigurationSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*IpConfigurationSpec)(nil), (*v1beta2.IpConfigurationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_IpConfigurationSpec_To_v1beta2_IpConfigurationSpec(a.(*IpConfigurationSpec), b.(*v1beta2.IpConfigurationSpec), scope)
 	}); err != nil {
@@ -292,31 +267,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.SubnetSpec)(nil), (*SubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(a.(*v1beta2.SubnetSpec), b.(*SubnetSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*SubnetSpec)(nil), (*v1beta2.SubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_SubnetSpec_To_v1beta2_SubnetSpec(a.(*SubnetSpec), b.(*v1beta2.SubnetSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.VM)(nil), (*VM)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_VM_To_v1beta1_VM(a.(*v1beta2.VM), b.(*VM), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VM)(nil), (*v1beta2.VM)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VM_To_v1beta2_VM(a.(*VM), b.(*v1beta2.VM), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.VnetSpec)(nil), (*VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_VnetSpec_To_v1beta1_VnetSpec(a.(*v1beta2.VnetSpec), b.(*VnetSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*VnetSpec)(nil), (*v1beta2.VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VnetSpec_To_v1beta2_VnetSpec(a.(*VnetSpec), b.(*v1beta2.VnetSpec), scope)
 	}); err != nil {
@@ -382,11 +342,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*IpConfigurations)(nil), (*v1beta2.IpConfigurations)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_IpConfigurations_To_v1beta2_IpConfigurations(a.(*IpConfigurations), b.(*v1beta2.IpConfigurations), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*NetworkInterfaces)(nil), (*v1beta2.NetworkInterfaces)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkInterfaces_To_v1beta2_NetworkInterfaces(a.(*NetworkInterfaces), b.(*v1beta2.NetworkInterfaces), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*OSDisk)(nil), (*v1beta2.OSDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_OSDisk_To_v1beta2_OSDisk(a.(*OSDisk), b.(*v1beta2.OSDisk), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*Subnets)(nil), (*v1beta2.Subnets)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Subnets_To_v1beta2_Subnets(a.(*Subnets), b.(*v1beta2.Subnets), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*corev1beta2.APIEndpoint)(nil), (*corev1beta1.APIEndpoint)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_APIEndpoint_To_v1beta1_APIEndpoint(a.(*corev1beta2.APIEndpoint), b.(*corev1beta1.APIEndpoint), scope)
 	}); err != nil {
@@ -397,6 +372,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.AzureStackHCIClusterSpec)(nil), (*AzureStackHCIClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCIClusterSpec(a.(*v1beta2.AzureStackHCIClusterSpec), b.(*AzureStackHCIClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.AzureStackHCIClusterStatus)(nil), (*AzureStackHCIClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_AzureStackHCIClusterStatus_To_v1beta1_AzureStackHCIClusterStatus(a.(*v1beta2.AzureStackHCIClusterStatus), b.(*AzureStackHCIClusterStatus), scope)
 	}); err != nil {
@@ -407,6 +387,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.AzureStackHCILoadBalancerStatus)(nil), (*AzureStackHCILoadBalancerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHCILoadBalancerStatus(a.(*v1beta2.AzureStackHCILoadBalancerStatus), b.(*AzureStackHCILoadBalancerStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.AzureStackHCIMachineSpec)(nil), (*AzureStackHCIMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_AzureStackHCIMachineSpec_To_v1beta1_AzureStackHCIMachineSpec(a.(*v1beta2.AzureStackHCIMachineSpec), b.(*AzureStackHCIMachineSpec), scope)
 	}); err != nil {
@@ -417,21 +402,66 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.AzureStackHCIMachineTemplate)(nil), (*AzureStackHCIMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMachineTemplate(a.(*v1beta2.AzureStackHCIMachineTemplate), b.(*AzureStackHCIMachineTemplate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.AzureStackHCIVirtualMachineSpec)(nil), (*AzureStackHCIVirtualMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_AzureStackHCIVirtualMachineSpec_To_v1beta1_AzureStackHCIVirtualMachineSpec(a.(*v1beta2.AzureStackHCIVirtualMachineSpec), b.(*AzureStackHCIVirtualMachineSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.AzureStackHCIVirtualMachineStatus)(nil), (*AzureStackHCIVirtualMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStackHCIVirtualMachineStatus(a.(*v1beta2.AzureStackHCIVirtualMachineStatus), b.(*AzureStackHCIVirtualMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.Image)(nil), (*Image)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_Image_To_v1beta1_Image(a.(*v1beta2.Image), b.(*Image), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.IpConfigurationSpec)(nil), (*IpConfigurationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec(a.(*v1beta2.IpConfigurationSpec), b.(*IpConfigurationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta2.IpConfigurations)(nil), (*IpConfigurations)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_IpConfigurations_To_v1beta1_IpConfigurations(a.(*v1beta2.IpConfigurations), b.(*IpConfigurations), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta2.NetworkInterfaces)(nil), (*NetworkInterfaces)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NetworkInterfaces_To_v1beta1_NetworkInterfaces(a.(*v1beta2.NetworkInterfaces), b.(*NetworkInterfaces), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.OSDisk)(nil), (*OSDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_OSDisk_To_v1beta1_OSDisk(a.(*v1beta2.OSDisk), b.(*OSDisk), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.SubnetSpec)(nil), (*SubnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(a.(*v1beta2.SubnetSpec), b.(*SubnetSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta2.Subnets)(nil), (*Subnets)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_Subnets_To_v1beta1_Subnets(a.(*v1beta2.Subnets), b.(*Subnets), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta2.VM)(nil), (*VM)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VM_To_v1beta1_VM(a.(*v1beta2.VM), b.(*VM), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta2.VnetSpec)(nil), (*VnetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_VnetSpec_To_v1beta1_VnetSpec(a.(*v1beta2.VnetSpec), b.(*VnetSpec), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -541,14 +571,14 @@ func autoConvert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCICluste
 	}
 	out.Version = (*string)(unsafe.Pointer(in.Version))
 	out.Management = in.Management
+	// WARNING: in.FailureDomains requires manual conversion: does not exist in peer-type
+	// WARNING: in.AvailabilitySets requires manual conversion: does not exist in peer-type
+	// WARNING: in.PlacementGroups requires manual conversion: does not exist in peer-type
+	// WARNING: in.StorageContainers requires manual conversion: does not exist in peer-type
+	// WARNING: in.StorageContainerPolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCIClusterSpec is an autogenerated conversion function.
-func Convert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCIClusterSpec(in *v1beta2.AzureStackHCIClusterSpec, out *AzureStackHCIClusterSpec, s conversion.Scope) error {
-	return autoConvert_v1beta2_AzureStackHCIClusterSpec_To_v1beta1_AzureStackHCIClusterSpec(in, out, s)
-}
-
 func autoConvert_v1beta1_AzureStackHCIClusterSpec_To_v1beta2_AzureStackHCIClusterSpec(in *AzureStackHCIClusterSpec, out *v1beta2.AzureStackHCIClusterSpec, s conversion.Scope) error {
 	if err := Convert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(&in.NetworkSpec, &out.NetworkSpec, s); err != nil {
 		return err
@@ -593,6 +623,12 @@ func autoConvert_v1beta2_AzureStackHCIClusterStatus_To_v1beta1_AzureStackHCIClus
 	} else {
 		out.Conditions = nil
 	}
+	// WARNING: in.FailureDomains requires manual conversion: does not exist in peer-type
+	// WARNING: in.AvailabilitySets requires manual conversion: does not exist in peer-type
+	// WARNING: in.PlacementGroups requires manual conversion: does not exist in peer-type
+	// WARNING: in.StorageContainers requires manual conversion: does not exist in peer-type
+	// WARNING: in.Capacity requires manual conversion: does not exist in peer-type
+	// WARNING: in.CapacityRetries requires manual conversion: does not exist in peer-type
 	// WARNING: in.Initialization requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -810,9 +846,19 @@ func Convert_v1beta1_AzureStackHCILoadBalancerList_To_v1beta2_AzureStackHCILoadB
 func autoConvert_v1beta2_AzureStackHCILoadBalancerSpec_To_v1beta1_AzureStackHCILoadBalancerSpec(in *v1beta2.AzureStackHCILoadBalancerSpec, out *AzureStackHCILoadBalancerSpec, s conversion.Scope) error {
 	out.SSHPublicKey = in.SSHPublicKey
 	// WARNING: in.Image requires manual conversion: inconvertible types (*github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2.Image vs github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta1.Image)
+	// WARNING: in.Version requires manual conversion: does not exist in peer-type
 	out.VMSize = in.VMSize
 	out.StorageContainer = in.StorageContainer
 	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	// WARNING: in.VipPool requires manual conversion: does not exist in peer-type
+	// WARNING: in.StaticVIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.Rules requires manual conversion: does not exist in peer-type
+	// WARNING: in.Probes requires manual conversion: does not exist in peer-type
+	// WARNING: in.Listeners requires manual conversion: does not exist in peer-type
+	// WARNING: in.Strategy requires manual conversion: does not exist in peer-type
+	// WARNING: in.HealthCheck requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Autoscaling requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -831,7 +877,14 @@ func autoConvert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHC
 	out.ReadyReplicas = in.ReadyReplicas
 	out.FailedReplicas = in.FailedReplicas
 	out.Address = in.Address
+	// WARNING: in.IPv6Address requires manual conversion: does not exist in peer-type
 	out.Port = in.Port
+	// WARNING: in.Listeners requires manual conversion: does not exist in peer-type
+	// WARNING: in.UnhealthyReplicas requires manual conversion: does not exist in peer-type
+	// WARNING: in.RemediationsAllowed requires manual conversion: does not exist in peer-type
+	// WARNING: in.Autoscaling requires manual conversion: does not exist in peer-type
+	// WARNING: in.Upgrade requires manual conversion: does not exist in peer-type
+	// WARNING: in.CapacityRetries requires manual conversion: does not exist in peer-type
 	out.Phase = in.Phase
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -850,11 +903,6 @@ func autoConvert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHC
 	return nil
 }
 
-// Convert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHCILoadBalancerStatus is an autogenerated conversion function.
-func Convert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHCILoadBalancerStatus(in *v1beta2.AzureStackHCILoadBalancerStatus, out *AzureStackHCILoadBalancerStatus, s conversion.Scope) error {
-	return autoConvert_v1beta2_AzureStackHCILoadBalancerStatus_To_v1beta1_AzureStackHCILoadBalancerStatus(in, out, s)
-}
-
 func autoConvert_v1beta1_AzureStackHCILoadBalancerStatus_To_v1beta2_AzureStackHCILoadBalancerStatus(in *AzureStackHCILoadBalancerStatus, out *v1beta2.AzureStackHCILoadBalancerStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Replicas = in.Replicas
@@ -992,6 +1040,7 @@ func Convert_v1beta1_AzureStackHCIMachineProviderCondition_To_v1beta2_AzureStack
 func autoConvert_v1beta2_AzureStackHCIMachineSpec_To_v1beta1_AzureStackHCIMachineSpec(in *v1beta2.AzureStackHCIMachineSpec, out *AzureStackHCIMachineSpec, s conversion.Scope) error {
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.VMSize = in.VMSize
+	// WARNING: in.CustomSize requires manual conversion: does not exist in peer-type
 	// WARNING: in.AvailabilityZone requires manual conversion: inconvertible types (*github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2.AvailabilityZone vs github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta1.AvailabilityZone)
 	// WARNING: in.Image requires manual conversion: inconvertible types (*github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2.Image vs github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta1.Image)
 	// WARNING: in.OSDisk requires manual conversion: inconvertible types (*github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2.OSDisk vs github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta1.OSDisk)
@@ -1001,7 +1050,9 @@ func autoConvert_v1beta2_AzureStackHCIMachineSpec_To_v1beta1_AzureStackHCIMachin
 	out.GpuCount = in.GpuCount
 	out.AllocatePublicIP = in.AllocatePublicIP
 	out.AdditionalSSHKeys = *(*[]string)(unsafe.Pointer(&in.AdditionalSSHKeys))
-	out.NetworkInterfaces = *(*NetworkInterfaces)(unsafe.Pointer(&in.NetworkInterfaces))
+	if err := Convert_v1beta2_NetworkInterfaces_To_v1beta1_NetworkInterfaces(&in.NetworkInterfaces, &out.NetworkInterfaces, s); err != nil {
+		return err
+	}
 	out.AvailabilitySetName = in.AvailabilitySetName
 	out.PlacementGroupName = in.PlacementGroupName
 	return nil
@@ -1019,7 +1070,9 @@ func autoConvert_v1beta1_AzureStackHCIMachineSpec_To_v1beta2_AzureStackHCIMachin
 	out.GpuCount = in.GpuCount
 	out.AllocatePublicIP = in.AllocatePublicIP
 	out.AdditionalSSHKeys = *(*[]string)(unsafe.Pointer(&in.AdditionalSSHKeys))
-	out.NetworkInterfaces = *(*v1beta2.NetworkInterfaces)(unsafe.Pointer(&in.NetworkInterfaces))
+	if err := Convert_v1beta1_NetworkInterfaces_To_v1beta2_NetworkInterfaces(&in.NetworkInterfaces, &out.NetworkInterfaces, s); err != nil {
+		return err
+	}
 	out.AvailabilitySetName = in.AvailabilitySetName
 	out.PlacementGroupName = in.PlacementGroupName
 	return nil
@@ -1029,6 +1082,7 @@ func autoConvert_v1beta2_AzureStackHCIMachineStatus_To_v1beta1_AzureStackHCIMach
 	out.Ready = in.Ready
 	out.Addresses = *(*[]corev1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.VMState = (*VMState)(unsafe.Pointer(in.VMState))
+	// WARNING: in.FailureDomain requires manual conversion: does not exist in peer-type
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(corev1beta1.Conditions, len(*in))
@@ -1041,6 +1095,8 @@ func autoConvert_v1beta2_AzureStackHCIMachineStatus_To_v1beta1_AzureStackHCIMach
 		out.Conditions = nil
 	}
 	// WARNING: in.Initialization requires manual conversion: does not exist in peer-type
+	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	return nil
 }
 
@@ -1059,8 +1115,8 @@ func autoConvert_v1beta1_AzureStackHCIMachineStatus_To_v1beta2_AzureStackHCIMach
 	} else {
 		out.Conditions = nil
 	}
-	// WARNING: in.FailureReason requires manual conversion: does not exist in peer-type
-	// WARNING: in.FailureMessage requires manual conversion: does not exist in peer-type
+	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	return nil
 }
 
@@ -1069,14 +1125,10 @@ func autoConvert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMa
 	if err := Convert_v1beta2_AzureStackHCIMachineTemplateSpec_To_v1beta1_AzureStackHCIMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	// WARNING: in.Status requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMachineTemplate is an autogenerated conversion function.
-func Convert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMachineTemplate(in *v1beta2.AzureStackHCIMachineTemplate, out *AzureStackHCIMachineTemplate, s conversion.Scope) error {
-	return autoConvert_v1beta2_AzureStackHCIMachineTemplate_To_v1beta1_AzureStackHCIMachineTemplate(in, out, s)
-}
-
 func autoConvert_v1beta1_AzureStackHCIMachineTemplate_To_v1beta2_AzureStackHCIMachineTemplate(in *AzureStackHCIMachineTemplate, out *v1beta2.AzureStackHCIMachineTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta1_AzureStackHCIMachineTemplateSpec_To_v1beta2_AzureStackHCIMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1276,6 +1328,7 @@ func Convert_v1beta1_AzureStackHCIVirtualMachineList_To_v1beta2_AzureStackHCIVir
 
 func autoConvert_v1beta2_AzureStackHCIVirtualMachineSpec_To_v1beta1_AzureStackHCIVirtualMachineSpec(in *v1beta2.AzureStackHCIVirtualMachineSpec, out *AzureStackHCIVirtualMachineSpec, s conversion.Scope) error {
 	out.VMSize = in.VMSize
+	// WARNING: in.CustomSize requires manual conversion: does not exist in peer-type
 	// WARNING: in.AvailabilityZone requires manual conversion: inconvertible types (*github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2.AvailabilityZone vs github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta1.AvailabilityZone)
 	// WARNING: in.Image requires manual conversion: inconvertible types (*github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2.Image vs github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta1.Image)
 	// WARNING: in.OSDisk requires manual conversion: inconvertible types (*github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2.OSDisk vs github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta1.OSDisk)
@@ -1291,9 +1344,12 @@ func autoConvert_v1beta2_AzureStackHCIVirtualMachineSpec_To_v1beta1_AzureStackHC
 	out.SubnetName = in.SubnetName
 	out.BackendPoolNames = *(*[]string)(unsafe.Pointer(&in.BackendPoolNames))
 	out.AdditionalSSHKeys = *(*[]string)(unsafe.Pointer(&in.AdditionalSSHKeys))
-	out.NetworkInterfaces = *(*NetworkInterfaces)(unsafe.Pointer(&in.NetworkInterfaces))
+	if err := Convert_v1beta2_NetworkInterfaces_To_v1beta1_NetworkInterfaces(&in.NetworkInterfaces, &out.NetworkInterfaces, s); err != nil {
+		return err
+	}
 	out.AvailabilitySetName = in.AvailabilitySetName
 	out.PlacementGroupName = in.PlacementGroupName
+	// WARNING: in.PowerState requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1314,7 +1370,9 @@ func autoConvert_v1beta1_AzureStackHCIVirtualMachineSpec_To_v1beta2_AzureStackHC
 	out.SubnetName = in.SubnetName
 	out.BackendPoolNames = *(*[]string)(unsafe.Pointer(&in.BackendPoolNames))
 	out.AdditionalSSHKeys = *(*[]string)(unsafe.Pointer(&in.AdditionalSSHKeys))
-	out.NetworkInterfaces = *(*v1beta2.NetworkInterfaces)(unsafe.Pointer(&in.NetworkInterfaces))
+	if err := Convert_v1beta1_NetworkInterfaces_To_v1beta2_NetworkInterfaces(&in.NetworkInterfaces, &out.NetworkInterfaces, s); err != nil {
+		return err
+	}
 	out.AvailabilitySetName = in.AvailabilitySetName
 	out.PlacementGroupName = in.PlacementGroupName
 	return nil
@@ -1324,8 +1382,13 @@ func autoConvert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStack
 	out.Ready = in.Ready
 	out.Addresses = *(*[]corev1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.VMState = (*VMState)(unsafe.Pointer(in.VMState))
+	// WARNING: in.Host requires manual conversion: does not exist in peer-type
+	// WARNING: in.PowerState requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	// WARNING: in.CapacityRetries requires manual conversion: does not exist in peer-type
+	// WARNING: in.CapacityChecks requires manual conversion: does not exist in peer-type
+	// WARNING: in.NextCapacityCheckTime requires manual conversion: does not exist in peer-type
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(corev1beta1.Conditions, len(*in))
@@ -1340,11 +1403,6 @@ func autoConvert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStack
 	return nil
 }
 
-// Convert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStackHCIVirtualMachineStatus is an autogenerated conversion function.
-func Convert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStackHCIVirtualMachineStatus(in *v1beta2.AzureStackHCIVirtualMachineStatus, out *AzureStackHCIVirtualMachineStatus, s conversion.Scope) error {
-	return autoConvert_v1beta2_AzureStackHCIVirtualMachineStatus_To_v1beta1_AzureStackHCIVirtualMachineStatus(in, out, s)
-}
-
 func autoConvert_v1beta1_AzureStackHCIVirtualMachineStatus_To_v1beta2_AzureStackHCIVirtualMachineStatus(in *AzureStackHCIVirtualMachineStatus, out *v1beta2.AzureStackHCIVirtualMachineStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Addresses = *(*[]corev1.NodeAddress)(unsafe.Pointer(&in.Addresses))
@@ -1402,6 +1460,7 @@ func autoConvert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec(in *
 	out.Name = in.Name
 	out.Primary = in.Primary
 	out.Allocation = IPAllocationMethod(in.Allocation)
+	// WARNING: in.IPVersion requires manual conversion: does not exist in peer-type
 	out.IpAddress = in.IpAddress
 	out.PrefixLength = in.PrefixLength
 	out.SubnetId = in.SubnetId
@@ -1409,11 +1468,6 @@ func autoConvert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec(in *
 	return nil
 }
 
-// Convert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec is an autogenerated conversion function.
-func Convert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec(in *v1beta2.IpConfigurationSpec, out *IpConfigurationSpec, s conversion.Scope) error {
-	return autoConvert_v1beta2_IpConfigurationSpec_To_v1beta1_IpConfigurationSpec(in, out, s)
-}
-
 func autoConvert_v1beta1_IpConfigurationSpec_To_v1beta2_IpConfigurationSpec(in *IpConfigurationSpec, out *v1beta2.IpConfigurationSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Primary = in.Primary
@@ -1452,7 +1506,9 @@ func Convert_v1beta1_ManagedDisk_To_v1beta2_ManagedDisk(in *ManagedDisk, out *v1
 
 func autoConvert_v1beta2_NetworkInterfaceSpec_To_v1beta1_NetworkInterfaceSpec(in *v1beta2.NetworkInterfaceSpec, out *NetworkInterfaceSpec, s conversion.Scope) error {
 	out.Name = in.Name
-	out.IPConfigurations = *(*IpConfigurations)(unsafe.Pointer(&in.IPConfigurations))
+	if err := Convert_v1beta2_IpConfigurations_To_v1beta1_IpConfigurations(&in.IPConfigurations, &out.IPConfigurations, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1463,7 +1519,9 @@ func Convert_v1beta2_NetworkInterfaceSpec_To_v1beta1_NetworkInterfaceSpec(in *v1
 
 func autoConvert_v1beta1_NetworkInterfaceSpec_To_v1beta2_NetworkInterfaceSpec(in *NetworkInterfaceSpec, out *v1beta2.NetworkInterfaceSpec, s conversion.Scope) error {
 	out.Name = in.Name
-	out.IPConfigurations = *(*v1beta2.IpConfigurations)(unsafe.Pointer(&in.IPConfigurations))
+	if err := Convert_v1beta1_IpConfigurations_To_v1beta2_IpConfigurations(&in.IPConfigurations, &out.IPConfigurations, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1476,7 +1534,9 @@ func autoConvert_v1beta2_NetworkSpec_To_v1beta1_NetworkSpec(in *v1beta2.NetworkS
 	if err := Convert_v1beta2_VnetSpec_To_v1beta1_VnetSpec(&in.Vnet, &out.Vnet, s); err != nil {
 		return err
 	}
-	out.Subnets = *(*Subnets)(unsafe.Pointer(&in.Subnets))
+	if err := Convert_v1beta2_Subnets_To_v1beta1_Subnets(&in.Subnets, &out.Subnets, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1489,7 +1549,9 @@ func autoConvert_v1beta1_NetworkSpec_To_v1beta2_NetworkSpec(in *NetworkSpec, out
 	if err := Convert_v1beta1_VnetSpec_To_v1beta2_VnetSpec(&in.Vnet, &out.Vnet, s); err != nil {
 		return err
 	}
-	out.Subnets = *(*v1beta2.Subnets)(unsafe.Pointer(&in.Subnets))
+	if err := Convert_v1beta1_Subnets_To_v1beta2_Subnets(&in.Subnets, &out.Subnets, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1521,14 +1583,10 @@ func autoConvert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(in *v1beta2.SubnetSpec
 	out.Name = in.Name
 	out.VnetID = in.VnetID
 	out.CidrBlock = in.CidrBlock
+	// WARNING: in.CidrBlocks requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec is an autogenerated conversion function.
-func Convert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(in *v1beta2.SubnetSpec, out *SubnetSpec, s conversion.Scope) error {
-	return autoConvert_v1beta2_SubnetSpec_To_v1beta1_SubnetSpec(in, out, s)
-}
-
 func autoConvert_v1beta1_SubnetSpec_To_v1beta2_SubnetSpec(in *SubnetSpec, out *v1beta2.SubnetSpec, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Name = in.Name
@@ -1556,14 +1614,11 @@ func autoConvert_v1beta2_VM_To_v1beta1_VM(in *v1beta2.VM, out *VM, s conversion.
 	out.BootstrapData = in.BootstrapData
 	out.State = VMState(in.State)
 	out.Identity = VMIdentity(in.Identity)
+	// WARNING: in.Host requires manual conversion: does not exist in peer-type
+	// WARNING: in.PowerState requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta2_VM_To_v1beta1_VM is an autogenerated conversion function.
-func Convert_v1beta2_VM_To_v1beta1_VM(in *v1beta2.VM, out *VM, s conversion.Scope) error {
-	return autoConvert_v1beta2_VM_To_v1beta1_VM(in, out, s)
-}
-
 func autoConvert_v1beta1_VM_To_v1beta2_VM(in *VM, out *v1beta2.VM, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Name = in.Name
@@ -1590,15 +1645,11 @@ func autoConvert_v1beta2_VnetSpec_To_v1beta1_VnetSpec(in *v1beta2.VnetSpec, out
 	out.ID = in.ID
 	out.Name = in.Name
 	out.CidrBlock = in.CidrBlock
+	// WARNING: in.CidrBlocks requires manual conversion: does not exist in peer-type
 	out.Group = in.Group
 	return nil
 }
 
-// Convert_v1beta2_VnetSpec_To_v1beta1_VnetSpec is an autogenerated conversion function.
-func Convert_v1beta2_VnetSpec_To_v1beta1_VnetSpec(in *v1beta2.VnetSpec, out *VnetSpec, s conversion.Scope) error {
-	return autoConvert_v1beta2_VnetSpec_To_v1beta1_VnetSpec(in, out, s)
-}
-
 func autoConvert_v1beta1_VnetSpec_To_v1beta2_VnetSpec(in *VnetSpec, out *v1beta2.VnetSpec, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Name = in.Name
@@ -45,10 +45,50 @@ type AzureStackHCIClusterSpec struct {
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty,omitzero"`
 
 	// Version indicates the desired Kubernetes version of the cluster.
-	Version *string `json:"version"`
+	// When unset, the version of the Cluster topology is used.
+	// +optional
+	Version *string `json:"version,omitempty"`
 
 	// Management is true when the cluster is a Management Cluster.
 	Management bool `json:"management,omitempty"`
+
+	// FailureDomains declares the failure domains machines can be spread across. Each failure domain
+	// maps to an availability set or placement group, Machines select one with spec.failureDomain.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	FailureDomains []FailureDomainSpec `json:"failureDomains,omitempty"`
+
+	// AvailabilitySets are created with the cluster and deleted with it. An availability set removed from
+	// the list is deleted once no virtual machine is in it.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	AvailabilitySets []AvailabilitySetSpec `json:"availabilitySets,omitempty"`
+
+	// PlacementGroups are created with the cluster and deleted with it. A placement group removed from
+	// the list is deleted once no virtual machine is in it.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	PlacementGroups []PlacementGroupSpec `json:"placementGroups,omitempty"`
+
+	// StorageContainers are the storage containers virtual machines of the cluster are placed in, typically one
+	// per cluster shared volume. Storage containers with a path are created with the cluster and deleted with it.
+	// A storage container removed from the list is deleted once no virtual machine is in it.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	StorageContainers []StorageContainerSpec `json:"storageContainers,omitempty"`
+
+	// StorageContainerPolicy selects the storage container of virtual machines that don't request one.
+	// +kubebuilder:default=Explicit
+	// +optional
+	StorageContainerPolicy StorageContainerPolicy `json:"storageContainerPolicy,omitempty"`
 }
 
 // AzureStackHCIClusterStatus defines the observed state of AzureStackHCICluster
@@ -64,6 +104,44 @@ type AzureStackHCIClusterStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// FailureDomains is the list of failure domains published to the Cluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	FailureDomains []clusterv1.FailureDomain `json:"failureDomains,omitempty"`
+
+	// AvailabilitySets are the availability sets created with the cluster and the number of virtual machines in them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	AvailabilitySets []PlacementStatus `json:"availabilitySets,omitempty"`
+
+	// PlacementGroups are the placement groups created with the cluster and the number of virtual machines in them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	PlacementGroups []PlacementStatus `json:"placementGroups,omitempty"`
+
+	// StorageContainers are the storage containers of the cluster and the number of virtual machines in them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=100
+	StorageContainers []PlacementStatus `json:"storageContainers,omitempty"`
+
+	// Capacity summarizes the resources available on the hosts and how many more virtual machines of the
+	// VM sizes used by the cluster fit on them.
+	// +optional
+	Capacity *ClusterCapacity `json:"capacity,omitempty"`
+
+	// CapacityRetries is the number of consecutive reconciliations that failed for lack of capacity.
+	// The delay before the next one doubles with each of them.
+	// +optional
+	CapacityRetries int32 `json:"capacityRetries,omitempty"`
+
 	// Initialization provides observations of the Cluster initialization process.
 	// NOTE: fields in this struct are part of the Cluster API contract and are used to orchestrate initial Cluster provisioning.
 	// The value of those fields is never updated after provisioning is completed.
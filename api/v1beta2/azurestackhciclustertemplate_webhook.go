@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/util/topology"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func (r *AzureStackHCIClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, r).
+		WithValidator(&azureStackHCIClusterTemplateWebhook{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta2-azurestackhciclustertemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azurestackhciclustertemplates,versions=v1beta2,name=validation.azurestackhciclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// azureStackHCIClusterTemplateWebhook rejects changes to the template spec of an AzureStackHCIClusterTemplate.
+// Clusters are moved to a new template by updating their ClusterClass.
+type azureStackHCIClusterTemplateWebhook struct{}
+
+var _ admission.Validator[*AzureStackHCIClusterTemplate] = &azureStackHCIClusterTemplateWebhook{}
+
+// ValidateCreate implements admission.Validator.
+func (*azureStackHCIClusterTemplateWebhook) ValidateCreate(_ context.Context, _ *AzureStackHCIClusterTemplate) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate implements admission.Validator.
+func (*azureStackHCIClusterTemplateWebhook) ValidateUpdate(ctx context.Context, oldTemplate, newTemplate *AzureStackHCIClusterTemplate) (admission.Warnings, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an admission.Request inside context: %v", err))
+	}
+
+	// the topology controller dry-runs the desired template against the current one to detect changes
+	if topology.IsDryRunRequest(req, newTemplate) {
+		return nil, nil
+	}
+
+	if !reflect.DeepEqual(oldTemplate.Spec.Template.Spec, newTemplate.Spec.Template.Spec) {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureStackHCIClusterTemplate").GroupKind(), newTemplate.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "template", "spec"), "AzureStackHCIClusterTemplate spec.template.spec is immutable, create a new template instead"),
+		})
+	}
+	return nil, nil
+}
+
+// ValidateDelete implements admission.Validator.
+func (*azureStackHCIClusterTemplateWebhook) ValidateDelete(_ context.Context, _ *AzureStackHCIClusterTemplate) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ImageFinalizer allows ReconcileAzureStackHCIImage to clean up the gallery image before
+	// removing it from the apiserver.
+	ImageFinalizer = "azurestackhciimage.infrastructure.cluster.x-k8s.io"
+)
+
+// AzureStackHCIImageSpec defines the desired state of AzureStackHCIImage
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type AzureStackHCIImageSpec struct {
+	// GalleryImageName is the name of the gallery image the source is imported as, machines reference
+	// the image with it. Defaults to the name of the AzureStackHCIImage.
+	// +optional
+	GalleryImageName string `json:"galleryImageName,omitempty"`
+
+	// Location is the location of the image gallery.
+	// +kubebuilder:validation:MinLength=1
+	Location string `json:"location"`
+
+	// Source is the VHDX the gallery image is imported from.
+	Source ImageSource `json:"source"`
+
+	// StorageContainer is the storage container the image is imported into.
+	// +optional
+	StorageContainer string `json:"storageContainer,omitempty"`
+
+	// OSType is the operating system of the image.
+	// +kubebuilder:default=Linux
+	// +optional
+	OSType OSType `json:"osType,omitempty"`
+}
+
+// ImageSource describes where a VHDX is imported from. Exactly one of url or path must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.url) != has(self.path)",message="exactly one of url or path must be set"
+type ImageSource struct {
+	// URL is the http(s) URL the VHDX is downloaded from, for example a SAS URL.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Path is the path of the VHDX on the cloud agent.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// AzureStackHCIImageStatus defines the observed state of AzureStackHCIImage
+type AzureStackHCIImageStatus struct {
+	// Ready is true when the gallery image was imported and can be used by machines.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// State is the import state of the gallery image.
+	// +optional
+	State ImageState `json:"state,omitempty"`
+
+	// DownloadProgress is the percentage of the VHDX downloaded while the image is imported.
+	// +optional
+	DownloadProgress int64 `json:"downloadProgress,omitempty"`
+
+	// Conditions defines current service state of the AzureStackHCIImage.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=azurestackhciimages,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Gallery Image",type="string",JSONPath=".spec.galleryImageName",description="Name of the gallery image"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="Import state of the gallery image"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready",description="Gallery image is ready"
+
+// AzureStackHCIImage is the Schema for the azurestackhciimages API.
+// It imports a VHDX into the image gallery so that machines can be created from it.
+type AzureStackHCIImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AzureStackHCIImageSpec   `json:"spec,omitempty"`
+	Status AzureStackHCIImageStatus `json:"status,omitempty"`
+}
+
+// GalleryImageName returns the name of the gallery image the source is imported as.
+func (i *AzureStackHCIImage) GalleryImageName() string {
+	if i.Spec.GalleryImageName != "" {
+		return i.Spec.GalleryImageName
+	}
+	return i.Name
+}
+
+// GetConditions returns the list of conditions for the AzureStackHCIImage.
+func (i *AzureStackHCIImage) GetConditions() []metav1.Condition {
+	return i.Status.Conditions
+}
+
+// SetConditions sets the conditions for the AzureStackHCIImage.
+func (i *AzureStackHCIImage) SetConditions(conditions []metav1.Condition) {
+	i.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// AzureStackHCIImageList contains a list of AzureStackHCIImage
+type AzureStackHCIImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureStackHCIImage `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &AzureStackHCIImage{}, &AzureStackHCIImageList{})
+}
@@ -19,6 +19,7 @@ package v1beta2
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
@@ -33,6 +34,12 @@ type AzureStackHCILoadBalancerSpec struct {
 	// +optional
 	Image *Image `json:"image,omitempty"`
 
+	// Version is the Kubernetes version of the default image the replicas run. Defaults to the version of
+	// the AzureStackHCICluster. Changing it rolls the replicas to the new image independently of the
+	// cluster version. Ignored when a custom image is set in Image.
+	// +optional
+	Version *string `json:"version,omitempty"`
+
 	VMSize string `json:"vmSize"`
 
 	// +optional
@@ -43,6 +50,342 @@ type AzureStackHCILoadBalancerSpec struct {
 	// +optional
 	// +kubebuilder:default=1
 	Replicas *int32 `json:"replicas,omitempty"`
+
+	// VipPool references the vip pool the frontend address is allocated from. Only the addresses used by
+	// load balancers in the cluster resource group are skipped, a vip pool must not be shared with other resource groups.
+	// +optional
+	VipPool *VipPoolSpec `json:"vipPool,omitempty"`
+
+	// StaticVIP requests a specific frontend address for the load balancer.
+	// If a VipPool is referenced the address must be within the pool.
+	// +optional
+	StaticVIP string `json:"staticVIP,omitempty"`
+
+	// Rules are the load balancing rules programmed on the load balancer. A rule on the
+	// api server port replaces the default api server rule.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Rules []LoadBalancingRule `json:"rules,omitempty"`
+
+	// Probes are the health probes referenced by the load balancing rules.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Probes []LoadBalancerProbe `json:"probes,omitempty"`
+
+	// Listeners request additional frontends for workload traffic, for example an ingress on ports 80 and 443.
+	// Each listener is served by its own load balancer that forwards to the backend pool all cluster nodes join.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Listeners []LoadBalancerListener `json:"listeners,omitempty"`
+
+	// Strategy describes how outdated replicas are replaced by new ones.
+	// +optional
+	Strategy *LoadBalancerStrategy `json:"strategy,omitempty"`
+
+	// HealthCheck enables the replacement of unhealthy replicas. Replicas are not remediated when unset.
+	// +optional
+	HealthCheck *LoadBalancerHealthCheck `json:"healthCheck,omitempty"`
+
+	// Placement spreads the replicas across hosts so the frontend survives a host failure.
+	// Replicas are placed without constraints when unset.
+	// +optional
+	Placement *LoadBalancerPlacement `json:"placement,omitempty"`
+
+	// Autoscaling adjusts Replicas to the connections served by the replicas. Replicas is not
+	// overwritten from the AzureStackHCICluster while autoscaling is enabled.
+	// +optional
+	Autoscaling *LoadBalancerAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// LoadBalancerAutoscaling describes how Replicas follows the connections served by the replicas.
+// +kubebuilder:validation:XValidation:rule="self.maxReplicas >= self.minReplicas",message="maxReplicas must be greater than or equal to minReplicas"
+type LoadBalancerAutoscaling struct {
+	// MinReplicas is the lower limit of Replicas.
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 `json:"minReplicas"`
+
+	// MaxReplicas is the upper limit of Replicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetConnectionsPerReplica is the average number of active connections per replica to scale to.
+	// +kubebuilder:validation:Minimum=1
+	TargetConnectionsPerReplica int64 `json:"targetConnectionsPerReplica"`
+
+	// ScaleUpStabilizationWindow is how long a scale up has to be recommended before it is applied. Defaults to 1m.
+	// +optional
+	ScaleUpStabilizationWindow *metav1.Duration `json:"scaleUpStabilizationWindow,omitempty"`
+
+	// ScaleDownStabilizationWindow is how long a scale down has to be recommended before it is applied. Defaults to 5m.
+	// +optional
+	ScaleDownStabilizationWindow *metav1.Duration `json:"scaleDownStabilizationWindow,omitempty"`
+}
+
+// LoadBalancerReplicaMetrics reports the traffic served by a replica.
+type LoadBalancerReplicaMetrics struct {
+	// Name of the replica.
+	Name string `json:"name"`
+
+	// ActiveConnections is the number of connections open through the replica.
+	// +optional
+	ActiveConnections int64 `json:"activeConnections,omitempty"`
+
+	// BytesPerSecond is the throughput of the replica.
+	// +optional
+	BytesPerSecond int64 `json:"bytesPerSecond,omitempty"`
+}
+
+// LoadBalancerAutoscalingStatus reports the metrics and recommendation of the autoscaler.
+type LoadBalancerAutoscalingStatus struct {
+	// Replicas are the metrics of the replicas.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Replicas []LoadBalancerReplicaMetrics `json:"replicas,omitempty"`
+
+	// ActiveConnections is the number of connections open through all replicas.
+	// +optional
+	ActiveConnections int64 `json:"activeConnections,omitempty"`
+
+	// RecommendedReplicas is the number of replicas recommended by the autoscaler.
+	// +optional
+	RecommendedReplicas int32 `json:"recommendedReplicas,omitempty"`
+
+	// RecommendedSince is the time the recommendation started to differ from Replicas.
+	// +optional
+	RecommendedSince *metav1.Time `json:"recommendedSince,omitempty"`
+
+	// LastScaleTime is the time Replicas was last changed by the autoscaler.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// LastMetricsTime is the time the metrics were last gathered.
+	// +optional
+	LastMetricsTime *metav1.Time `json:"lastMetricsTime,omitempty"`
+}
+
+// LoadBalancerPlacement describes how replicas are spread across hosts. The replicas join the referenced
+// availability set or placement group. When neither is referenced, the provider creates an availability set
+// for the load balancer and deletes it with the load balancer.
+type LoadBalancerPlacement struct {
+	// AvailabilitySetName is the name of an existing availability set the replicas join.
+	// +optional
+	AvailabilitySetName string `json:"availabilitySetName,omitempty"`
+
+	// PlacementGroupName is the name of an existing placement group the replicas join.
+	// +optional
+	PlacementGroupName string `json:"placementGroupName,omitempty"`
+
+	// PlatformFaultDomainCount is the number of fault domains of the availability set created by the provider. Defaults to 2.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	PlatformFaultDomainCount *int32 `json:"platformFaultDomainCount,omitempty"`
+}
+
+// IsProviderManaged returns true if the provider creates the availability set of the replicas.
+func (p *LoadBalancerPlacement) IsProviderManaged() bool {
+	return p != nil && p.AvailabilitySetName == "" && p.PlacementGroupName == ""
+}
+
+// LoadBalancerHealthCheck configures the remediation of unhealthy replicas. A replica is unhealthy when its
+// virtual machine failed, or when it is not running or not connected to the load balancer service for
+// longer than UnhealthyTimeout. Unhealthy replicas are deleted and recreated.
+type LoadBalancerHealthCheck struct {
+	// UnhealthyTimeout is how long a replica can be not running or not connected to the load balancer
+	// service before it is remediated. Defaults to 10m.
+	// +optional
+	UnhealthyTimeout *metav1.Duration `json:"unhealthyTimeout,omitempty"`
+
+	// MaxUnhealthy is the budget of unhealthy replicas that are remediated. No replica is remediated while
+	// more replicas are unhealthy, as the cause is likely outside of the replicas.
+	// Value can be an absolute number or a percentage of desired replicas, rounded down. Defaults to 100%.
+	// +optional
+	MaxUnhealthy *intstr.IntOrString `json:"maxUnhealthy,omitempty"`
+}
+
+// LoadBalancerStrategy describes how outdated replicas are replaced by new ones.
+type LoadBalancerStrategy struct {
+	// RollingUpdate configures the rolling replacement of outdated replicas.
+	// +optional
+	RollingUpdate *LoadBalancerRollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// LoadBalancerRollingUpdate configures the rolling replacement of outdated replicas. Replicas are
+// replaced one at a time and a new replica must be connected to the load balancer service before
+// an outdated replica is removed.
+type LoadBalancerRollingUpdate struct {
+	// MaxSurge is the maximum number of replicas that can be created above the desired number of replicas.
+	// Value can be an absolute number or a percentage of desired replicas, rounded up. Defaults to 1.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxUnavailable is the maximum number of desired replicas that can be unavailable during the update.
+	// Value can be an absolute number or a percentage of desired replicas, rounded down. Defaults to 0.
+	// MaxSurge and MaxUnavailable cannot both be 0, in which case MaxSurge is treated as 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// ProgressDeadline is how long an update can run while updated replicas are not ready before the
+	// replicas are rolled back to the previous image. Defaults to 30m.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+}
+
+// LoadBalancerUpgradeStatus reports the progress of a rolling update of the replicas.
+type LoadBalancerUpgradeStatus struct {
+	// OSVersion is the version the replicas are updated to.
+	// +optional
+	OSVersion string `json:"osVersion,omitempty"`
+
+	// UpdatedReplicas is the number of replicas running OSVersion.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// OutdatedReplicas is the number of replicas that still have to be replaced.
+	// +optional
+	OutdatedReplicas int32 `json:"outdatedReplicas,omitempty"`
+
+	// StartTime is the time the update started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time all replicas were updated.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// PreviousOSVersion is the version the replicas ran before the update.
+	// +optional
+	PreviousOSVersion string `json:"previousOSVersion,omitempty"`
+
+	// PreviousImage is the image the replicas ran before the update.
+	// +optional
+	PreviousImage *Image `json:"previousImage,omitempty"`
+
+	// FailedOSVersion is set when the replicas were rolled back because the updated replicas did not become
+	// ready within the progress deadline. The replicas run PreviousImage until a different version is requested.
+	// +optional
+	FailedOSVersion string `json:"failedOSVersion,omitempty"`
+}
+
+// LoadBalancerListener describes an additional frontend of the load balancer for workload traffic.
+type LoadBalancerListener struct {
+	// Name of the listener.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// StaticVIP requests a specific frontend address for the listener.
+	// If a VipPool is referenced the address must be within the pool.
+	// +optional
+	StaticVIP string `json:"staticVIP,omitempty"`
+
+	// Rules are the load balancing rules of the listener.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Rules []LoadBalancingRule `json:"rules"`
+
+	// Probes are the health probes referenced by the listener rules.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Probes []LoadBalancerProbe `json:"probes,omitempty"`
+}
+
+// LoadBalancerListenerStatus reports the frontend of a listener.
+type LoadBalancerListenerStatus struct {
+	// Name of the listener.
+	Name string `json:"name"`
+
+	// Address is the frontend IP address of the listener.
+	// +optional
+	Address string `json:"address,omitempty"`
+}
+
+// LoadBalancerProtocol is the transport protocol of a load balancing rule.
+// +kubebuilder:validation:Enum=Tcp;Udp;All
+type LoadBalancerProtocol string
+
+const (
+	LoadBalancerProtocolTCP LoadBalancerProtocol = "Tcp"
+	LoadBalancerProtocolUDP LoadBalancerProtocol = "Udp"
+	LoadBalancerProtocolAll LoadBalancerProtocol = "All"
+)
+
+// LoadBalancingRule describes how frontend traffic is forwarded to the backend pool.
+type LoadBalancingRule struct {
+	// Name of the rule.
+	Name string `json:"name"`
+
+	// Protocol of the rule. Defaults to Tcp.
+	// +optional
+	// +kubebuilder:default=Tcp
+	Protocol LoadBalancerProtocol `json:"protocol,omitempty"`
+
+	// FrontendPort is the port on the load balancer frontend address.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	FrontendPort int32 `json:"frontendPort"`
+
+	// BackendPort is the port on the backend machines.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	BackendPort int32 `json:"backendPort"`
+
+	// IdleTimeoutInMinutes is the timeout for idle tcp connections.
+	// +optional
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=30
+	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+
+	// EnableFloatingIP enables direct server return for the rule.
+	// +optional
+	EnableFloatingIP bool `json:"enableFloatingIP,omitempty"`
+
+	// ProbeName is the name of the health probe used by the rule.
+	// +optional
+	ProbeName string `json:"probeName,omitempty"`
+}
+
+// LoadBalancerProbeProtocol is the protocol of a health probe.
+// +kubebuilder:validation:Enum=Tcp;Http;Https
+type LoadBalancerProbeProtocol string
+
+const (
+	LoadBalancerProbeProtocolTCP   LoadBalancerProbeProtocol = "Tcp"
+	LoadBalancerProbeProtocolHTTP  LoadBalancerProbeProtocol = "Http"
+	LoadBalancerProbeProtocolHTTPS LoadBalancerProbeProtocol = "Https"
+)
+
+// LoadBalancerProbe describes a health probe of the backend machines.
+type LoadBalancerProbe struct {
+	// Name of the probe.
+	Name string `json:"name"`
+
+	// Protocol of the probe. Http and Https probes require a 200 response from RequestPath.
+	Protocol LoadBalancerProbeProtocol `json:"protocol"`
+
+	// Port probed on the backend machines.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// RequestPath is the path requested by Http and Https probes.
+	// +optional
+	RequestPath string `json:"requestPath,omitempty"`
+
+	// IntervalInSeconds is the interval between probes. Defaults to 5.
+	// +optional
+	// +kubebuilder:validation:Minimum=5
+	IntervalInSeconds *int32 `json:"intervalInSeconds,omitempty"`
+
+	// NumberOfProbes is the number of failed probes before a backend is taken out of rotation. Defaults to 2.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	NumberOfProbes *int32 `json:"numberOfProbes,omitempty"`
 }
 
 type AzureStackHCILoadBalancerStatus struct {
@@ -65,9 +408,40 @@ type AzureStackHCILoadBalancerStatus struct {
 	// +optional
 	Address string `json:"address,omitempty"`
 
+	// IPv6Address is the IPv6 address of the load balancer when the cluster network is dual-stack.
+	// +optional
+	IPv6Address string `json:"ipv6Address,omitempty"`
+
 	// Port is the port of the azureStackHCIloadbalancers frontend.
 	Port int32 `json:"port,omitempty"`
 
+	// Listeners reports the frontends of the workload listeners.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Listeners []LoadBalancerListenerStatus `json:"listeners,omitempty"`
+
+	// UnhealthyReplicas is the number of replicas considered unhealthy by the health check.
+	// +optional
+	UnhealthyReplicas int32 `json:"unhealthyReplicas,omitempty"`
+
+	// RemediationsAllowed is the number of further unhealthy replicas that can be remediated.
+	// +optional
+	RemediationsAllowed int32 `json:"remediationsAllowed,omitempty"`
+
+	// Autoscaling reports the metrics and recommendation of the autoscaler.
+	// +optional
+	Autoscaling *LoadBalancerAutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// Upgrade reports the progress of the last rolling update of the replicas.
+	// +optional
+	Upgrade *LoadBalancerUpgradeStatus `json:"upgrade,omitempty"`
+
+	// CapacityRetries is the number of consecutive reconciliations that failed for lack of capacity.
+	// The delay before the next one doubles with each of them.
+	// +optional
+	CapacityRetries int32 `json:"capacityRetries,omitempty"`
+
 	// Phase represents the current phase of loadbalancer actuation.
 	// E.g. Pending, Running, Terminating, Failed etc.
 	// +optional
@@ -20,6 +20,7 @@ package v1beta2
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/errors"
 )
 
 const (
@@ -36,6 +37,12 @@ type AzureStackHCIMachineSpec struct {
 
 	VMSize string `json:"vmSize"`
 
+	// CustomSize defines the processors and memory of the virtual machine when VMSize is Custom.
+	// +optional
+	CustomSize *VMCustomSize `json:"customSize,omitempty"`
+
+	// AvailabilityZone is ignored, Azure availability zones don't exist on Azure Stack HCI.
+	// Deprecated: use the failure domains of the AzureStackHCICluster instead.
 	// +optional
 	AvailabilityZone *AvailabilityZone `json:"availabilityZone,omitempty"`
 
@@ -83,6 +90,10 @@ type AzureStackHCIMachineStatus struct {
 	// +optional
 	VMState *VMState `json:"vmState,omitempty"`
 
+	// FailureDomain is the failure domain the virtual machine was placed in.
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+
 	// Conditions defines current service state of the AzureStackHCIMachine.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -90,6 +101,19 @@ type AzureStackHCIMachineStatus struct {
 	// Initialization provides observations of the AzureStackHCIMachine initialization process.
 	// +optional
 	Initialization *AzureStackHCIMachineInitializationStatus `json:"initialization,omitempty,omitzero"`
+
+	// FailureReason will be set in the event that there is a terminal problem provisioning the virtual machine,
+	// such as a missing image or an invalid vm size, and will contain a succinct value suitable for machine
+	// interpretation. Transient errors are not reported here. The TerminalFailure condition carries the same failure.
+	// NOTE: this field is part of the deprecated v1beta1 contract, it is surfaced in the Machine status.deprecated.v1beta1.
+	// +optional
+	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem provisioning the virtual machine
+	// and will contain a more verbose string suitable for logging and human consumption.
+	// NOTE: this field is part of the deprecated v1beta1 contract, it is surfaced in the Machine status.deprecated.v1beta1.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
 }
 
 // AzureStackHCIMachineInitializationStatus provides observations of the AzureStackHCIMachine initialization process.
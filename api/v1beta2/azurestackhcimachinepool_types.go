@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// MachinePoolFinalizer allows ReconcileAzureStackHCIMachinePool to clean up the virtual machines of the pool before
+	// removing it from the apiserver.
+	MachinePoolFinalizer = "azurestackhcimachinepool.infrastructure.cluster.x-k8s.io"
+)
+
+// AzureStackHCIMachinePoolSpec defines the desired state of AzureStackHCIMachinePool
+type AzureStackHCIMachinePoolSpec struct {
+	// ProviderIDList are the identification IDs of the virtual machines of the pool.
+	// NOTE: this field is part of the Cluster API contract, the MachinePool matches it with the provider IDs of the nodes.
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// Template describes the virtual machines of the pool. Changes to the template, the Kubernetes version
+	// or the bootstrap data secret of the MachinePool replace the virtual machines with a rolling update.
+	Template AzureStackHCIMachinePoolMachineTemplate `json:"template"`
+
+	// Strategy describes how outdated virtual machines are replaced by new ones.
+	// +optional
+	Strategy *MachinePoolStrategy `json:"strategy,omitempty"`
+}
+
+// AzureStackHCIMachinePoolMachineTemplate describes the virtual machines of a pool.
+type AzureStackHCIMachinePoolMachineTemplate struct {
+	VMSize string `json:"vmSize"`
+
+	// CustomSize defines the processors and memory of the virtual machine when VMSize is Custom.
+	// +optional
+	CustomSize *VMCustomSize `json:"customSize,omitempty"`
+
+	// +optional
+	Image *Image `json:"image,omitempty"`
+
+	// +optional
+	OSDisk *OSDisk `json:"osDisk,omitempty"`
+
+	SSHPublicKey string `json:"sshPublicKey"`
+
+	// +optional
+	AdditionalSSHKeys []string `json:"additionalSSHKeys,omitempty"`
+
+	// +optional
+	StorageContainer string `json:"storageContainer,omitempty"`
+
+	// +optional
+	GpuCount int32 `json:"gpuCount,omitempty"`
+
+	// +optional
+	NetworkInterfaces NetworkInterfaces `json:"networkInterfaces,omitempty"`
+
+	// +optional
+	AvailabilitySetName string `json:"availabilitySetName,omitempty"`
+
+	// +optional
+	PlacementGroupName string `json:"placementGroupName,omitempty"`
+}
+
+// MachinePoolStrategy describes how outdated virtual machines of a pool are replaced by new ones.
+type MachinePoolStrategy struct {
+	// RollingUpdate configures the rolling replacement of outdated virtual machines.
+	// +optional
+	RollingUpdate *MachinePoolRollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// MachinePoolRollingUpdate configures the rolling replacement of outdated virtual machines. An outdated
+// virtual machine is only removed while the remaining ready virtual machines satisfy maxUnavailable.
+type MachinePoolRollingUpdate struct {
+	// MaxSurge is the maximum number of virtual machines that can be created above the desired number of replicas.
+	// Value can be an absolute number or a percentage of desired replicas, rounded up. Defaults to 1.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxUnavailable is the maximum number of desired replicas that can be unavailable during the update.
+	// Value can be an absolute number or a percentage of desired replicas, rounded down. Defaults to 0.
+	// MaxSurge and MaxUnavailable cannot both be 0, in which case MaxSurge is treated as 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AzureStackHCIMachinePoolStatus defines the observed state of AzureStackHCIMachinePool
+type AzureStackHCIMachinePoolStatus struct {
+	// Ready is true when the virtual machines of the pool were provisioned for the first time.
+	// NOTE: this field is part of the Cluster API contract.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the number of virtual machines of the pool.
+	// NOTE: this field is part of the Cluster API contract.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of virtual machines of the pool that are running.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// UpToDateReplicas is the number of virtual machines of the pool created from the current template.
+	// +optional
+	UpToDateReplicas int32 `json:"upToDateReplicas"`
+
+	// Instances reports the virtual machines of the pool.
+	// +optional
+	Instances []AzureStackHCIMachinePoolInstance `json:"instances,omitempty"`
+
+	// Conditions defines current service state of the AzureStackHCIMachinePool.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Initialization provides observations of the AzureStackHCIMachinePool initialization process.
+	// +optional
+	Initialization *AzureStackHCIMachinePoolInitializationStatus `json:"initialization,omitempty,omitzero"`
+}
+
+// AzureStackHCIMachinePoolInstance reports a virtual machine of a pool.
+type AzureStackHCIMachinePoolInstance struct {
+	// Name is the name of the AzureStackHCIVirtualMachine.
+	Name string `json:"name"`
+
+	// ProviderID is the provider ID of the virtual machine, set once the virtual machine is running.
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// VMState is the provisioning state of the virtual machine.
+	// +optional
+	VMState *VMState `json:"vmState,omitempty"`
+
+	// UpToDate is true when the virtual machine was created from the current template.
+	// +optional
+	UpToDate bool `json:"upToDate"`
+}
+
+// AzureStackHCIMachinePoolInitializationStatus provides observations of the AzureStackHCIMachinePool initialization process.
+// +kubebuilder:validation:MinProperties=1
+type AzureStackHCIMachinePoolInitializationStatus struct {
+	// Provisioned is true when the infrastructure provider reports that the MachinePool's infrastructure is fully provisioned.
+	// NOTE: this field is part of the Cluster API contract, and it is used to orchestrate initial MachinePool provisioning.
+	// +optional
+	Provisioned *bool `json:"provisioned,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=azurestackhcimachinepools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Number of virtual machines"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas",description="Number of running virtual machines"
+// +kubebuilder:printcolumn:name="Up-to-date",type="integer",JSONPath=".status.upToDateReplicas",description="Number of virtual machines created from the current template"
+
+// AzureStackHCIMachinePool is the Schema for the azurestackhcimachinepools API.
+// It is the infrastructure of a Cluster API MachinePool and owns its AzureStackHCIVirtualMachines.
+type AzureStackHCIMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AzureStackHCIMachinePoolSpec   `json:"spec,omitempty"`
+	Status AzureStackHCIMachinePoolStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the list of conditions for the AzureStackHCIMachinePool.
+func (m *AzureStackHCIMachinePool) GetConditions() []metav1.Condition {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions for the AzureStackHCIMachinePool.
+func (m *AzureStackHCIMachinePool) SetConditions(conditions []metav1.Condition) {
+	m.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// AzureStackHCIMachinePoolList contains a list of AzureStackHCIMachinePool
+type AzureStackHCIMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureStackHCIMachinePool `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &AzureStackHCIMachinePool{}, &AzureStackHCIMachinePoolList{})
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package v1beta2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,16 +27,67 @@ type AzureStackHCIMachineTemplateSpec struct {
 	Template AzureStackHCIMachineTemplateResource `json:"template"`
 }
 
+// AzureStackHCIMachineTemplateStatus defines the observed state of AzureStackHCIMachineTemplate
+type AzureStackHCIMachineTemplateStatus struct {
+	// Capacity defines the resource capacity of the machines created from this template.
+	// It is resolved from the VM size and used by the cluster autoscaler to scale from zero.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// NodeInfo describes the architecture and operating system of the nodes created from this template.
+	// +optional
+	NodeInfo *NodeInfo `json:"nodeInfo,omitempty"`
+
+	// Conditions defines current service state of the AzureStackHCIMachineTemplate.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Architecture is the CPU architecture of a node.
+// +kubebuilder:validation:Enum=amd64;arm64
+type Architecture string
+
+const (
+	// ArchitectureAmd64 is the amd64 architecture.
+	ArchitectureAmd64 Architecture = "amd64"
+	// ArchitectureArm64 is the arm64 architecture.
+	ArchitectureArm64 Architecture = "arm64"
+)
+
+// NodeInfo contains information about the architecture and operating system of a node.
+// +kubebuilder:validation:MinProperties=1
+type NodeInfo struct {
+	// Architecture is the CPU architecture of the node.
+	// +optional
+	Architecture Architecture `json:"architecture,omitempty"`
+
+	// OperatingSystem is the operating system of the node, e.g. linux or windows.
+	// +optional
+	OperatingSystem string `json:"operatingSystem,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +kubebuilder:resource:path=azurestackhcimachinetemplates,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
 
 // AzureStackHCIMachineTemplate is the Schema for the azurestackhcimachinetemplates API
 type AzureStackHCIMachineTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec AzureStackHCIMachineTemplateSpec `json:"spec,omitempty"`
+	Spec   AzureStackHCIMachineTemplateSpec   `json:"spec,omitempty"`
+	Status AzureStackHCIMachineTemplateStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the list of conditions for an AzureStackHCIMachineTemplate API object.
+func (m *AzureStackHCIMachineTemplate) GetConditions() []metav1.Condition {
+	return m.Status.Conditions
+}
+
+// SetConditions will set the given conditions on an AzureStackHCIMachineTemplate object.
+func (m *AzureStackHCIMachineTemplate) SetConditions(conditions []metav1.Condition) {
+	m.Status.Conditions = conditions
 }
 
 // +kubebuilder:object:root=true
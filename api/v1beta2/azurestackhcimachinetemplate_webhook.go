@@ -18,10 +18,57 @@ limitations under the License.
 package v1beta2
 
 import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func (r *AzureStackHCIMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr, r).
+		WithValidator(&azureStackHCIMachineTemplateWebhook{}).
 		Complete()
 }
+
+// +kubebuilder:webhook:verbs=update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta2-azurestackhcimachinetemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azurestackhcimachinetemplates,versions=v1beta2,name=validation.azurestackhcimachinetemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// azureStackHCIMachineTemplateWebhook rejects changes to the template spec of an AzureStackHCIMachineTemplate.
+// Machines are rolled out by referencing a new template instead.
+type azureStackHCIMachineTemplateWebhook struct{}
+
+var _ admission.Validator[*AzureStackHCIMachineTemplate] = &azureStackHCIMachineTemplateWebhook{}
+
+// ValidateCreate implements admission.Validator.
+func (*azureStackHCIMachineTemplateWebhook) ValidateCreate(_ context.Context, _ *AzureStackHCIMachineTemplate) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate implements admission.Validator.
+func (*azureStackHCIMachineTemplateWebhook) ValidateUpdate(ctx context.Context, oldTemplate, newTemplate *AzureStackHCIMachineTemplate) (admission.Warnings, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an admission.Request inside context: %v", err))
+	}
+
+	// the topology controller dry-runs the desired template against the current one to detect changes
+	if topology.IsDryRunRequest(req, newTemplate) {
+		return nil, nil
+	}
+
+	if !reflect.DeepEqual(oldTemplate.Spec.Template.Spec, newTemplate.Spec.Template.Spec) {
+		return nil, apierrors.NewInvalid(GroupVersion.WithKind("AzureStackHCIMachineTemplate").GroupKind(), newTemplate.Name, field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "template", "spec"), "AzureStackHCIMachineTemplate spec.template.spec is immutable, create a new template instead"),
+		})
+	}
+	return nil, nil
+}
+
+// ValidateDelete implements admission.Validator.
+func (*azureStackHCIMachineTemplateWebhook) ValidateDelete(_ context.Context, _ *AzureStackHCIMachineTemplate) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// TestAzureStackHCIMachineTemplateValidateUpdate verifies that the template spec is immutable, except for the
+// dry-run requests the topology controller uses to compute the desired state.
+func TestAzureStackHCIMachineTemplateValidateUpdate(t *testing.T) {
+	template := func(vmSize string, annotations map[string]string) *AzureStackHCIMachineTemplate {
+		return &AzureStackHCIMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "md-0", Annotations: annotations},
+			Spec: AzureStackHCIMachineTemplateSpec{
+				Template: AzureStackHCIMachineTemplateResource{
+					Spec: AzureStackHCIMachineSpec{VMSize: vmSize},
+				},
+			},
+		}
+	}
+	dryRunAnnotations := map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+
+	tests := []struct {
+		name        string
+		oldTemplate *AzureStackHCIMachineTemplate
+		newTemplate *AzureStackHCIMachineTemplate
+		dryRun      bool
+		wantErr     bool
+	}{
+		{
+			name:        "unchanged spec",
+			oldTemplate: template("Default", nil),
+			newTemplate: template("Default", map[string]string{"foo": "bar"}),
+		},
+		{
+			name:        "changed spec",
+			oldTemplate: template("Default", nil),
+			newTemplate: template("Standard_K8S_v1", nil),
+			wantErr:     true,
+		},
+		{
+			name:        "changed spec in topology dry-run",
+			oldTemplate: template("Default", nil),
+			newTemplate: template("Standard_K8S_v1", dryRunAnnotations),
+			dryRun:      true,
+		},
+		{
+			name:        "changed spec in dry-run without topology annotation",
+			oldTemplate: template("Default", nil),
+			newTemplate: template("Standard_K8S_v1", nil),
+			dryRun:      true,
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{DryRun: ptr.To(tc.dryRun)},
+			})
+			_, err := (&azureStackHCIMachineTemplateWebhook{}).ValidateUpdate(ctx, tc.oldTemplate, tc.newTemplate)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationStep is an action taken to recover an unhealthy machine.
+// +kubebuilder:validation:Enum=SoftReboot;HardPowerCycle
+type RemediationStep string
+
+const (
+	// RemediationStepSoftReboot reboots the guest operating system through the guest agent.
+	RemediationStepSoftReboot RemediationStep = "SoftReboot"
+	// RemediationStepHardPowerCycle turns the virtual machine off and on again without involving the guest.
+	RemediationStepHardPowerCycle RemediationStep = "HardPowerCycle"
+	// RemediationStepReplace lets the owner of the Machine replace it. It is always the last step.
+	RemediationStepReplace RemediationStep = "Replace"
+)
+
+// AzureStackHCIRemediationSpec defines the desired state of AzureStackHCIRemediation
+type AzureStackHCIRemediationSpec struct {
+	// Strategy configures how the unhealthy machine is remediated.
+	// +optional
+	Strategy *RemediationStrategy `json:"strategy,omitempty"`
+}
+
+// RemediationStrategy configures the steps tried to recover an unhealthy machine before it is replaced.
+type RemediationStrategy struct {
+	// Steps are the actions tried in order before the Machine is replaced.
+	// Defaults to a soft reboot followed by a hard power cycle.
+	// +optional
+	// +kubebuilder:validation:MaxItems=2
+	// +listType=set
+	Steps []RemediationStep `json:"steps,omitempty"`
+
+	// RetryLimit is the number of times each step is tried. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	RetryLimit *int32 `json:"retryLimit,omitempty"`
+
+	// Timeout is how long the machine has to become healthy after a step before it is retried or
+	// the next step is taken. Defaults to 5 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// AzureStackHCIRemediationStatus defines the observed state of AzureStackHCIRemediation
+type AzureStackHCIRemediationStatus struct {
+	// Step is the remediation step currently taken.
+	// +optional
+	Step RemediationStep `json:"step,omitempty"`
+
+	// RetryCount is the number of times the current step was tried.
+	// +optional
+	RetryCount int32 `json:"retryCount,omitempty"`
+
+	// LastRemediated is the time the current step was last tried.
+	// +optional
+	LastRemediated *metav1.Time `json:"lastRemediated,omitempty"`
+
+	// Conditions defines current service state of the AzureStackHCIRemediation.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=azurestackhciremediations,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Step",type="string",JSONPath=".status.step",description="Remediation step currently taken"
+// +kubebuilder:printcolumn:name="Retries",type="integer",JSONPath=".status.retryCount",description="Number of times the current step was tried"
+// +kubebuilder:printcolumn:name="Last Remediated",type="date",JSONPath=".status.lastRemediated",description="Time the current step was last tried"
+
+// AzureStackHCIRemediation is the Schema for the azurestackhciremediations API.
+// It is created by a MachineHealthCheck for an unhealthy Machine, named after the Machine.
+type AzureStackHCIRemediation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AzureStackHCIRemediationSpec   `json:"spec,omitempty"`
+	Status AzureStackHCIRemediationStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the list of conditions for the AzureStackHCIRemediation.
+func (r *AzureStackHCIRemediation) GetConditions() []metav1.Condition {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the conditions for the AzureStackHCIRemediation.
+func (r *AzureStackHCIRemediation) SetConditions(conditions []metav1.Condition) {
+	r.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// AzureStackHCIRemediationList contains a list of AzureStackHCIRemediation
+type AzureStackHCIRemediationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureStackHCIRemediation `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &AzureStackHCIRemediation{}, &AzureStackHCIRemediationList{})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AzureStackHCIRemediationTemplateSpec defines the desired state of AzureStackHCIRemediationTemplate
+type AzureStackHCIRemediationTemplateSpec struct {
+	Template AzureStackHCIRemediationTemplateResource `json:"template"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=azurestackhciremediationtemplates,scope=Namespaced,categories=cluster-api
+
+// AzureStackHCIRemediationTemplate is the Schema for the azurestackhciremediationtemplates API.
+// It is referenced by the remediation templateRef of a MachineHealthCheck.
+type AzureStackHCIRemediationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AzureStackHCIRemediationTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AzureStackHCIRemediationTemplateList contains a list of AzureStackHCIRemediationTemplate
+type AzureStackHCIRemediationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureStackHCIRemediationTemplate `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &AzureStackHCIRemediationTemplate{}, &AzureStackHCIRemediationTemplateList{})
+}
+
+// AzureStackHCIRemediationTemplateResource describes the data needed to create an AzureStackHCIRemediation from a template
+type AzureStackHCIRemediationTemplateResource struct {
+	// Spec is the specification of the desired remediation of the machine.
+	Spec AzureStackHCIRemediationSpec `json:"spec"`
+}
@@ -33,6 +33,12 @@ const (
 type AzureStackHCIVirtualMachineSpec struct {
 	VMSize string `json:"vmSize"`
 
+	// CustomSize defines the processors and memory of the virtual machine when VMSize is Custom.
+	// +optional
+	CustomSize *VMCustomSize `json:"customSize,omitempty"`
+
+	// AvailabilityZone is ignored, Azure availability zones don't exist on Azure Stack HCI.
+	// Deprecated: use the failure domains of the AzureStackHCICluster instead.
 	// +optional
 	AvailabilityZone *AvailabilityZone `json:"availabilityZone,omitempty"`
 
@@ -69,6 +75,13 @@ type AzureStackHCIVirtualMachineSpec struct {
 
 	// +optional
 	PlacementGroupName string `json:"placementGroupName,omitempty"`
+
+	// PowerState is the desired power state of the virtual machine, which is started or stopped to match it.
+	// The power state is left unmanaged if not set. One-off operations, like a restart, are requested with
+	// the power-operation annotation instead.
+	// +optional
+	// +kubebuilder:validation:Enum=Running;Stopped
+	PowerState *VMPowerState `json:"powerState,omitempty"`
 }
 
 // AzureStackHCIVirtualMachineStatus defines the observed state of AzureStackHCIVirtualMachine
@@ -84,12 +97,34 @@ type AzureStackHCIVirtualMachineStatus struct {
 	// +optional
 	VMState *VMState `json:"vmState,omitempty"`
 
+	// Host is the name of the node the AzureStackHCI virtual machine runs on.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// PowerState is the power state of the AzureStackHCI virtual machine.
+	// +optional
+	PowerState VMPowerState `json:"powerState,omitempty"`
+
 	// +optional
 	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
 
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
 
+	// CapacityRetries is the number of consecutive creations that failed for lack of capacity.
+	// +optional
+	CapacityRetries int32 `json:"capacityRetries,omitempty"`
+
+	// CapacityChecks is the number of consecutive capacity checks that found no host with the capacity for the
+	// virtual machine before creating it. Unlike CapacityRetries, it doesn't make the lack of capacity terminal.
+	// +optional
+	CapacityChecks int32 `json:"capacityChecks,omitempty"`
+
+	// NextCapacityCheckTime is the time the capacity for the virtual machine is checked again while it waits
+	// for capacity. The delay doubles with each consecutive lack of capacity.
+	// +optional
+	NextCapacityCheckTime *metav1.Time `json:"nextCapacityCheckTime,omitempty"`
+
 	// Conditions defines current service state of the AzureStackHCIVirtualMachine.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
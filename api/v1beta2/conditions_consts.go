@@ -43,6 +43,42 @@ const (
 	// This is deterministic at the call-site because the failing call targets MOC, so downstream
 	// consumers can switch on the reason instead of pattern-matching the error message.
 	MOCUnreachableReason = "MOCUnreachable"
+	// InvalidConfigurationReason used when MOC rejects the virtual machine configuration, for example an invalid vm size.
+	InvalidConfigurationReason = "InvalidConfiguration"
+	// FailureDomainNotFoundReason used when the failure domain of the Machine isn't declared on the AzureStackHCICluster.
+	FailureDomainNotFoundReason = "FailureDomainNotFound"
+	// WaitingForImageReason used while the AzureStackHCIImage of the virtual machine is being imported.
+	WaitingForImageReason = "WaitingForImage"
+	// ImageNotFoundReason used when the gallery image of the virtual machine doesn't exist in its location.
+	// No network interface, disk or virtual machine is created until the image is available, and the virtual
+	// machine fails terminally when the image is still missing after DefaultImageNotFoundTimeout.
+	ImageNotFoundReason = "ImageNotFound"
+	// WaitingForCapacityReason used while no host has the capacity for the virtual machine. The capacity is checked
+	// again with an exponential backoff, and the virtual machine is created once it fits.
+	WaitingForCapacityReason = "WaitingForCapacity"
+
+	// ImageAvailableCondition reports whether the gallery image of the virtual machine exists in its location. It is
+	// false with the ImageNotFoundReason while the image is missing. Unlike VMRunningCondition, it isn't overwritten
+	// by the later steps of the provisioning, so it tells whether the image is the reason a virtual machine isn't created.
+	ImageAvailableCondition = "ImageAvailable"
+	// ImageFoundReason used when the gallery image of the virtual machine exists in its location.
+	ImageFoundReason = "ImageFound"
+
+	// TerminalFailureCondition is set to true when the virtual machine cannot be provisioned without changing its spec,
+	// for example when the image is not found, the vm size is invalid, or capacity is still lacking after
+	// DefaultMaxCapacityRetries attempts. It is not set for transient failures. A Machine with this condition
+	// is marked for remediation by a MachineHealthCheck.
+	TerminalFailureCondition = "TerminalFailure"
+
+	// VMPowerStateSyncedCondition reports whether the requested power state or power operation was applied to the
+	// virtual machine. It is only set while the power state is managed or an operation was requested.
+	VMPowerStateSyncedCondition = "PowerStateSynced"
+	// VMPowerOperationFailedReason used when a power operation failed on the virtual machine.
+	VMPowerOperationFailedReason = "PowerOperationFailed"
+	// VMInvalidPowerOperationReason used when the requested power operation is unknown or conflicts with the desired power state.
+	VMInvalidPowerOperationReason = "InvalidPowerOperation"
+	// VMPowerStateChangingReason used while the virtual machine transitions to the desired power state.
+	VMPowerStateChangingReason = "PowerStateChanging"
 )
 
 // Conditions and condition Reasons for the AzureStackHCICluster object
@@ -58,6 +94,11 @@ const (
 	LoadBalancerDeletingReason = "LoadBalancerDeleting"
 	// AzureStackHCIMachinesDeletingReason used when waiting on machines to be deleted
 	AzureStackHCIMachinesDeletingReason = "AzureStackHCIMachineDeleting"
+
+	// ControlPlaneEndpointReadyCondition reports whether the ControlPlaneEndpoint matches the loadbalancer frontend address.
+	ControlPlaneEndpointReadyCondition = "ControlPlaneEndpointReady"
+	// ControlPlaneEndpointDriftedReason used when the loadbalancer frontend address differs from the ControlPlaneEndpoint.
+	ControlPlaneEndpointDriftedReason = "ControlPlaneEndpointDrifted"
 )
 
 // Conditions and condition Reasons for the AzureStackHCILoadBalancer object
@@ -75,6 +116,13 @@ const (
 	LoadBalancerAddressUnavailableReason = "AddressUnavailable"
 	// LoadBalancerNoReplicasReadyReason used when no replicas are in a ready state.
 	LoadBalancerNoReplicasReadyReason = "NoReplicasReady"
+	// LoadBalancerVipPoolUnavailableReason used when the referenced vip pool cannot serve the loadbalancer frontend address.
+	LoadBalancerVipPoolUnavailableReason = "VipPoolUnavailable"
+
+	// LoadBalancerAddressPinnedCondition reports whether the MOC frontend address matches the address recorded in the status.
+	LoadBalancerAddressPinnedCondition = "AddressPinned"
+	// LoadBalancerAddressDriftedReason used when the MOC frontend address differs from the recorded address.
+	LoadBalancerAddressDriftedReason = "AddressDrifted"
 
 	// LoadBalancerReplicasReadyCondition reports on current status of the AzureStackHCILoadBalancer machine replicas
 	LoadBalancerReplicasReadyCondition = "LoadBalancerReplicasReady"
@@ -88,6 +136,95 @@ const (
 	LoadBalancerReplicasUpgradingReason = "Upgrading"
 	// LoadBalancerReplicasFailedReason used when we have failed replicas.
 	LoadBalancerReplicasFailedReason = "FailedReplicas"
+
+	// LoadBalancerReplicasHealthyCondition reports whether all replicas are healthy and if unhealthy replicas are remediated.
+	LoadBalancerReplicasHealthyCondition = "ReplicasHealthy"
+	// LoadBalancerRemediatingReplicasReason used when unhealthy replicas are replaced.
+	LoadBalancerRemediatingReplicasReason = "RemediatingReplicas"
+	// LoadBalancerRemediationNotAllowedReason used when more replicas than allowed are unhealthy and none are replaced.
+	LoadBalancerRemediationNotAllowedReason = "RemediationNotAllowed"
+
+	// LoadBalancerReplicasServiceConnectedCondition reports whether all running replicas are connected to the loadbalancer service.
+	LoadBalancerReplicasServiceConnectedCondition = "ReplicasServiceConnected"
+	// LoadBalancerReplicasDisconnectedReason used when running replicas are not connected to the loadbalancer service.
+	LoadBalancerReplicasDisconnectedReason = "ReplicasDisconnected"
+
+	// LoadBalancerAutoscalingActiveCondition reports whether the autoscaler is able to compute a recommendation.
+	LoadBalancerAutoscalingActiveCondition = "AutoscalingActive"
+	// LoadBalancerMetricsUnavailableReason used when the replica metrics cannot be gathered.
+	LoadBalancerMetricsUnavailableReason = "MetricsUnavailable"
+
+	// LoadBalancerImageAvailableCondition reports whether the image of new replicas exists in the gallery.
+	LoadBalancerImageAvailableCondition = "ImageAvailable"
+	// LoadBalancerImageNotFoundReason used when the image of new replicas is not found in the gallery.
+	LoadBalancerImageNotFoundReason = "ImageNotFound"
+
+	// LoadBalancerReplicasUpToDateCondition reports whether all replicas run the requested version.
+	LoadBalancerReplicasUpToDateCondition = "ReplicasUpToDate"
+	// LoadBalancerUpgradeRolledBackReason used when the replicas were rolled back to the previous image.
+	LoadBalancerUpgradeRolledBackReason = "UpgradeRolledBack"
+
+	// LoadBalancerMaintenanceCondition reports whether replicas are drained for host maintenance.
+	LoadBalancerMaintenanceCondition = "Maintenance"
+	// LoadBalancerDrainingReplicasReason used when replicas are replaced before they are removed for host maintenance.
+	LoadBalancerDrainingReplicasReason = "DrainingReplicas"
+)
+
+// AzureStackHCIMachinePool Conditions and Reasons
+
+const (
+	// MachinePoolReplicasReadyCondition reports whether the pool has the desired number of running virtual machines.
+	MachinePoolReplicasReadyCondition = "ReplicasReady"
+	// MachinePoolScalingUpReason used when virtual machines are added to the pool.
+	MachinePoolScalingUpReason = "ScalingUp"
+	// MachinePoolScalingDownReason used when virtual machines are removed from the pool.
+	MachinePoolScalingDownReason = "ScalingDown"
+	// MachinePoolWaitingForReplicasReadyReason used while virtual machines of the pool are not running yet.
+	MachinePoolWaitingForReplicasReadyReason = "WaitingForReplicasReady"
+	// MachinePoolReplacingFailedReplicasReason used when virtual machines that failed terminally are replaced.
+	MachinePoolReplacingFailedReplicasReason = "ReplacingFailedReplicas"
+	// MachinePoolWaitingForBootstrapDataReason used while the bootstrap data secret of the MachinePool isn't available.
+	MachinePoolWaitingForBootstrapDataReason = "WaitingForBootstrapData"
+
+	// MachinePoolReplicasUpToDateCondition reports whether all virtual machines were created from the current template.
+	MachinePoolReplicasUpToDateCondition = "ReplicasUpToDate"
+	// MachinePoolRollingUpdateReason used when outdated virtual machines are replaced.
+	MachinePoolRollingUpdateReason = "RollingUpdate"
+)
+
+// AzureStackHCIMachineTemplate Conditions and Reasons
+
+const (
+	// MachineTemplateCapacityResolvedCondition reports whether the capacity of the template was resolved from its VM size.
+	MachineTemplateCapacityResolvedCondition = "CapacityResolved"
+	// UnknownVMSizeReason used when the VM size isn't in the MOC VM size catalog.
+	UnknownVMSizeReason = "UnknownVMSize"
+)
+
+// AzureStackHCIImage Conditions and Reasons
+
+const (
+	// ImageReadyCondition reports whether the gallery image was imported and can be used by machines.
+	ImageReadyCondition = "ImageReady"
+	// ImageImportingReason used while the VHDX is imported into the image gallery.
+	ImageImportingReason = "ImageImporting"
+	// ImageImportFailedReason used when the VHDX couldn't be imported into the image gallery.
+	ImageImportFailedReason = "ImageImportFailed"
+	// ImageImportedReason used when the gallery image was imported.
+	ImageImportedReason = "ImageImported"
+)
+
+// AzureStackHCIRemediation Conditions and Reasons
+
+const (
+	// RemediationInProgressCondition reports the remediation step taken to recover the unhealthy machine.
+	RemediationInProgressCondition = "RemediationInProgress"
+	// RemediationStepFailedReason used when the current remediation step couldn't be applied to the virtual machine.
+	RemediationStepFailedReason = "RemediationStepFailed"
+	// WaitingForMachineReason used while the Machine or its infrastructure isn't available yet.
+	WaitingForMachineReason = "WaitingForMachine"
+	// WaitingForReplacementReason used when all steps failed and the owner of the Machine was asked to replace it.
+	WaitingForReplacementReason = "WaitingForReplacement"
 )
 
 // Common condition Reasons used across multiple AzureStackHCI resources
@@ -20,6 +20,8 @@ limitations under the License.
 package v1beta2
 
 import (
+	"net/netip"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -88,12 +90,40 @@ type VnetSpec struct {
 	Name string `json:"name"`
 
 	// CidrBlock is the CIDR block to be used when the provider creates a managed virtual network.
+	// Deprecated: use CidrBlocks.
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// CidrBlocks are the CIDR blocks to be used when the provider creates a managed virtual network.
+	// Set an IPv4 and an IPv6 block for a dual-stack network.
+	// +optional
+	// +kubebuilder:validation:MaxItems=2
+	CidrBlocks []string `json:"cidrBlocks,omitempty"`
+
 	// Group is the resource group the vnet should use.
 	Group string `json:"group,omitempty"`
 }
 
+// GetCidrBlocks returns the CIDR blocks of the virtual network, falling back to the deprecated CidrBlock.
+func (v *VnetSpec) GetCidrBlocks() []string {
+	if len(v.CidrBlocks) > 0 {
+		return v.CidrBlocks
+	}
+	if v.CidrBlock != "" {
+		return []string{v.CidrBlock}
+	}
+	return nil
+}
+
+// IsIPv6Enabled returns true if one of the CIDR blocks of the virtual network is an IPv6 block.
+func (v *VnetSpec) IsIPv6Enabled() bool {
+	for _, cidr := range v.GetCidrBlocks() {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil && prefix.Addr().Is6() {
+			return true
+		}
+	}
+	return false
+}
+
 // Subnets is a slice of Subnet.
 type Subnets []*SubnetSpec
 
@@ -115,6 +145,17 @@ const (
 	IPAllocationMethod_Static  IPAllocationMethod = 2
 )
 
+// IPVersion is the address family of an ip configuration.
+// +kubebuilder:validation:Enum=IPv4;IPv6
+type IPVersion string
+
+const (
+	// IPv4 address family.
+	IPv4 IPVersion = "IPv4"
+	// IPv6 address family.
+	IPv6 IPVersion = "IPv6"
+)
+
 // nolint: golint
 type IpConfigurationSpec struct {
 	Name string `json:"name,omitempty"`
@@ -122,6 +163,9 @@ type IpConfigurationSpec struct {
 	Primary bool `json:"primary,omitempty"`
 	// +optional
 	Allocation IPAllocationMethod `json:"allocation,omitempty"`
+	// IPVersion is the address family of the ip configuration. Defaults to IPv4.
+	// +optional
+	IPVersion IPVersion `json:"ipVersion,omitempty"`
 	// below fields are unused, but adding for completeness
 	// +optional
 	IpAddress string `json:"ipAddress,omitempty"`
@@ -150,6 +194,10 @@ const (
 	OSVersionLabelName = "msft.microsoft/os-version"
 	// LoadBalancerLabel is the label set on load balancer replica machines
 	LoadBalancerLabel = "msft.microsoft/load-balancer"
+	// MachinePoolLabel is the label set on machine pool virtual machines
+	MachinePoolLabel = "msft.microsoft/machine-pool"
+	// MachinePoolTemplateHashLabel is the label set on machine pool virtual machines to identify the template they were created from
+	MachinePoolTemplateHashLabel = "msft.microsoft/machine-pool-template-hash"
 )
 
 // VMState describes the state of an Azure virtual machine.
@@ -170,6 +218,54 @@ var (
 	VMStateUpdating = VMState("Updating")
 )
 
+// ImageState describes the import state of a gallery image.
+type ImageState string
+
+var (
+	// ImageStateImporting ...
+	ImageStateImporting = ImageState("Importing")
+	// ImageStateSucceeded ...
+	ImageStateSucceeded = ImageState("Succeeded")
+	// ImageStateFailed ...
+	ImageStateFailed = ImageState("Failed")
+	// ImageStateDeleting ...
+	ImageStateDeleting = ImageState("Deleting")
+)
+
+// VMPowerState describes the power state of a virtual machine.
+type VMPowerState string
+
+var (
+	// VMPowerStateRunning ...
+	VMPowerStateRunning = VMPowerState("Running")
+	// VMPowerStateStopped ...
+	VMPowerStateStopped = VMPowerState("Stopped")
+	// VMPowerStatePaused ...
+	VMPowerStatePaused = VMPowerState("Paused")
+	// VMPowerStateSaved ...
+	VMPowerStateSaved = VMPowerState("Saved")
+	// VMPowerStateCritical ...
+	VMPowerStateCritical = VMPowerState("Critical")
+	// VMPowerStateUnknown ...
+	VMPowerStateUnknown = VMPowerState("Unknown")
+)
+
+// VMPowerOperation is a one-off power operation requested on a virtual machine.
+type VMPowerOperation string
+
+var (
+	// VMPowerOperationStart starts the virtual machine.
+	VMPowerOperationStart = VMPowerOperation("start")
+	// VMPowerOperationStop turns the virtual machine off.
+	VMPowerOperationStop = VMPowerOperation("stop")
+	// VMPowerOperationShutdown shuts the guest operating system down.
+	VMPowerOperationShutdown = VMPowerOperation("shutdown")
+	// VMPowerOperationRestart reboots the guest operating system.
+	VMPowerOperationRestart = VMPowerOperation("restart")
+	// VMPowerOperationPowerCycle turns the virtual machine off and on again.
+	VMPowerOperationPowerCycle = VMPowerOperation("powercycle")
+)
+
 // VM describes an Azure virtual machine.
 type VM struct {
 	ID   string `json:"id,omitempty"`
@@ -189,6 +285,12 @@ type VM struct {
 	// State - The provisioning state, which only appears in the response.
 	State    VMState    `json:"vmState,omitempty"`
 	Identity VMIdentity `json:"identity,omitempty"`
+
+	// Host is the name of the node the virtual machine runs on.
+	Host string `json:"host,omitempty"`
+
+	// PowerState is the power state of the virtual machine.
+	PowerState VMPowerState `json:"powerState,omitempty"`
 }
 
 // Image defines information about the image to use for VM creation.
@@ -213,6 +315,145 @@ type Image struct {
 	OSType  OSType  `json:"osType"`
 }
 
+// FailureDomainSpec declares a failure domain. Machines placed in the failure domain join its availability set
+// or placement group.
+// +kubebuilder:validation:XValidation:rule="has(self.availabilitySetName) || has(self.placementGroupName)",message="either availabilitySetName or placementGroupName must be set"
+type FailureDomainSpec struct {
+	// Name is the name of the failure domain, referenced by Machine.spec.failureDomain.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ControlPlane marks the failure domain as suitable for control plane machines. Defaults to true.
+	// +optional
+	ControlPlane *bool `json:"controlPlane,omitempty"`
+
+	// AvailabilitySetName is the name of the availability set machines in the failure domain join.
+	// +optional
+	AvailabilitySetName string `json:"availabilitySetName,omitempty"`
+
+	// PlacementGroupName is the name of the placement group machines in the failure domain join.
+	// +optional
+	PlacementGroupName string `json:"placementGroupName,omitempty"`
+
+	// Attributes are arbitrary attributes published with the failure domain.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// AvailabilitySetSpec declares an availability set created with the cluster.
+type AvailabilitySetSpec struct {
+	// Name is the name of the availability set.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// PlatformFaultDomainCount is the number of fault domains virtual machines are spread across. Defaults to 2.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PlatformFaultDomainCount *int32 `json:"platformFaultDomainCount,omitempty"`
+}
+
+// PlacementGroupPolicy is the placement policy of the virtual machines in a placement group.
+// +kubebuilder:validation:Enum=Affinity;AntiAffinity;StrictAntiAffinity
+type PlacementGroupPolicy string
+
+const (
+	// PlacementGroupPolicyAffinity places the virtual machines on the same host.
+	PlacementGroupPolicyAffinity PlacementGroupPolicy = "Affinity"
+	// PlacementGroupPolicyAntiAffinity prefers placing the virtual machines on different hosts.
+	PlacementGroupPolicyAntiAffinity PlacementGroupPolicy = "AntiAffinity"
+	// PlacementGroupPolicyStrictAntiAffinity places the virtual machines on different hosts, or fails.
+	PlacementGroupPolicyStrictAntiAffinity PlacementGroupPolicy = "StrictAntiAffinity"
+)
+
+// PlacementGroupSpec declares a placement group created with the cluster.
+type PlacementGroupSpec struct {
+	// Name is the name of the placement group.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Policy is the placement policy of the virtual machines in the placement group.
+	// +kubebuilder:default=AntiAffinity
+	// +optional
+	Policy PlacementGroupPolicy `json:"policy,omitempty"`
+}
+
+// StorageContainerSpec declares a storage container of the cluster.
+type StorageContainerSpec struct {
+	// Name is the name of the storage container.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Path is the path of the storage container on the cloud agent nodes, for example a cluster shared volume.
+	// When unset, the storage container must already exist and is not deleted with the cluster.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// StorageContainerPolicy selects the storage container of virtual machines that don't request one.
+// +kubebuilder:validation:Enum=Explicit;LeastUsed;RoundRobin
+type StorageContainerPolicy string
+
+const (
+	// StorageContainerPolicyExplicit only uses the storage container requested by the machine, virtual machines
+	// that don't request one are placed in the default storage container of MOC.
+	StorageContainerPolicyExplicit StorageContainerPolicy = "Explicit"
+	// StorageContainerPolicyLeastUsed places virtual machines in the storage container of the cluster with the
+	// fewest virtual machines.
+	StorageContainerPolicyLeastUsed StorageContainerPolicy = "LeastUsed"
+	// StorageContainerPolicyRoundRobin places virtual machines in the storage containers of the cluster in turn.
+	StorageContainerPolicyRoundRobin StorageContainerPolicy = "RoundRobin"
+)
+
+// PlacementStatus reports an availability set, placement group or storage container of the cluster.
+type PlacementStatus struct {
+	// Name is the name of the availability set, placement group or storage container.
+	Name string `json:"name"`
+
+	// VirtualMachines is the number of virtual machines in the availability set, placement group or storage container.
+	VirtualMachines int32 `json:"virtualMachines"`
+}
+
+// ClusterCapacity summarizes the resources available on the hosts of the cloud.
+type ClusterCapacity struct {
+	// Hosts is the number of hosts reporting their available resources.
+	Hosts int32 `json:"hosts"`
+
+	// Available is the cpu, memory and GPUs available on the hosts.
+	// +optional
+	Available corev1.ResourceList `json:"available,omitempty"`
+
+	// VMSizes reports how many more virtual machines of each VM size used by the cluster fit on the hosts.
+	// +optional
+	// +listType=map
+	// +listMapKey=vmSize
+	// +kubebuilder:validation:MaxItems=100
+	VMSizes []VMSizeCapacity `json:"vmSizes,omitempty"`
+
+	// LastUpdateTime is the time the capacity was last collected.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// VMSizeCapacity reports how many more virtual machines of a VM size fit on the hosts.
+type VMSizeCapacity struct {
+	// VMSize is the VM size.
+	VMSize string `json:"vmSize"`
+
+	// Available is the number of virtual machines of the VM size that fit on the hosts.
+	Available int32 `json:"available"`
+}
+
+// VMCustomSize defines the processors and memory of a virtual machine of the Custom VM size.
+type VMCustomSize struct {
+	// CpuCount is the number of processors of the virtual machine.
+	// +kubebuilder:validation:Minimum=1
+	CpuCount int32 `json:"cpuCount"`
+
+	// MemoryMB is the memory of the virtual machine in MB.
+	// +kubebuilder:validation:Minimum=1
+	MemoryMB int32 `json:"memoryMB"`
+}
+
 type AvailabilityZone struct {
 	ID      *string `json:"id,omitempty"`
 	Enabled *bool   `json:"enabled,omitempty"`
@@ -257,7 +498,34 @@ type SubnetSpec struct {
 	VnetID string `json:"vnetId"`
 
 	// CidrBlock is the CIDR block to be used when the provider creates a managed Vnet.
+	// Deprecated: use CidrBlocks.
 	CidrBlock string `json:"cidrBlock,omitempty"`
+
+	// CidrBlocks are the CIDR blocks to be used when the provider creates a managed Vnet.
+	// Set an IPv4 and an IPv6 block for a dual-stack subnet.
+	// +optional
+	// +kubebuilder:validation:MaxItems=2
+	CidrBlocks []string `json:"cidrBlocks,omitempty"`
+}
+
+// VipPoolSpec references the vip pool that load balancer frontend addresses are allocated from.
+type VipPoolSpec struct {
+	// Name of the vip pool.
+	Name string `json:"name"`
+
+	// StartIP is the first address of a vip pool owned by the cluster. When both StartIP and EndIP
+	// are set the pool is created along with the cluster and deleted when the cluster is deleted.
+	// +optional
+	StartIP string `json:"startIP,omitempty"`
+
+	// EndIP is the last address of a vip pool owned by the cluster.
+	// +optional
+	EndIP string `json:"endIP,omitempty"`
+}
+
+// IsOwned returns true if the vip pool is managed by the cluster.
+func (v *VipPoolSpec) IsOwned() bool {
+	return v != nil && v.StartIP != "" && v.EndIP != ""
 }
 
 const (
@@ -266,4 +534,14 @@ const (
 	AnnotationControlPlaneReady          = "azurestackhci.cluster.sigs.k8s.io/control-plane-ready"
 	AzureOperationIDAnnotationKey        = "management.azure.com/operationId"
 	AzureCorrelationIDAnnotationKey      = "management.azure.com/correlationId"
+	// AnnotationLoadBalancerMaintenance is set on a load balancer replica to drain it before its host is taken down.
+	// The replica is taken out of the load balancer service once a replacement replica is connected, and removed
+	// once its connections drained.
+	AnnotationLoadBalancerMaintenance = "azurestackhci.cluster.sigs.k8s.io/loadbalancer-maintenance"
+	// AnnotationLoadBalancerDrainStarted records when a load balancer replica was taken out of the load balancer
+	// service, in RFC3339 format.
+	AnnotationLoadBalancerDrainStarted = "azurestackhci.cluster.sigs.k8s.io/loadbalancer-drain-started"
+	// AnnotationVMPowerOperation requests a one-off power operation on an AzureStackHCIVirtualMachine:
+	// start, stop, shutdown, restart or powercycle. It is removed once the operation was applied.
+	AnnotationVMPowerOperation = "azurestackhci.cluster.sigs.k8s.io/power-operation"
 )
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestVnetSpecCidrBlocks(t *testing.T) {
+	tests := []struct {
+		name       string
+		vnet       VnetSpec
+		wantBlocks []string
+		wantIPv6   bool
+	}{
+		{
+			name: "no blocks",
+		},
+		{
+			name:       "deprecated cidr block",
+			vnet:       VnetSpec{CidrBlock: "10.0.0.0/16"},
+			wantBlocks: []string{"10.0.0.0/16"},
+		},
+		{
+			name:       "cidr blocks take precedence",
+			vnet:       VnetSpec{CidrBlock: "10.0.0.0/16", CidrBlocks: []string{"10.1.0.0/16"}},
+			wantBlocks: []string{"10.1.0.0/16"},
+		},
+		{
+			name:       "dual-stack",
+			vnet:       VnetSpec{CidrBlocks: []string{"10.0.0.0/16", "fd00::/64"}},
+			wantBlocks: []string{"10.0.0.0/16", "fd00::/64"},
+			wantIPv6:   true,
+		},
+		{
+			name:       "invalid block is not IPv6",
+			vnet:       VnetSpec{CidrBlocks: []string{"fd00::"}},
+			wantBlocks: []string{"fd00::"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tc.vnet.GetCidrBlocks()).To(Equal(tc.wantBlocks))
+			g.Expect(tc.vnet.IsIPv6Enabled()).To(Equal(tc.wantIPv6))
+		})
+	}
+}
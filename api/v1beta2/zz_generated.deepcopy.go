@@ -25,9 +25,31 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1beta2 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvailabilitySetSpec) DeepCopyInto(out *AvailabilitySetSpec) {
+	*out = *in
+	if in.PlatformFaultDomainCount != nil {
+		in, out := &in.PlatformFaultDomainCount, &out.PlatformFaultDomainCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailabilitySetSpec.
+func (in *AvailabilitySetSpec) DeepCopy() *AvailabilitySetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AvailabilitySetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AvailabilityZone) DeepCopyInto(out *AvailabilityZone) {
 	*out = *in
@@ -147,6 +169,30 @@ func (in *AzureStackHCIClusterSpec) DeepCopyInto(out *AzureStackHCIClusterSpec)
 		*out = new(string)
 		**out = **in
 	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]FailureDomainSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AvailabilitySets != nil {
+		in, out := &in.AvailabilitySets, &out.AvailabilitySets
+		*out = make([]AvailabilitySetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlacementGroups != nil {
+		in, out := &in.PlacementGroups, &out.PlacementGroups
+		*out = make([]PlacementGroupSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageContainers != nil {
+		in, out := &in.StorageContainers, &out.StorageContainers
+		*out = make([]StorageContainerSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIClusterSpec.
@@ -170,6 +216,33 @@ func (in *AzureStackHCIClusterStatus) DeepCopyInto(out *AzureStackHCIClusterStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]corev1beta2.FailureDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AvailabilitySets != nil {
+		in, out := &in.AvailabilitySets, &out.AvailabilitySets
+		*out = make([]PlacementStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlacementGroups != nil {
+		in, out := &in.PlacementGroups, &out.PlacementGroups
+		*out = make([]PlacementStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageContainers != nil {
+		in, out := &in.StorageContainers, &out.StorageContainers
+		*out = make([]PlacementStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(ClusterCapacity)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Initialization != nil {
 		in, out := &in.Initialization, &out.Initialization
 		*out = new(AzureStackHCIClusterInitializationStatus)
@@ -277,6 +350,103 @@ func (in *AzureStackHCIClusterTemplateSpec) DeepCopy() *AzureStackHCIClusterTemp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIImage) DeepCopyInto(out *AzureStackHCIImage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIImage.
+func (in *AzureStackHCIImage) DeepCopy() *AzureStackHCIImage {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureStackHCIImage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIImageList) DeepCopyInto(out *AzureStackHCIImageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureStackHCIImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIImageList.
+func (in *AzureStackHCIImageList) DeepCopy() *AzureStackHCIImageList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIImageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureStackHCIImageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIImageSpec) DeepCopyInto(out *AzureStackHCIImageSpec) {
+	*out = *in
+	out.Source = in.Source
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIImageSpec.
+func (in *AzureStackHCIImageSpec) DeepCopy() *AzureStackHCIImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIImageStatus) DeepCopyInto(out *AzureStackHCIImageStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIImageStatus.
+func (in *AzureStackHCIImageStatus) DeepCopy() *AzureStackHCIImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureStackHCILoadBalancer) DeepCopyInto(out *AzureStackHCILoadBalancer) {
 	*out = *in
@@ -344,11 +514,62 @@ func (in *AzureStackHCILoadBalancerSpec) DeepCopyInto(out *AzureStackHCILoadBala
 		*out = new(Image)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
 		**out = **in
 	}
+	if in.VipPool != nil {
+		in, out := &in.VipPool, &out.VipPool
+		*out = new(VipPoolSpec)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]LoadBalancingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]LoadBalancerProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]LoadBalancerListener, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(LoadBalancerStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(LoadBalancerHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(LoadBalancerPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(LoadBalancerAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCILoadBalancerSpec.
@@ -364,6 +585,21 @@ func (in *AzureStackHCILoadBalancerSpec) DeepCopy() *AzureStackHCILoadBalancerSp
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureStackHCILoadBalancerStatus) DeepCopyInto(out *AzureStackHCILoadBalancerStatus) {
 	*out = *in
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]LoadBalancerListenerStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(LoadBalancerAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(LoadBalancerUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -473,34 +709,111 @@ func (in *AzureStackHCIMachineList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureStackHCIMachineProviderCondition) DeepCopyInto(out *AzureStackHCIMachineProviderCondition) {
+func (in *AzureStackHCIMachinePool) DeepCopyInto(out *AzureStackHCIMachinePool) {
 	*out = *in
-	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachineProviderCondition.
-func (in *AzureStackHCIMachineProviderCondition) DeepCopy() *AzureStackHCIMachineProviderCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachinePool.
+func (in *AzureStackHCIMachinePool) DeepCopy() *AzureStackHCIMachinePool {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureStackHCIMachineProviderCondition)
+	out := new(AzureStackHCIMachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureStackHCIMachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIMachinePoolInitializationStatus) DeepCopyInto(out *AzureStackHCIMachinePoolInitializationStatus) {
+	*out = *in
+	if in.Provisioned != nil {
+		in, out := &in.Provisioned, &out.Provisioned
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachinePoolInitializationStatus.
+func (in *AzureStackHCIMachinePoolInitializationStatus) DeepCopy() *AzureStackHCIMachinePoolInitializationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIMachinePoolInitializationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureStackHCIMachineSpec) DeepCopyInto(out *AzureStackHCIMachineSpec) {
+func (in *AzureStackHCIMachinePoolInstance) DeepCopyInto(out *AzureStackHCIMachinePoolInstance) {
 	*out = *in
-	if in.ProviderID != nil {
-		in, out := &in.ProviderID, &out.ProviderID
-		*out = new(string)
+	if in.VMState != nil {
+		in, out := &in.VMState, &out.VMState
+		*out = new(VMState)
 		**out = **in
 	}
-	if in.AvailabilityZone != nil {
-		in, out := &in.AvailabilityZone, &out.AvailabilityZone
-		*out = new(AvailabilityZone)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachinePoolInstance.
+func (in *AzureStackHCIMachinePoolInstance) DeepCopy() *AzureStackHCIMachinePoolInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIMachinePoolInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIMachinePoolList) DeepCopyInto(out *AzureStackHCIMachinePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureStackHCIMachinePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachinePoolList.
+func (in *AzureStackHCIMachinePoolList) DeepCopy() *AzureStackHCIMachinePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIMachinePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureStackHCIMachinePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIMachinePoolMachineTemplate) DeepCopyInto(out *AzureStackHCIMachinePoolMachineTemplate) {
+	*out = *in
+	if in.CustomSize != nil {
+		in, out := &in.CustomSize, &out.CustomSize
+		*out = new(VMCustomSize)
+		**out = **in
 	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
@@ -530,49 +843,192 @@ func (in *AzureStackHCIMachineSpec) DeepCopyInto(out *AzureStackHCIMachineSpec)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachineSpec.
-func (in *AzureStackHCIMachineSpec) DeepCopy() *AzureStackHCIMachineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachinePoolMachineTemplate.
+func (in *AzureStackHCIMachinePoolMachineTemplate) DeepCopy() *AzureStackHCIMachinePoolMachineTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureStackHCIMachineSpec)
+	out := new(AzureStackHCIMachinePoolMachineTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureStackHCIMachineStatus) DeepCopyInto(out *AzureStackHCIMachineStatus) {
+func (in *AzureStackHCIMachinePoolSpec) DeepCopyInto(out *AzureStackHCIMachinePoolSpec) {
 	*out = *in
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]corev1.NodeAddress, len(*in))
+	if in.ProviderIDList != nil {
+		in, out := &in.ProviderIDList, &out.ProviderIDList
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.VMState != nil {
-		in, out := &in.VMState, &out.VMState
-		*out = new(VMState)
-		**out = **in
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Initialization != nil {
-		in, out := &in.Initialization, &out.Initialization
-		*out = new(AzureStackHCIMachineInitializationStatus)
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(MachinePoolStrategy)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachineStatus.
-func (in *AzureStackHCIMachineStatus) DeepCopy() *AzureStackHCIMachineStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachinePoolSpec.
+func (in *AzureStackHCIMachinePoolSpec) DeepCopy() *AzureStackHCIMachinePoolSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureStackHCIMachineStatus)
+	out := new(AzureStackHCIMachinePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIMachinePoolStatus) DeepCopyInto(out *AzureStackHCIMachinePoolStatus) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]AzureStackHCIMachinePoolInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Initialization != nil {
+		in, out := &in.Initialization, &out.Initialization
+		*out = new(AzureStackHCIMachinePoolInitializationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachinePoolStatus.
+func (in *AzureStackHCIMachinePoolStatus) DeepCopy() *AzureStackHCIMachinePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIMachinePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIMachineProviderCondition) DeepCopyInto(out *AzureStackHCIMachineProviderCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachineProviderCondition.
+func (in *AzureStackHCIMachineProviderCondition) DeepCopy() *AzureStackHCIMachineProviderCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIMachineProviderCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIMachineSpec) DeepCopyInto(out *AzureStackHCIMachineSpec) {
+	*out = *in
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CustomSize != nil {
+		in, out := &in.CustomSize, &out.CustomSize
+		*out = new(VMCustomSize)
+		**out = **in
+	}
+	if in.AvailabilityZone != nil {
+		in, out := &in.AvailabilityZone, &out.AvailabilityZone
+		*out = new(AvailabilityZone)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OSDisk != nil {
+		in, out := &in.OSDisk, &out.OSDisk
+		*out = new(OSDisk)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalSSHKeys != nil {
+		in, out := &in.AdditionalSSHKeys, &out.AdditionalSSHKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make(NetworkInterfaces, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(NetworkInterfaceSpec)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachineSpec.
+func (in *AzureStackHCIMachineSpec) DeepCopy() *AzureStackHCIMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIMachineStatus) DeepCopyInto(out *AzureStackHCIMachineStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]corev1.NodeAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.VMState != nil {
+		in, out := &in.VMState, &out.VMState
+		*out = new(VMState)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Initialization != nil {
+		in, out := &in.Initialization, &out.Initialization
+		*out = new(AzureStackHCIMachineInitializationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachineStatus.
+func (in *AzureStackHCIMachineStatus) DeepCopy() *AzureStackHCIMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIMachineStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -583,6 +1039,7 @@ func (in *AzureStackHCIMachineTemplate) DeepCopyInto(out *AzureStackHCIMachineTe
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachineTemplate.
@@ -668,27 +1125,41 @@ func (in *AzureStackHCIMachineTemplateSpec) DeepCopy() *AzureStackHCIMachineTemp
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureStackHCIResourceReference) DeepCopyInto(out *AzureStackHCIResourceReference) {
+func (in *AzureStackHCIMachineTemplateStatus) DeepCopyInto(out *AzureStackHCIMachineTemplateStatus) {
 	*out = *in
-	if in.ID != nil {
-		in, out := &in.ID, &out.ID
-		*out = new(string)
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.NodeInfo != nil {
+		in, out := &in.NodeInfo, &out.NodeInfo
+		*out = new(NodeInfo)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIResourceReference.
-func (in *AzureStackHCIResourceReference) DeepCopy() *AzureStackHCIResourceReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIMachineTemplateStatus.
+func (in *AzureStackHCIMachineTemplateStatus) DeepCopy() *AzureStackHCIMachineTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureStackHCIResourceReference)
+	out := new(AzureStackHCIMachineTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureStackHCIVirtualMachine) DeepCopyInto(out *AzureStackHCIVirtualMachine) {
+func (in *AzureStackHCIRemediation) DeepCopyInto(out *AzureStackHCIRemediation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -696,18 +1167,18 @@ func (in *AzureStackHCIVirtualMachine) DeepCopyInto(out *AzureStackHCIVirtualMac
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIVirtualMachine.
-func (in *AzureStackHCIVirtualMachine) DeepCopy() *AzureStackHCIVirtualMachine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIRemediation.
+func (in *AzureStackHCIRemediation) DeepCopy() *AzureStackHCIRemediation {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureStackHCIVirtualMachine)
+	out := new(AzureStackHCIRemediation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AzureStackHCIVirtualMachine) DeepCopyObject() runtime.Object {
+func (in *AzureStackHCIRemediation) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -715,31 +1186,31 @@ func (in *AzureStackHCIVirtualMachine) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureStackHCIVirtualMachineList) DeepCopyInto(out *AzureStackHCIVirtualMachineList) {
+func (in *AzureStackHCIRemediationList) DeepCopyInto(out *AzureStackHCIRemediationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]AzureStackHCIVirtualMachine, len(*in))
+		*out = make([]AzureStackHCIRemediation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIVirtualMachineList.
-func (in *AzureStackHCIVirtualMachineList) DeepCopy() *AzureStackHCIVirtualMachineList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIRemediationList.
+func (in *AzureStackHCIRemediationList) DeepCopy() *AzureStackHCIRemediationList {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureStackHCIVirtualMachineList)
+	out := new(AzureStackHCIRemediationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AzureStackHCIVirtualMachineList) DeepCopyObject() runtime.Object {
+func (in *AzureStackHCIRemediationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -747,201 +1218,832 @@ func (in *AzureStackHCIVirtualMachineList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureStackHCIVirtualMachineSpec) DeepCopyInto(out *AzureStackHCIVirtualMachineSpec) {
+func (in *AzureStackHCIRemediationSpec) DeepCopyInto(out *AzureStackHCIRemediationSpec) {
 	*out = *in
-	if in.AvailabilityZone != nil {
-		in, out := &in.AvailabilityZone, &out.AvailabilityZone
-		*out = new(AvailabilityZone)
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(RemediationStrategy)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(Image)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIRemediationSpec.
+func (in *AzureStackHCIRemediationSpec) DeepCopy() *AzureStackHCIRemediationSpec {
+	if in == nil {
+		return nil
 	}
-	if in.OSDisk != nil {
-		in, out := &in.OSDisk, &out.OSDisk
-		*out = new(OSDisk)
-		(*in).DeepCopyInto(*out)
+	out := new(AzureStackHCIRemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIRemediationStatus) DeepCopyInto(out *AzureStackHCIRemediationStatus) {
+	*out = *in
+	if in.LastRemediated != nil {
+		in, out := &in.LastRemediated, &out.LastRemediated
+		*out = (*in).DeepCopy()
 	}
-	if in.BootstrapData != nil {
-		in, out := &in.BootstrapData, &out.BootstrapData
-		*out = new(string)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.BackendPoolNames != nil {
-		in, out := &in.BackendPoolNames, &out.BackendPoolNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIRemediationStatus.
+func (in *AzureStackHCIRemediationStatus) DeepCopy() *AzureStackHCIRemediationStatus {
+	if in == nil {
+		return nil
 	}
-	if in.AdditionalSSHKeys != nil {
-		in, out := &in.AdditionalSSHKeys, &out.AdditionalSSHKeys
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(AzureStackHCIRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIRemediationTemplate) DeepCopyInto(out *AzureStackHCIRemediationTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIRemediationTemplate.
+func (in *AzureStackHCIRemediationTemplate) DeepCopy() *AzureStackHCIRemediationTemplate {
+	if in == nil {
+		return nil
 	}
-	if in.NetworkInterfaces != nil {
-		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
-		*out = make(NetworkInterfaces, len(*in))
+	out := new(AzureStackHCIRemediationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureStackHCIRemediationTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIRemediationTemplateList) DeepCopyInto(out *AzureStackHCIRemediationTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureStackHCIRemediationTemplate, len(*in))
 		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(NetworkInterfaceSpec)
-				(*in).DeepCopyInto(*out)
-			}
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIVirtualMachineSpec.
-func (in *AzureStackHCIVirtualMachineSpec) DeepCopy() *AzureStackHCIVirtualMachineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIRemediationTemplateList.
+func (in *AzureStackHCIRemediationTemplateList) DeepCopy() *AzureStackHCIRemediationTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIRemediationTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureStackHCIRemediationTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIRemediationTemplateResource) DeepCopyInto(out *AzureStackHCIRemediationTemplateResource) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIRemediationTemplateResource.
+func (in *AzureStackHCIRemediationTemplateResource) DeepCopy() *AzureStackHCIRemediationTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIRemediationTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIRemediationTemplateSpec) DeepCopyInto(out *AzureStackHCIRemediationTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIRemediationTemplateSpec.
+func (in *AzureStackHCIRemediationTemplateSpec) DeepCopy() *AzureStackHCIRemediationTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIRemediationTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIResourceReference) DeepCopyInto(out *AzureStackHCIResourceReference) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIResourceReference.
+func (in *AzureStackHCIResourceReference) DeepCopy() *AzureStackHCIResourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIResourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIVirtualMachine) DeepCopyInto(out *AzureStackHCIVirtualMachine) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIVirtualMachine.
+func (in *AzureStackHCIVirtualMachine) DeepCopy() *AzureStackHCIVirtualMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIVirtualMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureStackHCIVirtualMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIVirtualMachineList) DeepCopyInto(out *AzureStackHCIVirtualMachineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureStackHCIVirtualMachine, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIVirtualMachineList.
+func (in *AzureStackHCIVirtualMachineList) DeepCopy() *AzureStackHCIVirtualMachineList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIVirtualMachineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureStackHCIVirtualMachineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIVirtualMachineSpec) DeepCopyInto(out *AzureStackHCIVirtualMachineSpec) {
+	*out = *in
+	if in.CustomSize != nil {
+		in, out := &in.CustomSize, &out.CustomSize
+		*out = new(VMCustomSize)
+		**out = **in
+	}
+	if in.AvailabilityZone != nil {
+		in, out := &in.AvailabilityZone, &out.AvailabilityZone
+		*out = new(AvailabilityZone)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OSDisk != nil {
+		in, out := &in.OSDisk, &out.OSDisk
+		*out = new(OSDisk)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootstrapData != nil {
+		in, out := &in.BootstrapData, &out.BootstrapData
+		*out = new(string)
+		**out = **in
+	}
+	if in.BackendPoolNames != nil {
+		in, out := &in.BackendPoolNames, &out.BackendPoolNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalSSHKeys != nil {
+		in, out := &in.AdditionalSSHKeys, &out.AdditionalSSHKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = make(NetworkInterfaces, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(NetworkInterfaceSpec)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.PowerState != nil {
+		in, out := &in.PowerState, &out.PowerState
+		*out = new(VMPowerState)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIVirtualMachineSpec.
+func (in *AzureStackHCIVirtualMachineSpec) DeepCopy() *AzureStackHCIVirtualMachineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIVirtualMachineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureStackHCIVirtualMachineStatus) DeepCopyInto(out *AzureStackHCIVirtualMachineStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]corev1.NodeAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.VMState != nil {
+		in, out := &in.VMState, &out.VMState
+		*out = new(VMState)
+		**out = **in
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.NextCapacityCheckTime != nil {
+		in, out := &in.NextCapacityCheckTime, &out.NextCapacityCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIVirtualMachineStatus.
+func (in *AzureStackHCIVirtualMachineStatus) DeepCopy() *AzureStackHCIVirtualMachineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureStackHCIVirtualMachineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapacity) DeepCopyInto(out *ClusterCapacity) {
+	*out = *in
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.VMSizes != nil {
+		in, out := &in.VMSizes, &out.VMSizes
+		*out = make([]VMSizeCapacity, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapacity.
+func (in *ClusterCapacity) DeepCopy() *ClusterCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureDomainSpec) DeepCopyInto(out *FailureDomainSpec) {
+	*out = *in
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureDomainSpec.
+func (in *FailureDomainSpec) DeepCopy() *FailureDomainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureDomainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Image) DeepCopyInto(out *Image) {
+	*out = *in
+	if in.Publisher != nil {
+		in, out := &in.Publisher, &out.Publisher
+		*out = new(string)
+		**out = **in
+	}
+	if in.Offer != nil {
+		in, out := &in.Offer, &out.Offer
+		*out = new(string)
+		**out = **in
+	}
+	if in.SKU != nil {
+		in, out := &in.SKU, &out.SKU
+		*out = new(string)
+		**out = **in
+	}
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.SubscriptionID != nil {
+		in, out := &in.SubscriptionID, &out.SubscriptionID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceGroup != nil {
+		in, out := &in.ResourceGroup, &out.ResourceGroup
+		*out = new(string)
+		**out = **in
+	}
+	if in.Gallery != nil {
+		in, out := &in.Gallery, &out.Gallery
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
+func (in *Image) DeepCopy() *Image {
+	if in == nil {
+		return nil
+	}
+	out := new(Image)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSource) DeepCopyInto(out *ImageSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSource.
+func (in *ImageSource) DeepCopy() *ImageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IpConfigurationSpec) DeepCopyInto(out *IpConfigurationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IpConfigurationSpec.
+func (in *IpConfigurationSpec) DeepCopy() *IpConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IpConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in IpConfigurations) DeepCopyInto(out *IpConfigurations) {
+	{
+		in := &in
+		*out = make(IpConfigurations, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(IpConfigurationSpec)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IpConfigurations.
+func (in IpConfigurations) DeepCopy() IpConfigurations {
+	if in == nil {
+		return nil
+	}
+	out := new(IpConfigurations)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerAutoscaling) DeepCopyInto(out *LoadBalancerAutoscaling) {
+	*out = *in
+	if in.ScaleUpStabilizationWindow != nil {
+		in, out := &in.ScaleUpStabilizationWindow, &out.ScaleUpStabilizationWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerAutoscaling.
+func (in *LoadBalancerAutoscaling) DeepCopy() *LoadBalancerAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerAutoscalingStatus) DeepCopyInto(out *LoadBalancerAutoscalingStatus) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]LoadBalancerReplicaMetrics, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecommendedSince != nil {
+		in, out := &in.RecommendedSince, &out.RecommendedSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastMetricsTime != nil {
+		in, out := &in.LastMetricsTime, &out.LastMetricsTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerAutoscalingStatus.
+func (in *LoadBalancerAutoscalingStatus) DeepCopy() *LoadBalancerAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerHealthCheck) DeepCopyInto(out *LoadBalancerHealthCheck) {
+	*out = *in
+	if in.UnhealthyTimeout != nil {
+		in, out := &in.UnhealthyTimeout, &out.UnhealthyTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxUnhealthy != nil {
+		in, out := &in.MaxUnhealthy, &out.MaxUnhealthy
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerHealthCheck.
+func (in *LoadBalancerHealthCheck) DeepCopy() *LoadBalancerHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerListener) DeepCopyInto(out *LoadBalancerListener) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]LoadBalancingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]LoadBalancerProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerListener.
+func (in *LoadBalancerListener) DeepCopy() *LoadBalancerListener {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerListener)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerListenerStatus) DeepCopyInto(out *LoadBalancerListenerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerListenerStatus.
+func (in *LoadBalancerListenerStatus) DeepCopy() *LoadBalancerListenerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureStackHCIVirtualMachineSpec)
+	out := new(LoadBalancerListenerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AzureStackHCIVirtualMachineStatus) DeepCopyInto(out *AzureStackHCIVirtualMachineStatus) {
+func (in *LoadBalancerPlacement) DeepCopyInto(out *LoadBalancerPlacement) {
 	*out = *in
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]corev1.NodeAddress, len(*in))
-		copy(*out, *in)
-	}
-	if in.VMState != nil {
-		in, out := &in.VMState, &out.VMState
-		*out = new(VMState)
+	if in.PlatformFaultDomainCount != nil {
+		in, out := &in.PlatformFaultDomainCount, &out.PlatformFaultDomainCount
+		*out = new(int32)
 		**out = **in
 	}
-	if in.FailureReason != nil {
-		in, out := &in.FailureReason, &out.FailureReason
-		*out = new(errors.MachineStatusError)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerPlacement.
+func (in *LoadBalancerPlacement) DeepCopy() *LoadBalancerPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerProbe) DeepCopyInto(out *LoadBalancerProbe) {
+	*out = *in
+	if in.IntervalInSeconds != nil {
+		in, out := &in.IntervalInSeconds, &out.IntervalInSeconds
+		*out = new(int32)
 		**out = **in
 	}
-	if in.FailureMessage != nil {
-		in, out := &in.FailureMessage, &out.FailureMessage
-		*out = new(string)
+	if in.NumberOfProbes != nil {
+		in, out := &in.NumberOfProbes, &out.NumberOfProbes
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerProbe.
+func (in *LoadBalancerProbe) DeepCopy() *LoadBalancerProbe {
+	if in == nil {
+		return nil
 	}
+	out := new(LoadBalancerProbe)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureStackHCIVirtualMachineStatus.
-func (in *AzureStackHCIVirtualMachineStatus) DeepCopy() *AzureStackHCIVirtualMachineStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerReplicaMetrics) DeepCopyInto(out *LoadBalancerReplicaMetrics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerReplicaMetrics.
+func (in *LoadBalancerReplicaMetrics) DeepCopy() *LoadBalancerReplicaMetrics {
 	if in == nil {
 		return nil
 	}
-	out := new(AzureStackHCIVirtualMachineStatus)
+	out := new(LoadBalancerReplicaMetrics)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Image) DeepCopyInto(out *Image) {
+func (in *LoadBalancerRollingUpdate) DeepCopyInto(out *LoadBalancerRollingUpdate) {
 	*out = *in
-	if in.Publisher != nil {
-		in, out := &in.Publisher, &out.Publisher
-		*out = new(string)
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
 		**out = **in
 	}
-	if in.Offer != nil {
-		in, out := &in.Offer, &out.Offer
-		*out = new(string)
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
 		**out = **in
 	}
-	if in.SKU != nil {
-		in, out := &in.SKU, &out.SKU
-		*out = new(string)
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.ID != nil {
-		in, out := &in.ID, &out.ID
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerRollingUpdate.
+func (in *LoadBalancerRollingUpdate) DeepCopy() *LoadBalancerRollingUpdate {
+	if in == nil {
+		return nil
 	}
-	if in.SubscriptionID != nil {
-		in, out := &in.SubscriptionID, &out.SubscriptionID
-		*out = new(string)
-		**out = **in
+	out := new(LoadBalancerRollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerStrategy) DeepCopyInto(out *LoadBalancerStrategy) {
+	*out = *in
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(LoadBalancerRollingUpdate)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.ResourceGroup != nil {
-		in, out := &in.ResourceGroup, &out.ResourceGroup
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerStrategy.
+func (in *LoadBalancerStrategy) DeepCopy() *LoadBalancerStrategy {
+	if in == nil {
+		return nil
 	}
-	if in.Gallery != nil {
-		in, out := &in.Gallery, &out.Gallery
-		*out = new(string)
-		**out = **in
+	out := new(LoadBalancerStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerUpgradeStatus) DeepCopyInto(out *LoadBalancerUpgradeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Version != nil {
-		in, out := &in.Version, &out.Version
-		*out = new(string)
+	if in.PreviousImage != nil {
+		in, out := &in.PreviousImage, &out.PreviousImage
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerUpgradeStatus.
+func (in *LoadBalancerUpgradeStatus) DeepCopy() *LoadBalancerUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancingRule) DeepCopyInto(out *LoadBalancingRule) {
+	*out = *in
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Image.
-func (in *Image) DeepCopy() *Image {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancingRule.
+func (in *LoadBalancingRule) DeepCopy() *LoadBalancingRule {
 	if in == nil {
 		return nil
 	}
-	out := new(Image)
+	out := new(LoadBalancingRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IpConfigurationSpec) DeepCopyInto(out *IpConfigurationSpec) {
+func (in *MachinePoolRollingUpdate) DeepCopyInto(out *MachinePoolRollingUpdate) {
 	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IpConfigurationSpec.
-func (in *IpConfigurationSpec) DeepCopy() *IpConfigurationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolRollingUpdate.
+func (in *MachinePoolRollingUpdate) DeepCopy() *MachinePoolRollingUpdate {
 	if in == nil {
 		return nil
 	}
-	out := new(IpConfigurationSpec)
+	out := new(MachinePoolRollingUpdate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in IpConfigurations) DeepCopyInto(out *IpConfigurations) {
-	{
-		in := &in
-		*out = make(IpConfigurations, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(IpConfigurationSpec)
-				**out = **in
-			}
-		}
+func (in *MachinePoolStrategy) DeepCopyInto(out *MachinePoolStrategy) {
+	*out = *in
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(MachinePoolRollingUpdate)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IpConfigurations.
-func (in IpConfigurations) DeepCopy() IpConfigurations {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolStrategy.
+func (in *MachinePoolStrategy) DeepCopy() *MachinePoolStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(IpConfigurations)
+	out := new(MachinePoolStrategy)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -1013,7 +2115,7 @@ func (in NetworkInterfaces) DeepCopy() NetworkInterfaces {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 	*out = *in
-	out.Vnet = in.Vnet
+	in.Vnet.DeepCopyInto(&out.Vnet)
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
 		*out = make(Subnets, len(*in))
@@ -1021,7 +2123,7 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(SubnetSpec)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -1037,6 +2139,21 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInfo) DeepCopyInto(out *NodeInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInfo.
+func (in *NodeInfo) DeepCopy() *NodeInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OSDisk) DeepCopyInto(out *OSDisk) {
 	*out = *in
@@ -1057,9 +2174,89 @@ func (in *OSDisk) DeepCopy() *OSDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementGroupSpec) DeepCopyInto(out *PlacementGroupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementGroupSpec.
+func (in *PlacementGroupSpec) DeepCopy() *PlacementGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementStatus) DeepCopyInto(out *PlacementStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementStatus.
+func (in *PlacementStatus) DeepCopy() *PlacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationStrategy) DeepCopyInto(out *RemediationStrategy) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RemediationStep, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryLimit != nil {
+		in, out := &in.RetryLimit, &out.RetryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStrategy.
+func (in *RemediationStrategy) DeepCopy() *RemediationStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageContainerSpec) DeepCopyInto(out *StorageContainerSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageContainerSpec.
+func (in *StorageContainerSpec) DeepCopy() *StorageContainerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageContainerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 	*out = *in
+	if in.CidrBlocks != nil {
+		in, out := &in.CidrBlocks, &out.CidrBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetSpec.
@@ -1081,7 +2278,7 @@ func (in Subnets) DeepCopyInto(out *Subnets) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(SubnetSpec)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -1114,6 +2311,51 @@ func (in *VM) DeepCopy() *VM {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMCustomSize) DeepCopyInto(out *VMCustomSize) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMCustomSize.
+func (in *VMCustomSize) DeepCopy() *VMCustomSize {
+	if in == nil {
+		return nil
+	}
+	out := new(VMCustomSize)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMSizeCapacity) DeepCopyInto(out *VMSizeCapacity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMSizeCapacity.
+func (in *VMSizeCapacity) DeepCopy() *VMSizeCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(VMSizeCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VipPoolSpec) DeepCopyInto(out *VipPoolSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VipPoolSpec.
+func (in *VipPoolSpec) DeepCopy() *VipPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VipPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in VirtualMachinesByCreationTimestamp) DeepCopyInto(out *VirtualMachinesByCreationTimestamp) {
 	{
@@ -1142,6 +2384,11 @@ func (in VirtualMachinesByCreationTimestamp) DeepCopy() VirtualMachinesByCreatio
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetSpec) DeepCopyInto(out *VnetSpec) {
 	*out = *in
+	if in.CidrBlocks != nil {
+		in, out := &in.CidrBlocks, &out.CidrBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VnetSpec.
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	"github.com/microsoft/moc/pkg/status"
+	wssdcommon "github.com/microsoft/moc/rpc/common"
+)
+
+// SDKToImageState converts the statuses of an SDK GalleryImage to its import state and download progress.
+func SDKToImageState(image compute.GalleryImage) (infrav1.ImageState, int64) {
+	if image.GalleryImageProperties == nil {
+		return infrav1.ImageStateSucceeded, 0
+	}
+
+	imageStatus := status.GetFromStatuses(image.Statuses)
+	progress := imageStatus.GetDownloadStatus().GetProgressPercentage()
+	switch imageStatus.GetProvisioningStatus().GetCurrentState() {
+	case wssdcommon.ProvisionState_CREATING, wssdcommon.ProvisionState_IMPORTING,
+		wssdcommon.ProvisionState_PROVISIONING, wssdcommon.ProvisionState_UPDATING:
+		return infrav1.ImageStateImporting, progress
+	case wssdcommon.ProvisionState_CREATE_FAILED, wssdcommon.ProvisionState_IMPORT_FAILED,
+		wssdcommon.ProvisionState_PROVISION_FAILED, wssdcommon.ProvisionState_UPDATE_FAILED:
+		return infrav1.ImageStateFailed, progress
+	case wssdcommon.ProvisionState_DELETING, wssdcommon.ProvisionState_DELETE_PENDING:
+		return infrav1.ImageStateDeleting, progress
+	default:
+		// older cloud agents don't report the provisioning state of gallery images, an image that
+		// exists is usable
+		return infrav1.ImageStateSucceeded, progress
+	}
+}
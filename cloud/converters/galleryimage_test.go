@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	"github.com/microsoft/moc/pkg/status"
+	wssdcommon "github.com/microsoft/moc/rpc/common"
+	. "github.com/onsi/gomega"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+// TestSDKToImageState verifies that the provisioning state reported by the cloud agent is converted to the
+// import state of the gallery image.
+func TestSDKToImageState(t *testing.T) {
+	tests := []struct {
+		name         string
+		state        wssdcommon.ProvisionState
+		progress     int64
+		wantState    infrav1.ImageState
+		wantProgress int64
+	}{
+		{name: "importing", state: wssdcommon.ProvisionState_IMPORTING, progress: 42, wantState: infrav1.ImageStateImporting, wantProgress: 42},
+		{name: "creating", state: wssdcommon.ProvisionState_CREATING, wantState: infrav1.ImageStateImporting},
+		{name: "created", state: wssdcommon.ProvisionState_CREATED, progress: 100, wantState: infrav1.ImageStateSucceeded, wantProgress: 100},
+		{name: "imported", state: wssdcommon.ProvisionState_IMPORTED, wantState: infrav1.ImageStateSucceeded},
+		{name: "import failed", state: wssdcommon.ProvisionState_IMPORT_FAILED, wantState: infrav1.ImageStateFailed},
+		{name: "deleting", state: wssdcommon.ProvisionState_DELETING, wantState: infrav1.ImageStateDeleting},
+		{name: "state not reported", state: wssdcommon.ProvisionState_UNKNOWN, wantState: infrav1.ImageStateSucceeded},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			imageStatus := status.InitStatus()
+			imageStatus.ProvisioningStatus = &wssdcommon.ProvisionStatus{CurrentState: tc.state}
+			imageStatus.DownloadStatus = &wssdcommon.DownloadStatus{ProgressPercentage: tc.progress}
+			image := compute.GalleryImage{
+				GalleryImageProperties: &compute.GalleryImageProperties{
+					Statuses: status.GetStatuses(imageStatus),
+				},
+			}
+			state, progress := SDKToImageState(image)
+			g.Expect(state).To(Equal(tc.wantState))
+			g.Expect(progress).To(Equal(tc.wantProgress))
+		})
+	}
+}
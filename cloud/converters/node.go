@@ -0,0 +1,42 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/microsoft/moc-sdk-for-go/services/cloud"
+	wssdcommon "github.com/microsoft/moc/rpc/common"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// SDKToNodeInfo converts the info reported in the statuses of an SDK Node, which include the resources
+// available on the host.
+func SDKToNodeInfo(node cloud.Node) (*wssdcommon.NodeInfo, error) {
+	if node.NodeProperties == nil || node.Statuses["Info"] == nil {
+		return nil, errors.Errorf("node %s doesn't report its info", to.String(node.Name))
+	}
+
+	// the moc messages are generated with the legacy protobuf API, which prototext reaches through an adapter
+	info := &wssdcommon.NodeInfo{}
+	if err := prototext.Unmarshal([]byte(*node.Statuses["Info"]), protoadapt.MessageV2Of(info)); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the info of node %s", to.String(node.Name))
+	}
+	return info, nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/microsoft/moc-sdk-for-go/services/cloud"
+	wssdcommon "github.com/microsoft/moc/rpc/common"
+	. "github.com/onsi/gomega"
+)
+
+// TestSDKToNodeInfo verifies that the resources available on a host are parsed from the info the cloud agent reports.
+func TestSDKToNodeInfo(t *testing.T) {
+	g := NewWithT(t)
+
+	reported := &wssdcommon.NodeInfo{
+		Name: "host-1",
+		Availability: &wssdcommon.Resources{
+			Processor: &wssdcommon.Processor{Logicalprocessors: 24},
+			Memory:    &wssdcommon.PhysicalMemory{SizeBytes: 64 * 1024 * 1024 * 1024},
+			HostGPUs:  []*wssdcommon.HostGPU{{Id: "gpu-1"}},
+		},
+	}
+	node := cloud.Node{
+		Name: to.StringPtr("host-1"),
+		NodeProperties: &cloud.NodeProperties{
+			Statuses: map[string]*string{"Info": to.StringPtr(reported.String())},
+		},
+	}
+
+	info, err := SDKToNodeInfo(node)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(info.GetAvailability().GetProcessor().GetLogicalprocessors()).To(Equal(uint32(24)))
+	g.Expect(info.GetAvailability().GetMemory().GetSizeBytes()).To(Equal(uint64(64 * 1024 * 1024 * 1024)))
+	g.Expect(info.GetAvailability().GetHostGPUs()).To(HaveLen(1))
+
+	_, err = SDKToNodeInfo(cloud.Node{Name: to.StringPtr("host-2"), NodeProperties: &cloud.NodeProperties{}})
+	g.Expect(err).To(HaveOccurred())
+}
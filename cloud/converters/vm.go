@@ -30,5 +30,29 @@ func SDKToVM(v compute.VirtualMachine) (*infrav1.VM, error) {
 		Name:  to.String(v.Name),
 		State: infrav1.VMStateSucceeded, // Hard-coded for now until we expose provisioning state
 	}
+	if v.VirtualMachineProperties != nil && v.Host != nil {
+		vm.Host = to.String(v.Host.ID)
+	}
+	if v.VirtualMachineProperties != nil {
+		vm.PowerState = SDKToVMPowerState(to.String(v.Statuses["PowerState"]))
+	}
 	return vm, nil
 }
+
+// SDKToVMPowerState converts the power state reported by the cloud agent.
+func SDKToVMPowerState(powerState string) infrav1.VMPowerState {
+	switch powerState {
+	case "Running":
+		return infrav1.VMPowerStateRunning
+	case "Off":
+		return infrav1.VMPowerStateStopped
+	case "Paused":
+		return infrav1.VMPowerStatePaused
+	case "Saved":
+		return infrav1.VMPowerStateSaved
+	case "Critical":
+		return infrav1.VMPowerStateCritical
+	default:
+		return infrav1.VMPowerStateUnknown
+	}
+}
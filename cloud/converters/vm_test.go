@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	. "github.com/onsi/gomega"
+)
+
+// TestSDKToVMHost verifies that the host the virtual machine runs on is reported when the cloud agent returns it.
+func TestSDKToVMHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		vm       compute.VirtualMachine
+		wantHost string
+	}{
+		{
+			name: "no properties",
+			vm:   compute.VirtualMachine{Name: to.StringPtr("vm")},
+		},
+		{
+			name: "host not reported",
+			vm:   compute.VirtualMachine{Name: to.StringPtr("vm"), VirtualMachineProperties: &compute.VirtualMachineProperties{}},
+		},
+		{
+			name: "host reported",
+			vm: compute.VirtualMachine{
+				Name:                     to.StringPtr("vm"),
+				VirtualMachineProperties: &compute.VirtualMachineProperties{Host: &compute.SubResource{ID: to.StringPtr("node-1")}},
+			},
+			wantHost: "node-1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			vm, err := SDKToVM(tc.vm)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(vm.Name).To(Equal("vm"))
+			g.Expect(vm.Host).To(Equal(tc.wantHost))
+		})
+	}
+}
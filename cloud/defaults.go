@@ -19,6 +19,7 @@ package azurestackhci
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/blang/semver"
 	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
@@ -42,6 +43,19 @@ const (
 	DefaultNodeSubnetCIDR = "10.1.0.0/16"
 	// DefaultInternalLBIPAddress is the default internal load balancer ip address
 	DefaultInternalLBIPAddress = "10.0.0.100"
+	// DefaultMaxCapacityRetries is the number of creations failing for lack of capacity before the failure is terminal
+	DefaultMaxCapacityRetries = 5
+	// DefaultCapacityBackoff is the delay before checking the capacity again after the first lack of capacity
+	DefaultCapacityBackoff = 30 * time.Second
+	// DefaultMaxCapacityBackoff is the maximum delay between two capacity checks
+	DefaultMaxCapacityBackoff = 10 * time.Minute
+	// DefaultImageNotFoundTimeout is how long a virtual machine waits for its gallery image to be imported before
+	// the missing image is a terminal failure, like any path MOC doesn't find.
+	DefaultImageNotFoundTimeout = 15 * time.Minute
+	// DefaultMOCUnreachableRetryInterval is the delay before retrying a call to MOC that failed because MOC was unreachable
+	DefaultMOCUnreachableRetryInterval = 30 * time.Second
+	// DefaultPlatformFaultDomainCount is the default number of fault domains of a provider created availability set
+	DefaultPlatformFaultDomainCount = 2
 	// DefaultAzureStackHCIDNSZone is the default provided azurestackhci dns zone
 	DefaultAzureStackHCIDNSZone = "cloudapp.azurestackhci.com"
 	// UserAgent used for communicating with azurestackhci
@@ -67,26 +81,18 @@ const (
 	LBRoleAksHciApiServer = "AKSHCI_APISERVER"
 )
 
-// SupportedAvailabilityZoneLocations is a slice of the locations where Availability Zones are supported.
-// This is used to validate whether a virtual machine should leverage an Availability Zone.
-// Based on the Availability Zones listed in https://docs.microsoft.com/en-us/azure/availability-zones/az-overview
-var SupportedAvailabilityZoneLocations = []string{
-	// Americas
-	"centralus",
-	"eastus",
-	"eastus2",
-	"westus2",
-
-	// Europe
-	"francecentral",
-	"northeurope",
-	"uksouth",
-	"westeurope",
-
-	// Asia Pacific
-	"japaneast",
-	"southeastasia",
-}
+const (
+	// APIServerRuleName is the name of the load balancing rule for the api server.
+	APIServerRuleName = "apiserver"
+	// APIServerProbeName is the name of the health probe for the api server.
+	APIServerProbeName = "apiserver-readyz"
+	// DefaultAPIServerProbePath is the request path probed on the api server.
+	DefaultAPIServerProbePath = "/readyz"
+	// DefaultProbeIntervalInSeconds is the default interval between health probes.
+	DefaultProbeIntervalInSeconds = 5
+	// DefaultProbeThreshold is the default number of failed probes before a backend is taken out of rotation.
+	DefaultProbeThreshold = 2
+)
 
 // GenerateVnetName generates a virtual network name, based on the cluster name.
 func GenerateVnetName(clusterName string) string {
@@ -152,6 +158,30 @@ func GenerateAzureStackHCILoadBalancerMachineName(loadBalancerName string) (stri
 	return fmt.Sprintf("%s-%s", loadBalancerName, randomString), nil
 }
 
+// GenerateAzureStackHCILoadBalancerListenerName generates the name of the load balancer serving a listener.
+func GenerateAzureStackHCILoadBalancerListenerName(loadBalancerName, listenerName string) string {
+	return fmt.Sprintf("%s-%s", loadBalancerName, listenerName)
+}
+
+// GenerateAzureStackHCILoadBalancerIPv6Name generates the name of the load balancer serving the IPv6 frontend.
+func GenerateAzureStackHCILoadBalancerIPv6Name(loadBalancerName string) string {
+	return fmt.Sprintf("%s-ipv6", loadBalancerName)
+}
+
+// GenerateAzureStackHCIMachinePoolMachineName generates the name of a machine pool virtual machine based on the name of the machine pool.
+func GenerateAzureStackHCIMachinePoolMachineName(machinePoolName string) (string, error) {
+	randomString, err := infrav1util.RandomAlphaNumericString(5)
+	if err != nil {
+		return machinePoolName, err
+	}
+	return fmt.Sprintf("%s-%s", machinePoolName, randomString), nil
+}
+
+// GenerateAzureStackHCILoadBalancerAvailabilitySetName generates the name of the availability set of the load balancer replicas.
+func GenerateAzureStackHCILoadBalancerAvailabilitySetName(loadBalancerName string) string {
+	return fmt.Sprintf("%s-avset", loadBalancerName)
+}
+
 // GenerateControlPlaneBackendPoolName generates the name of a control plane backend pool based on the name of a cluster.
 // This backend pool name should be used by the control plane only
 func GenerateControlPlaneBackendPoolName(clusterName string) string {
@@ -191,3 +221,13 @@ func GetDefaultImage(osType infrav1.OSType, k8sVersion string) (*infrav1.Image,
 
 	return defaultImage, nil
 }
+
+// CapacityBackoff returns the delay before checking the capacity again after the given number of consecutive
+// lacks of capacity. The delay doubles each time, up to DefaultMaxCapacityBackoff.
+func CapacityBackoff(retries int32) time.Duration {
+	backoff := DefaultCapacityBackoff
+	for i := int32(1); i < retries && backoff < DefaultMaxCapacityBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, DefaultMaxCapacityBackoff)
+}
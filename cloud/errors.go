@@ -18,10 +18,64 @@ limitations under the License.
 package azurestackhci
 
 import (
+	"time"
+
+	mocerrors "github.com/microsoft/moc/pkg/errors"
+	moccodes "github.com/microsoft/moc/pkg/errors/codes"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
 )
 
+// ErrorClass describes how a controller handles an error returned by MOC.
+type ErrorClass struct {
+	// Reason is the condition reason reporting the error.
+	Reason string
+	// Terminal is true when retrying can't fix the error without changing the spec, for example a missing image
+	// or an invalid vm size.
+	Terminal bool
+	// Capacity is true when the cloud lacks the resources for the request. The caller backs the retries off with
+	// CapacityBackoff, which depends on the number of consecutive failures.
+	Capacity bool
+	// RequeueAfter is the suggested delay before retrying, zero to retry with the default rate limiting.
+	RequeueAfter time.Duration
+}
+
+// ClassifyError maps an error returned by MOC to the way it's handled. Errors that aren't specific to MOC are
+// retryable and reported with the default reason.
+func ClassifyError(err error, defaultReason string) ErrorClass {
+	if err == nil {
+		return ErrorClass{}
+	}
+	// MOC matches legacy errors by their message, the context added by wrapping could match another error.
+	err = errors.Cause(err)
+
+	switch mocerrors.GetErrorCode(err) {
+	case moccodes.OutOfMemory.String():
+		return ErrorClass{Reason: infrav1.OutOfMemoryReason, Capacity: true}
+	case moccodes.OutOfCapacity.String():
+		return ErrorClass{Reason: infrav1.OutOfCapacityReason, Capacity: true}
+	case moccodes.OutOfNodeCapacity.String():
+		return ErrorClass{Reason: infrav1.OutOfNodeCapacityReason, Capacity: true}
+	case moccodes.InvalidInput.String(), moccodes.InvalidConfiguration.String():
+		return ErrorClass{Reason: infrav1.InvalidConfigurationReason, Terminal: true}
+	case moccodes.NotFound.String():
+		if mocerrors.IsPathNotFound(err) {
+			// the image or another path referenced by the spec doesn't exist
+			return ErrorClass{Reason: infrav1.PathNotFoundReason, Terminal: true}
+		}
+		return ErrorClass{Reason: infrav1.NotFoundReason}
+	}
+
+	// GetErrorCode doesn't inspect gRPC status codes, an unreachable MOC agent is reported with codes.Unavailable.
+	if mocerrors.IsGRPCUnavailable(err) {
+		return ErrorClass{Reason: infrav1.MOCUnreachableReason, RequeueAfter: DefaultMOCUnreachableRetryInterval}
+	}
+	return ErrorClass{Reason: defaultReason}
+}
+
 // ResourceNotFound parses the error to check if its a resource not found
 func ResourceNotFound(err error) bool {
 	if e, ok := status.FromError(err); ok && e.Code() == codes.NotFound {
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azurestackhci
+
+import (
+	"testing"
+
+	mocerrors "github.com/microsoft/moc/pkg/errors"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+// TestClassifyError verifies that only errors that can't be fixed by retrying are terminal, so that a
+// MachineHealthCheck doesn't replace Machines on transient errors, and that a delay is suggested for errors
+// that won't be fixed right away.
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name: "image not found",
+			err:  mocerrors.Wrap(mocerrors.PathNotFound, "image linux-1.30 not found"),
+			want: ErrorClass{Reason: infrav1.PathNotFoundReason, Terminal: true},
+		},
+		{
+			name: "invalid vm size",
+			err:  mocerrors.Wrap(mocerrors.InvalidInput, "vm size Standard_X is not supported"),
+			want: ErrorClass{Reason: infrav1.InvalidConfigurationReason, Terminal: true},
+		},
+		{
+			name: "invalid configuration",
+			err:  mocerrors.Wrap(mocerrors.InvalidConfiguration, "invalid storage container"),
+			want: ErrorClass{Reason: infrav1.InvalidConfigurationReason, Terminal: true},
+		},
+		{
+			name: "out of capacity is retried",
+			err:  mocerrors.Wrap(mocerrors.OutOfCapacity, "no host can fit the vm"),
+			want: ErrorClass{Reason: infrav1.OutOfCapacityReason, Capacity: true},
+		},
+		{
+			name: "out of node capacity is retried",
+			err:  mocerrors.Wrap(mocerrors.OutOfNodeCapacity, "node is full"),
+			want: ErrorClass{Reason: infrav1.OutOfNodeCapacityReason, Capacity: true},
+		},
+		{
+			name: "out of memory is retried",
+			err:  mocerrors.Wrap(mocerrors.OutOfMemory, "not enough memory"),
+			want: ErrorClass{Reason: infrav1.OutOfMemoryReason, Capacity: true},
+		},
+		{
+			name: "wrapped out of memory is retried",
+			err:  errors.Wrap(mocerrors.Wrap(mocerrors.OutOfMemory, "not enough memory"), "failed to create vm"),
+			want: ErrorClass{Reason: infrav1.OutOfMemoryReason, Capacity: true},
+		},
+		{
+			name: "not found is retried",
+			err:  mocerrors.Wrap(mocerrors.NotFound, "virtual network not found"),
+			want: ErrorClass{Reason: infrav1.NotFoundReason},
+		},
+		{
+			name: "moc unreachable is retried",
+			err:  status.Error(codes.Unavailable, "dial tcp: lookup moc-agent: no such host"),
+			want: ErrorClass{Reason: infrav1.MOCUnreachableReason, RequeueAfter: DefaultMOCUnreachableRetryInterval},
+		},
+		{
+			name: "unknown error is retried",
+			err:  mocerrors.Wrap(mocerrors.Failed, "something went wrong"),
+			want: ErrorClass{Reason: infrav1.VMProvisionFailedReason},
+		},
+		{
+			name: "non moc error is retried",
+			err:  errors.New("connection reset"),
+			want: ErrorClass{Reason: infrav1.VMProvisionFailedReason},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(ClassifyError(tc.err, infrav1.VMProvisionFailedReason)).To(Equal(tc.want))
+		})
+	}
+}
@@ -36,3 +36,17 @@ type GetterService interface {
 	Reconcile(ctx context.Context, spec interface{}) error
 	Delete(ctx context.Context, spec interface{}) error
 }
+
+// PowerService is implemented by services managing the power state of virtual machines.
+type PowerService interface {
+	Start(ctx context.Context, spec interface{}) error
+	Stop(ctx context.Context, spec interface{}) error
+	Shutdown(ctx context.Context, spec interface{}) error
+	Restart(ctx context.Context, spec interface{}) error
+	PowerCycle(ctx context.Context, spec interface{}) error
+}
+
+// PrecheckService is implemented by services checking that the cloud has the capacity for a resource before it is created.
+type PrecheckService interface {
+	Precheck(ctx context.Context, spec interface{}) (bool, string, error)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/go-logr/logr"
 	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
 	azhciauth "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/auth"
 	"github.com/microsoft/moc/pkg/auth"
 	"github.com/microsoft/moc/pkg/diagnostics"
@@ -134,6 +135,30 @@ func (s *ClusterScope) Vnet() *infrav1.VnetSpec {
 	return &s.AzureStackHCICluster.Spec.NetworkSpec.Vnet
 }
 
+// VnetCidrBlocks returns the CIDR blocks of the cluster Vnet, or the default CIDR if none are set.
+func (s *ClusterScope) VnetCidrBlocks() []string {
+	if cidrs := s.Vnet().GetCidrBlocks(); len(cidrs) > 0 {
+		return cidrs
+	}
+	return []string{azurestackhci.DefaultVnetCIDR}
+}
+
+// DualStackNetworkInterfaces returns the network interfaces used for virtual machines that do not configure
+// their own, with an IPv4 primary and an IPv6 ip configuration, or nil if the cluster Vnet is single-stack.
+func (s *ClusterScope) DualStackNetworkInterfaces() infrav1.NetworkInterfaces {
+	if !s.Vnet().IsIPv6Enabled() {
+		return nil
+	}
+	return infrav1.NetworkInterfaces{
+		{
+			IPConfigurations: infrav1.IpConfigurations{
+				{Primary: true, IPVersion: infrav1.IPv4},
+				{IPVersion: infrav1.IPv6},
+			},
+		},
+	}
+}
+
 // Subnets returns the cluster subnets.
 func (s *ClusterScope) Subnets() infrav1.Subnets {
 	return s.AzureStackHCICluster.Spec.NetworkSpec.Subnets
@@ -180,9 +205,21 @@ func (s *ClusterScope) PatchObject() error {
 		patch.WithOwnedConditions{Conditions: []string{
 			clusterv1.ReadyCondition,
 			infrav1.NetworkInfrastructureReadyCondition,
+			infrav1.ControlPlaneEndpointReadyCondition,
 		}})
 }
 
+// AddCapacityRetry counts a reconciliation that failed for lack of capacity and returns the number of consecutive failures.
+func (s *ClusterScope) AddCapacityRetry() int32 {
+	s.AzureStackHCICluster.Status.CapacityRetries++
+	return s.AzureStackHCICluster.Status.CapacityRetries
+}
+
+// ResetCapacityRetries resets the number of reconciliations that failed for lack of capacity.
+func (s *ClusterScope) ResetCapacityRetries() {
+	s.AzureStackHCICluster.Status.CapacityRetries = 0
+}
+
 // Close closes the current scope persisting the cluster configuration and status.
 func (s *ClusterScope) Close() error {
 	return s.PatchObject()
@@ -200,6 +237,21 @@ func (s *ClusterScope) AzureStackHCILoadBalancer() *infrav1.AzureStackHCILoadBal
 	return s.AzureStackHCICluster.Spec.AzureStackHCILoadBalancer
 }
 
+// FailureDomain returns the failure domain declared on the AzureStackHCICluster with the given name, nil if there is none.
+func (s *ClusterScope) FailureDomain(name string) *infrav1.FailureDomainSpec {
+	for i := range s.AzureStackHCICluster.Spec.FailureDomains {
+		if s.AzureStackHCICluster.Spec.FailureDomains[i].Name == name {
+			return &s.AzureStackHCICluster.Spec.FailureDomains[i]
+		}
+	}
+	return nil
+}
+
+// SetFailureDomains sets the AzureStackHCICluster failure domains.
+func (s *ClusterScope) SetFailureDomains(failureDomains []clusterv1.FailureDomain) {
+	s.AzureStackHCICluster.Status.FailureDomains = failureDomains
+}
+
 // GetNamespaceOrDefault returns the default namespace if given empty
 func GetNamespaceOrDefault(namespace string) string {
 	if namespace == "" {
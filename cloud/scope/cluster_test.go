@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+)
+
+func TestClusterScopeDualStack(t *testing.T) {
+	tests := []struct {
+		name           string
+		vnet           infrav1.VnetSpec
+		wantCidrBlocks []string
+		wantDualStack  bool
+	}{
+		{
+			name:           "default cidr",
+			wantCidrBlocks: []string{azurestackhci.DefaultVnetCIDR},
+		},
+		{
+			name:           "single-stack",
+			vnet:           infrav1.VnetSpec{CidrBlock: "10.0.0.0/16"},
+			wantCidrBlocks: []string{"10.0.0.0/16"},
+		},
+		{
+			name:           "dual-stack",
+			vnet:           infrav1.VnetSpec{CidrBlocks: []string{"10.0.0.0/16", "fd00::/64"}},
+			wantCidrBlocks: []string{"10.0.0.0/16", "fd00::/64"},
+			wantDualStack:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ClusterScope{
+				AzureStackHCICluster: &infrav1.AzureStackHCICluster{
+					Spec: infrav1.AzureStackHCIClusterSpec{NetworkSpec: infrav1.NetworkSpec{Vnet: tc.vnet}},
+				},
+			}
+			g.Expect(s.VnetCidrBlocks()).To(Equal(tc.wantCidrBlocks))
+
+			nics := s.DualStackNetworkInterfaces()
+			if !tc.wantDualStack {
+				g.Expect(nics).To(BeNil())
+				return
+			}
+			g.Expect(nics).To(HaveLen(1))
+			ipConfigs := nics[0].IPConfigurations
+			g.Expect(ipConfigs).To(HaveLen(2))
+			g.Expect(ipConfigs[0].Primary).To(BeTrue())
+			g.Expect(ipConfigs[0].IPVersion).To(Equal(infrav1.IPv4))
+			g.Expect(ipConfigs[1].Primary).To(BeFalse())
+			g.Expect(ipConfigs[1].IPVersion).To(Equal(infrav1.IPv6))
+		})
+	}
+}
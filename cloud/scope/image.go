@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azhciauth "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/auth"
+	"github.com/microsoft/moc/pkg/auth"
+	"github.com/microsoft/moc/pkg/diagnostics"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ImageScopeParams defines the input parameters used to create a new ImageScope.
+type ImageScopeParams struct {
+	AzureStackHCIClients
+	Client             client.Client
+	Logger             *logr.Logger
+	AzureStackHCIImage *infrav1.AzureStackHCIImage
+}
+
+// NewImageScope creates a new ImageScope from the supplied parameters. The cloud agent and the authorizer
+// of the clients are used when they are set, otherwise they come from the environment.
+// This is meant to be called for each reconcile iteration.
+func NewImageScope(params ImageScopeParams) (*ImageScope, error) {
+	if params.Client == nil {
+		return nil, errors.New("client is required when creating an ImageScope")
+	}
+
+	if params.AzureStackHCIImage == nil {
+		return nil, errors.New("azurestackhci image is required when creating an ImageScope")
+	}
+
+	if params.Logger == nil {
+		log := klogr.New()
+		params.Logger = &log
+	}
+
+	if params.AzureStackHCIClients.CloudAgentFqdn == "" {
+		agentFqdn := os.Getenv("AZURESTACKHCI_CLOUDAGENT_FQDN")
+		if agentFqdn == "" {
+			return nil, errors.New("error creating azurestackhci services. Environment variable AZURESTACKHCI_CLOUDAGENT_FQDN is not set")
+		}
+		params.AzureStackHCIClients.CloudAgentFqdn = agentFqdn
+	}
+
+	scopeContext := diagnostics.NewContextWithCorrelationId(context.Background(), params.AzureStackHCIImage.GetAnnotations()[infrav1.AzureCorrelationIDAnnotationKey])
+	if params.AzureStackHCIClients.Authorizer == nil {
+		authorizer, err := azhciauth.ReconcileAzureStackHCIAccess(scopeContext, *params.Logger, params.Client, params.AzureStackHCIClients.CloudAgentFqdn)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create azurestackhci session")
+		}
+		params.AzureStackHCIClients.Authorizer = authorizer
+	}
+
+	helper, err := patch.NewHelper(params.AzureStackHCIImage, params.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init patch helper")
+	}
+	return &ImageScope{
+		client:               params.Client,
+		AzureStackHCIImage:   params.AzureStackHCIImage,
+		AzureStackHCIClients: params.AzureStackHCIClients,
+		Logger:               *params.Logger,
+		patchHelper:          helper,
+		Context:              scopeContext,
+	}, nil
+}
+
+// ImageScope defines a scope defined around an image.
+type ImageScope struct {
+	logr.Logger
+	client      client.Client
+	patchHelper *patch.Helper
+	Context     context.Context
+
+	AzureStackHCIClients
+	AzureStackHCIImage *infrav1.AzureStackHCIImage
+}
+
+// GetResourceGroup allows ImageScope to fulfill ScopeInterface and thus to be used by the cloud services.
+// Gallery images belong to a location rather than a resource group.
+func (i *ImageScope) GetResourceGroup() string {
+	return ""
+}
+
+// GetCloudAgentFqdn returns the cloud agent fqdn string.
+func (i *ImageScope) GetCloudAgentFqdn() string {
+	return i.CloudAgentFqdn
+}
+
+// GetAuthorizer is a getter for the environment generated authorizer.
+func (i *ImageScope) GetAuthorizer() auth.Authorizer {
+	return i.Authorizer
+}
+
+// GetLogger returns the logger.
+func (i *ImageScope) GetLogger() logr.Logger {
+	return i.Logger
+}
+
+// GetCustomResourceTypeWithName return image resource string.
+func (i *ImageScope) GetCustomResourceTypeWithName() string {
+	return fmt.Sprintf("Image/%s/%s", i.Namespace(), i.Name())
+}
+
+// Name returns the AzureStackHCIImage name.
+func (i *ImageScope) Name() string {
+	return i.AzureStackHCIImage.Name
+}
+
+// Namespace returns the namespace name.
+func (i *ImageScope) Namespace() string {
+	return i.AzureStackHCIImage.Namespace
+}
+
+// GalleryImageName returns the name of the gallery image the source is imported as.
+func (i *ImageScope) GalleryImageName() string {
+	return i.AzureStackHCIImage.GalleryImageName()
+}
+
+// Location returns the location of the image gallery.
+func (i *ImageScope) Location() string {
+	return i.AzureStackHCIImage.Spec.Location
+}
+
+// SetState sets the AzureStackHCIImage import state and download progress.
+func (i *ImageScope) SetState(state infrav1.ImageState, progress int64) {
+	i.AzureStackHCIImage.Status.State = state
+	i.AzureStackHCIImage.Status.DownloadProgress = progress
+	i.AzureStackHCIImage.Status.Ready = state == infrav1.ImageStateSucceeded
+}
+
+// PatchObject persists the image spec and status.
+func (i *ImageScope) PatchObject() error {
+	return i.patchHelper.Patch(i.Context,
+		i.AzureStackHCIImage,
+		patch.WithOwnedConditions{Conditions: []string{
+			infrav1.ImageReadyCondition,
+		}})
+}
+
+// Close the ImageScope by updating the image spec, image status.
+func (i *ImageScope) Close() error {
+	return i.PatchObject()
+}
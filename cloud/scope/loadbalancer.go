@@ -19,11 +19,13 @@ package scope
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
 	"github.com/microsoft/moc/pkg/diagnostics"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
@@ -88,6 +90,18 @@ type LoadBalancerScope struct {
 const (
 	// The maximum number of Replicas that can be created above the desired amount.
 	MaxSurge = 1
+	// The maximum number of desired Replicas that can be unavailable during an upgrade.
+	MaxUnavailable = 0
+	// How long a Replica can be unhealthy before it is remediated.
+	DefaultUnhealthyTimeout = 10 * time.Minute
+	// How long a scale up has to be recommended by the autoscaler before it is applied.
+	DefaultScaleUpStabilizationWindow = time.Minute
+	// How long a scale down has to be recommended by the autoscaler before it is applied.
+	DefaultScaleDownStabilizationWindow = 5 * time.Minute
+	// How long an update can run while updated Replicas are not ready before it is rolled back.
+	DefaultProgressDeadline = 30 * time.Minute
+	// How long a Replica taken out of the loadbalancer service is kept for its connections to drain.
+	DefaultDrainTimeout = 5 * time.Minute
 )
 
 // Name returns the Name of the AzureStackHCILoadBalancer
@@ -100,14 +114,50 @@ func (l *LoadBalancerScope) Address() string {
 	return l.AzureStackHCILoadBalancer.Status.Address
 }
 
-// OSVersion returns the AzureStackHCILoadBalancer image OS version
+// OSVersion returns the AzureStackHCILoadBalancer image OS version the Replicas run.
+// This is the previous version while an update is rolled back.
 func (l *LoadBalancerScope) OSVersion() string {
-	if l.AzureStackHCILoadBalancer.Spec.Image != nil && l.AzureStackHCILoadBalancer.Spec.Image.Version != nil {
-		return *l.AzureStackHCILoadBalancer.Spec.Image.Version
+	if l.IsUpgradeRolledBack() {
+		return l.AzureStackHCILoadBalancer.Status.Upgrade.PreviousOSVersion
+	}
+	return l.TargetOSVersion()
+}
+
+// TargetOSVersion returns the AzureStackHCILoadBalancer image OS version requested by the spec.
+func (l *LoadBalancerScope) TargetOSVersion() string {
+	image := l.AzureStackHCILoadBalancer.Spec.Image
+	switch {
+	case image != nil && image.Version != nil:
+		return *image.Version
+	case image != nil && image.Name != nil && *image.Name != "":
+		// custom images are only updated when they carry a version
+		return ""
+	default:
+		return l.KubernetesVersion()
+	}
+}
+
+// KubernetesVersion returns the Kubernetes version of the default image of the Replicas.
+func (l *LoadBalancerScope) KubernetesVersion() string {
+	if l.AzureStackHCILoadBalancer.Spec.Version != nil {
+		return *l.AzureStackHCILoadBalancer.Spec.Version
+	}
+	if l.AzureStackHCICluster.Spec.Version != nil {
+		return *l.AzureStackHCICluster.Spec.Version
+	}
+	// clusters stamped from a ClusterClass follow the topology version
+	if l.Cluster != nil {
+		return l.Cluster.Spec.Topology.Version
 	}
 	return ""
 }
 
+// IsUpgradeRolledBack returns true if the Replicas were rolled back from the requested version.
+func (l *LoadBalancerScope) IsUpgradeRolledBack() bool {
+	upgrade := l.AzureStackHCILoadBalancer.Status.Upgrade
+	return upgrade != nil && upgrade.PreviousImage != nil && upgrade.FailedOSVersion != "" && upgrade.FailedOSVersion == l.TargetOSVersion()
+}
+
 // SetAnnotation sets a key value annotation on the AzureStackHCILoadBalancer
 func (l *LoadBalancerScope) SetAnnotation(key, value string) {
 	if l.AzureStackHCILoadBalancer.Annotations == nil {
@@ -126,6 +176,17 @@ func (l *LoadBalancerScope) Close() error {
 	return l.patchHelper.Patch(context.TODO(), l.AzureStackHCILoadBalancer)
 }
 
+// AddCapacityRetry counts a reconciliation that failed for lack of capacity and returns the number of consecutive failures.
+func (l *LoadBalancerScope) AddCapacityRetry() int32 {
+	l.AzureStackHCILoadBalancer.Status.CapacityRetries++
+	return l.AzureStackHCILoadBalancer.Status.CapacityRetries
+}
+
+// ResetCapacityRetries resets the number of reconciliations that failed for lack of capacity.
+func (l *LoadBalancerScope) ResetCapacityRetries() {
+	l.AzureStackHCILoadBalancer.Status.CapacityRetries = 0
+}
+
 // SetReady sets the AzureStackHCILoadBalancer Ready Status
 func (l *LoadBalancerScope) SetReady() {
 	l.AzureStackHCILoadBalancer.Status.Ready = true
@@ -186,9 +247,127 @@ func (l *LoadBalancerScope) GetDesiredReplicas() int32 {
 	return *l.AzureStackHCILoadBalancer.Spec.Replicas
 }
 
+// SetDesiredReplicas sets the AzureStackHCILoadBalancer spec.Replicas
+func (l *LoadBalancerScope) SetDesiredReplicas(replicas int32) {
+	l.AzureStackHCILoadBalancer.Spec.Replicas = &replicas
+}
+
 // GetMaxReplicas returns the maximum number of Replicas that can be created
 func (l *LoadBalancerScope) GetMaxReplicas() int32 {
-	return (l.GetDesiredReplicas() + MaxSurge)
+	return (l.GetDesiredReplicas() + l.GetMaxSurge())
+}
+
+// GetMaxSurge returns the number of Replicas that can be created above the desired amount during an upgrade.
+func (l *LoadBalancerScope) GetMaxSurge() int32 {
+	maxSurge, maxUnavailable := l.rollingUpdateValues()
+	if maxSurge == 0 && maxUnavailable == 0 {
+		return MaxSurge
+	}
+	return maxSurge
+}
+
+// GetMaxUnavailable returns the number of desired Replicas that can be unavailable during an upgrade.
+func (l *LoadBalancerScope) GetMaxUnavailable() int32 {
+	_, maxUnavailable := l.rollingUpdateValues()
+	return maxUnavailable
+}
+
+// rollingUpdateValues resolves the rolling update strategy against the desired Replicas.
+func (l *LoadBalancerScope) rollingUpdateValues() (maxSurge, maxUnavailable int32) {
+	strategy := l.AzureStackHCILoadBalancer.Spec.Strategy
+	if strategy == nil || strategy.RollingUpdate == nil {
+		return MaxSurge, MaxUnavailable
+	}
+
+	desired := int(l.GetDesiredReplicas())
+	surge, unavailable := MaxSurge, MaxUnavailable
+	if strategy.RollingUpdate.MaxSurge != nil {
+		if value, err := intstr.GetScaledValueFromIntOrPercent(strategy.RollingUpdate.MaxSurge, desired, true); err == nil {
+			surge = value
+		}
+	}
+	if strategy.RollingUpdate.MaxUnavailable != nil {
+		if value, err := intstr.GetScaledValueFromIntOrPercent(strategy.RollingUpdate.MaxUnavailable, desired, false); err == nil {
+			unavailable = min(value, desired)
+		}
+	}
+	// Assume that overflow will not happen for G115
+	return int32(max(surge, 0)), int32(max(unavailable, 0)) //nolint
+}
+
+// GetProgressDeadline returns how long an update can run while updated Replicas are not ready.
+func (l *LoadBalancerScope) GetProgressDeadline() time.Duration {
+	strategy := l.AzureStackHCILoadBalancer.Spec.Strategy
+	if strategy == nil || strategy.RollingUpdate == nil || strategy.RollingUpdate.ProgressDeadline == nil {
+		return DefaultProgressDeadline
+	}
+	return strategy.RollingUpdate.ProgressDeadline.Duration
+}
+
+// HealthCheckEnabled returns true if unhealthy Replicas are remediated.
+func (l *LoadBalancerScope) HealthCheckEnabled() bool {
+	return l.AzureStackHCILoadBalancer.Spec.HealthCheck != nil
+}
+
+// GetUnhealthyTimeout returns how long a Replica can be unhealthy before it is remediated.
+func (l *LoadBalancerScope) GetUnhealthyTimeout() time.Duration {
+	healthCheck := l.AzureStackHCILoadBalancer.Spec.HealthCheck
+	if healthCheck == nil || healthCheck.UnhealthyTimeout == nil {
+		return DefaultUnhealthyTimeout
+	}
+	return healthCheck.UnhealthyTimeout.Duration
+}
+
+// GetMaxUnhealthy returns the number of unhealthy Replicas up to which Replicas are remediated.
+func (l *LoadBalancerScope) GetMaxUnhealthy() int32 {
+	desired := l.GetDesiredReplicas()
+	healthCheck := l.AzureStackHCILoadBalancer.Spec.HealthCheck
+	if healthCheck == nil || healthCheck.MaxUnhealthy == nil {
+		return desired
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(healthCheck.MaxUnhealthy, int(desired), false)
+	if err != nil {
+		return 0
+	}
+	// Assume that overflow will not happen for G115
+	return int32(max(value, 0)) //nolint
+}
+
+// SetUnhealthyReplicas sets the AzureStackHCILoadBalancer UnhealthyReplicas and RemediationsAllowed status
+func (l *LoadBalancerScope) SetUnhealthyReplicas(replicas int32) {
+	l.AzureStackHCILoadBalancer.Status.UnhealthyReplicas = replicas
+	l.AzureStackHCILoadBalancer.Status.RemediationsAllowed = max(l.GetMaxUnhealthy()-replicas, 0)
+}
+
+// GetStabilizationWindow returns how long a scale up or down has to be recommended before it is applied.
+func (l *LoadBalancerScope) GetStabilizationWindow(scaleUp bool) time.Duration {
+	autoscaling := l.AzureStackHCILoadBalancer.Spec.Autoscaling
+	switch {
+	case scaleUp && autoscaling != nil && autoscaling.ScaleUpStabilizationWindow != nil:
+		return autoscaling.ScaleUpStabilizationWindow.Duration
+	case scaleUp:
+		return DefaultScaleUpStabilizationWindow
+	case autoscaling != nil && autoscaling.ScaleDownStabilizationWindow != nil:
+		return autoscaling.ScaleDownStabilizationWindow.Duration
+	default:
+		return DefaultScaleDownStabilizationWindow
+	}
+}
+
+// GetAutoscalingStatus returns the AzureStackHCILoadBalancer autoscaling status, initializing it if needed.
+func (l *LoadBalancerScope) GetAutoscalingStatus() *infrav1.LoadBalancerAutoscalingStatus {
+	if l.AzureStackHCILoadBalancer.Status.Autoscaling == nil {
+		l.AzureStackHCILoadBalancer.Status.Autoscaling = &infrav1.LoadBalancerAutoscalingStatus{}
+	}
+	return l.AzureStackHCILoadBalancer.Status.Autoscaling
+}
+
+// GetUpgradeStatus returns the AzureStackHCILoadBalancer upgrade status, initializing it if needed.
+func (l *LoadBalancerScope) GetUpgradeStatus() *infrav1.LoadBalancerUpgradeStatus {
+	if l.AzureStackHCILoadBalancer.Status.Upgrade == nil {
+		l.AzureStackHCILoadBalancer.Status.Upgrade = &infrav1.LoadBalancerUpgradeStatus{}
+	}
+	return l.AzureStackHCILoadBalancer.Status.Upgrade
 }
 
 // SetErrorMessage sets the AzureStackHCILoadBalancer status error message.
@@ -0,0 +1,310 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+func newLoadBalancerScope(spec infrav1.AzureStackHCILoadBalancerSpec) *LoadBalancerScope {
+	return &LoadBalancerScope{
+		AzureStackHCILoadBalancer: &infrav1.AzureStackHCILoadBalancer{Spec: spec},
+		AzureStackHCICluster:      &infrav1.AzureStackHCICluster{},
+	}
+}
+
+func TestLoadBalancerScopeRollingUpdate(t *testing.T) {
+	intOrString := func(v intstr.IntOrString) *intstr.IntOrString { return &v }
+	tests := []struct {
+		name               string
+		replicas           int32
+		rollingUpdate      *infrav1.LoadBalancerRollingUpdate
+		wantMaxSurge       int32
+		wantMaxUnavailable int32
+	}{
+		{
+			name:               "defaults",
+			replicas:           3,
+			wantMaxSurge:       MaxSurge,
+			wantMaxUnavailable: MaxUnavailable,
+		},
+		{
+			name:     "surge and unavailable of zero fall back to a surge of one",
+			replicas: 3,
+			rollingUpdate: &infrav1.LoadBalancerRollingUpdate{
+				MaxSurge:       intOrString(intstr.FromInt32(0)),
+				MaxUnavailable: intOrString(intstr.FromInt32(0)),
+			},
+			wantMaxSurge: 1,
+		},
+		{
+			name:     "replace in place",
+			replicas: 3,
+			rollingUpdate: &infrav1.LoadBalancerRollingUpdate{
+				MaxSurge:       intOrString(intstr.FromInt32(0)),
+				MaxUnavailable: intOrString(intstr.FromInt32(1)),
+			},
+			wantMaxUnavailable: 1,
+		},
+		{
+			name:     "percentages round surge up and unavailable down",
+			replicas: 3,
+			rollingUpdate: &infrav1.LoadBalancerRollingUpdate{
+				MaxSurge:       intOrString(intstr.FromString("50%")),
+				MaxUnavailable: intOrString(intstr.FromString("50%")),
+			},
+			wantMaxSurge:       2,
+			wantMaxUnavailable: 1,
+		},
+		{
+			name:     "unavailable is capped at the desired replicas",
+			replicas: 2,
+			rollingUpdate: &infrav1.LoadBalancerRollingUpdate{
+				MaxUnavailable: intOrString(intstr.FromInt32(5)),
+			},
+			wantMaxSurge:       MaxSurge,
+			wantMaxUnavailable: 2,
+		},
+		{
+			name:     "invalid percentage keeps the default",
+			replicas: 3,
+			rollingUpdate: &infrav1.LoadBalancerRollingUpdate{
+				MaxSurge: intOrString(intstr.FromString("many")),
+			},
+			wantMaxSurge: MaxSurge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			spec := infrav1.AzureStackHCILoadBalancerSpec{Replicas: ptr.To(tc.replicas)}
+			if tc.rollingUpdate != nil {
+				spec.Strategy = &infrav1.LoadBalancerStrategy{RollingUpdate: tc.rollingUpdate}
+			}
+			l := newLoadBalancerScope(spec)
+			g.Expect(l.GetMaxSurge()).To(Equal(tc.wantMaxSurge))
+			g.Expect(l.GetMaxUnavailable()).To(Equal(tc.wantMaxUnavailable))
+			g.Expect(l.GetMaxReplicas()).To(Equal(tc.replicas + tc.wantMaxSurge))
+		})
+	}
+}
+
+func TestLoadBalancerScopeHealthCheck(t *testing.T) {
+	tests := []struct {
+		name                    string
+		healthCheck             *infrav1.LoadBalancerHealthCheck
+		unhealthy               int32
+		wantEnabled             bool
+		wantTimeout             time.Duration
+		wantMaxUnhealthy        int32
+		wantRemediationsAllowed int32
+	}{
+		{
+			name:                    "disabled",
+			unhealthy:               1,
+			wantTimeout:             DefaultUnhealthyTimeout,
+			wantMaxUnhealthy:        4,
+			wantRemediationsAllowed: 3,
+		},
+		{
+			name:                    "defaults remediate every replica",
+			healthCheck:             &infrav1.LoadBalancerHealthCheck{},
+			wantEnabled:             true,
+			wantTimeout:             DefaultUnhealthyTimeout,
+			wantMaxUnhealthy:        4,
+			wantRemediationsAllowed: 4,
+		},
+		{
+			name: "percentage rounds down",
+			healthCheck: &infrav1.LoadBalancerHealthCheck{
+				UnhealthyTimeout: &metav1.Duration{Duration: time.Minute},
+				MaxUnhealthy:     ptr.To(intstr.FromString("40%")),
+			},
+			unhealthy:               1,
+			wantEnabled:             true,
+			wantTimeout:             time.Minute,
+			wantMaxUnhealthy:        1,
+			wantRemediationsAllowed: 0,
+		},
+		{
+			name: "budget exceeded",
+			healthCheck: &infrav1.LoadBalancerHealthCheck{
+				MaxUnhealthy: ptr.To(intstr.FromInt32(1)),
+			},
+			unhealthy:               3,
+			wantEnabled:             true,
+			wantTimeout:             DefaultUnhealthyTimeout,
+			wantMaxUnhealthy:        1,
+			wantRemediationsAllowed: 0,
+		},
+		{
+			name: "invalid percentage disables remediation",
+			healthCheck: &infrav1.LoadBalancerHealthCheck{
+				MaxUnhealthy: ptr.To(intstr.FromString("some")),
+			},
+			wantEnabled: true,
+			wantTimeout: DefaultUnhealthyTimeout,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			l := newLoadBalancerScope(infrav1.AzureStackHCILoadBalancerSpec{Replicas: ptr.To[int32](4), HealthCheck: tc.healthCheck})
+			g.Expect(l.HealthCheckEnabled()).To(Equal(tc.wantEnabled))
+			g.Expect(l.GetUnhealthyTimeout()).To(Equal(tc.wantTimeout))
+			g.Expect(l.GetMaxUnhealthy()).To(Equal(tc.wantMaxUnhealthy))
+
+			l.SetUnhealthyReplicas(tc.unhealthy)
+			g.Expect(l.AzureStackHCILoadBalancer.Status.UnhealthyReplicas).To(Equal(tc.unhealthy))
+			g.Expect(l.AzureStackHCILoadBalancer.Status.RemediationsAllowed).To(Equal(tc.wantRemediationsAllowed))
+		})
+	}
+}
+
+func TestLoadBalancerScopeStabilizationWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		autoscaling   *infrav1.LoadBalancerAutoscaling
+		wantScaleUp   time.Duration
+		wantScaleDown time.Duration
+	}{
+		{
+			name:          "defaults",
+			wantScaleUp:   DefaultScaleUpStabilizationWindow,
+			wantScaleDown: DefaultScaleDownStabilizationWindow,
+		},
+		{
+			name: "configured",
+			autoscaling: &infrav1.LoadBalancerAutoscaling{
+				ScaleUpStabilizationWindow:   &metav1.Duration{Duration: time.Second},
+				ScaleDownStabilizationWindow: &metav1.Duration{Duration: time.Hour},
+			},
+			wantScaleUp:   time.Second,
+			wantScaleDown: time.Hour,
+		},
+		{
+			name: "only scale down configured",
+			autoscaling: &infrav1.LoadBalancerAutoscaling{
+				ScaleDownStabilizationWindow: &metav1.Duration{Duration: time.Hour},
+			},
+			wantScaleUp:   DefaultScaleUpStabilizationWindow,
+			wantScaleDown: time.Hour,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			l := newLoadBalancerScope(infrav1.AzureStackHCILoadBalancerSpec{Autoscaling: tc.autoscaling})
+			g.Expect(l.GetStabilizationWindow(true)).To(Equal(tc.wantScaleUp))
+			g.Expect(l.GetStabilizationWindow(false)).To(Equal(tc.wantScaleDown))
+		})
+	}
+}
+
+func TestLoadBalancerScopeOSVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           infrav1.AzureStackHCILoadBalancerSpec
+		clusterVersion *string
+		upgrade        *infrav1.LoadBalancerUpgradeStatus
+		wantTarget     string
+		wantOSVersion  string
+		wantRolledBack bool
+	}{
+		{
+			name:          "image version",
+			spec:          infrav1.AzureStackHCILoadBalancerSpec{Image: &infrav1.Image{Version: ptr.To("1.0.0")}, Version: ptr.To("v1.30.0")},
+			wantTarget:    "1.0.0",
+			wantOSVersion: "1.0.0",
+		},
+		{
+			name:       "custom image without version",
+			spec:       infrav1.AzureStackHCILoadBalancerSpec{Image: &infrav1.Image{Name: ptr.To("custom")}, Version: ptr.To("v1.30.0")},
+			wantTarget: "",
+		},
+		{
+			name:          "loadbalancer kubernetes version",
+			spec:          infrav1.AzureStackHCILoadBalancerSpec{Version: ptr.To("v1.30.0")},
+			wantTarget:    "v1.30.0",
+			wantOSVersion: "v1.30.0",
+		},
+		{
+			name:           "cluster kubernetes version",
+			clusterVersion: ptr.To("v1.29.0"),
+			wantTarget:     "v1.29.0",
+			wantOSVersion:  "v1.29.0",
+		},
+		{
+			name: "rolled back",
+			spec: infrav1.AzureStackHCILoadBalancerSpec{Version: ptr.To("v1.30.0")},
+			upgrade: &infrav1.LoadBalancerUpgradeStatus{
+				FailedOSVersion:   "v1.30.0",
+				PreviousOSVersion: "v1.29.0",
+				PreviousImage:     &infrav1.Image{Name: ptr.To("previous")},
+			},
+			wantTarget:     "v1.30.0",
+			wantOSVersion:  "v1.29.0",
+			wantRolledBack: true,
+		},
+		{
+			name: "new version after a rollback",
+			spec: infrav1.AzureStackHCILoadBalancerSpec{Version: ptr.To("v1.31.0")},
+			upgrade: &infrav1.LoadBalancerUpgradeStatus{
+				FailedOSVersion:   "v1.30.0",
+				PreviousOSVersion: "v1.29.0",
+				PreviousImage:     &infrav1.Image{Name: ptr.To("previous")},
+			},
+			wantTarget:    "v1.31.0",
+			wantOSVersion: "v1.31.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			l := newLoadBalancerScope(tc.spec)
+			l.AzureStackHCICluster.Spec.Version = tc.clusterVersion
+			l.AzureStackHCILoadBalancer.Status.Upgrade = tc.upgrade
+			g.Expect(l.TargetOSVersion()).To(Equal(tc.wantTarget))
+			g.Expect(l.OSVersion()).To(Equal(tc.wantOSVersion))
+			g.Expect(l.IsUpgradeRolledBack()).To(Equal(tc.wantRolledBack))
+		})
+	}
+}
+
+func TestLoadBalancerScopeProgressDeadline(t *testing.T) {
+	g := NewWithT(t)
+	l := newLoadBalancerScope(infrav1.AzureStackHCILoadBalancerSpec{})
+	g.Expect(l.GetProgressDeadline()).To(Equal(DefaultProgressDeadline))
+
+	l.AzureStackHCILoadBalancer.Spec.Strategy = &infrav1.LoadBalancerStrategy{
+		RollingUpdate: &infrav1.LoadBalancerRollingUpdate{ProgressDeadline: &metav1.Duration{Duration: time.Hour}},
+	}
+	g.Expect(l.GetProgressDeadline()).To(Equal(time.Hour))
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/utils/pointer"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -117,6 +118,16 @@ func (m *MachineScope) AvailabilityZone() string {
 	return *m.AzureStackHCIMachine.Spec.AvailabilityZone.ID
 }
 
+// FailureDomain returns the failure domain the Machine was assigned to, empty if there is none.
+func (m *MachineScope) FailureDomain() string {
+	return m.Machine.Spec.FailureDomain
+}
+
+// SetFailureDomain sets the AzureStackHCIMachine failure domain.
+func (m *MachineScope) SetFailureDomain(v string) {
+	m.AzureStackHCIMachine.Status.FailureDomain = v
+}
+
 // Name returns the AzureStackHCIMachine name.
 func (m *MachineScope) Name() string {
 	return m.AzureStackHCIMachine.Name
@@ -174,6 +185,11 @@ func (m *MachineScope) SetVMState(v *infrav1.VMState) {
 	*m.AzureStackHCIMachine.Status.VMState = *v
 }
 
+// SetAddresses sets the AzureStackHCIMachine addresses.
+func (m *MachineScope) SetAddresses(addresses []corev1.NodeAddress) {
+	m.AzureStackHCIMachine.Status.Addresses = addresses
+}
+
 // SetReady sets the AzureStackHCIMachine Ready Status
 func (m *MachineScope) SetReady() {
 	m.AzureStackHCIMachine.Status.Ready = true
@@ -188,6 +204,16 @@ func (m *MachineScope) SetReady() {
 	}
 }
 
+// SetFailureReason sets the AzureStackHCIMachine status failure reason.
+func (m *MachineScope) SetFailureReason(v *capierrors.MachineStatusError) {
+	m.AzureStackHCIMachine.Status.FailureReason = v
+}
+
+// SetFailureMessage sets the AzureStackHCIMachine status failure message.
+func (m *MachineScope) SetFailureMessage(v *string) {
+	m.AzureStackHCIMachine.Status.FailureMessage = v
+}
+
 // SetAnnotation sets a key value annotation on the AzureStackHCIMachine.
 func (m *MachineScope) SetAnnotation(key, value string) {
 	if m.AzureStackHCIMachine.Annotations == nil {
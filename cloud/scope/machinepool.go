@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/go-logr/logr"
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/moc/pkg/diagnostics"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MachinePoolScopeParams defines the input parameters used to create a new MachinePoolScope.
+type MachinePoolScopeParams struct {
+	Client                   client.Client
+	Logger                   *logr.Logger
+	Cluster                  *clusterv1.Cluster
+	MachinePool              *clusterv1.MachinePool
+	AzureStackHCICluster     *infrav1.AzureStackHCICluster
+	AzureStackHCIMachinePool *infrav1.AzureStackHCIMachinePool
+}
+
+// NewMachinePoolScope creates a new MachinePoolScope from the supplied parameters.
+// This is meant to be called for each reconcile iteration.
+func NewMachinePoolScope(params MachinePoolScopeParams) (*MachinePoolScope, error) {
+	if params.Client == nil {
+		return nil, errors.New("client is required when creating a MachinePoolScope")
+	}
+	if params.MachinePool == nil {
+		return nil, errors.New("machine pool is required when creating a MachinePoolScope")
+	}
+	if params.Cluster == nil {
+		return nil, errors.New("cluster is required when creating a MachinePoolScope")
+	}
+	if params.AzureStackHCICluster == nil {
+		return nil, errors.New("azurestackhci cluster is required when creating a MachinePoolScope")
+	}
+	if params.AzureStackHCIMachinePool == nil {
+		return nil, errors.New("azurestackhci machine pool is required when creating a MachinePoolScope")
+	}
+
+	if params.Logger == nil {
+		log := klogr.New()
+		params.Logger = &log
+	}
+
+	helper, err := patch.NewHelper(params.AzureStackHCIMachinePool, params.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init patch helper")
+	}
+	scopeContext := diagnostics.NewContextWithCorrelationId(context.Background(), params.AzureStackHCIMachinePool.GetAnnotations()[infrav1.AzureCorrelationIDAnnotationKey])
+	return &MachinePoolScope{
+		client:                   params.Client,
+		Cluster:                  params.Cluster,
+		MachinePool:              params.MachinePool,
+		AzureStackHCICluster:     params.AzureStackHCICluster,
+		AzureStackHCIMachinePool: params.AzureStackHCIMachinePool,
+		Logger:                   *params.Logger,
+		patchHelper:              helper,
+		Context:                  scopeContext,
+	}, nil
+}
+
+// MachinePoolScope defines a scope defined around a machine pool and its cluster.
+type MachinePoolScope struct {
+	logr.Logger
+	client      client.Client
+	patchHelper *patch.Helper
+	Context     context.Context
+
+	Cluster                  *clusterv1.Cluster
+	MachinePool              *clusterv1.MachinePool
+	AzureStackHCICluster     *infrav1.AzureStackHCICluster
+	AzureStackHCIMachinePool *infrav1.AzureStackHCIMachinePool
+}
+
+// Name returns the AzureStackHCIMachinePool name.
+func (m *MachinePoolScope) Name() string {
+	return m.AzureStackHCIMachinePool.Name
+}
+
+// Namespace returns the namespace name.
+func (m *MachinePoolScope) Namespace() string {
+	return m.AzureStackHCIMachinePool.Namespace
+}
+
+// GetLogger returns the logger.
+func (m *MachinePoolScope) GetLogger() logr.Logger {
+	return m.Logger
+}
+
+// KubernetesVersion returns the Kubernetes version of the MachinePool.
+func (m *MachinePoolScope) KubernetesVersion() string {
+	return m.MachinePool.Spec.Template.Spec.Version
+}
+
+// GetDesiredReplicas returns the number of virtual machines requested by the MachinePool.
+func (m *MachinePoolScope) GetDesiredReplicas() int32 {
+	return ptr.Deref(m.MachinePool.Spec.Replicas, 1)
+}
+
+// GetReplicas returns the number of virtual machines of the pool.
+func (m *MachinePoolScope) GetReplicas() int32 {
+	return m.AzureStackHCIMachinePool.Status.Replicas
+}
+
+// GetReadyReplicas returns the number of running virtual machines of the pool.
+func (m *MachinePoolScope) GetReadyReplicas() int32 {
+	return m.AzureStackHCIMachinePool.Status.ReadyReplicas
+}
+
+// GetMaxReplicas returns the maximum number of virtual machines that can be created during a rolling update.
+func (m *MachinePoolScope) GetMaxReplicas() int32 {
+	return m.GetDesiredReplicas() + m.GetMaxSurge()
+}
+
+// GetMaxSurge returns the number of virtual machines that can be created above the desired amount during a rolling update.
+func (m *MachinePoolScope) GetMaxSurge() int32 {
+	maxSurge, maxUnavailable := m.rollingUpdateValues()
+	if maxSurge == 0 && maxUnavailable == 0 {
+		return MaxSurge
+	}
+	return maxSurge
+}
+
+// GetMaxUnavailable returns the number of desired virtual machines that can be unavailable during a rolling update.
+func (m *MachinePoolScope) GetMaxUnavailable() int32 {
+	_, maxUnavailable := m.rollingUpdateValues()
+	return maxUnavailable
+}
+
+// rollingUpdateValues resolves the rolling update strategy against the desired replicas.
+func (m *MachinePoolScope) rollingUpdateValues() (maxSurge, maxUnavailable int32) {
+	strategy := m.AzureStackHCIMachinePool.Spec.Strategy
+	if strategy == nil || strategy.RollingUpdate == nil {
+		return MaxSurge, MaxUnavailable
+	}
+
+	desired := int(m.GetDesiredReplicas())
+	surge, unavailable := MaxSurge, MaxUnavailable
+	if strategy.RollingUpdate.MaxSurge != nil {
+		if value, err := intstr.GetScaledValueFromIntOrPercent(strategy.RollingUpdate.MaxSurge, desired, true); err == nil {
+			surge = value
+		}
+	}
+	if strategy.RollingUpdate.MaxUnavailable != nil {
+		if value, err := intstr.GetScaledValueFromIntOrPercent(strategy.RollingUpdate.MaxUnavailable, desired, false); err == nil {
+			unavailable = min(value, desired)
+		}
+	}
+	// Assume that overflow will not happen for G115
+	return int32(max(surge, 0)), int32(max(unavailable, 0)) //nolint
+}
+
+// TemplateHash returns a hash of the shape of the virtual machines of the pool, its template and Kubernetes version.
+// Virtual machines labelled with a different hash are outdated and replaced by the rolling update. The bootstrap
+// data isn't part of the hash: it only matters when a virtual machine is created, so a rotated bootstrap secret is
+// picked up by new virtual machines and doesn't replace the running ones.
+func (m *MachinePoolScope) TemplateHash() (string, error) {
+	input := struct {
+		Template infrav1.AzureStackHCIMachinePoolMachineTemplate `json:"template"`
+		Version  string                                          `json:"version"`
+	}{
+		Template: m.AzureStackHCIMachinePool.Spec.Template,
+		Version:  m.KubernetesVersion(),
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal machine pool template")
+	}
+	hasher := fnv.New32a()
+	if _, err := hasher.Write(data); err != nil {
+		return "", errors.Wrap(err, "failed to hash machine pool template")
+	}
+	return fmt.Sprintf("%08x", hasher.Sum32()), nil
+}
+
+// SetProviderIDList sets the provider IDs of the running virtual machines of the pool.
+func (m *MachinePoolScope) SetProviderIDList(providerIDs []string) {
+	m.AzureStackHCIMachinePool.Spec.ProviderIDList = providerIDs
+}
+
+// SetReplicas sets the number of virtual machines of the pool.
+func (m *MachinePoolScope) SetReplicas(replicas, readyReplicas, upToDateReplicas int32) {
+	m.AzureStackHCIMachinePool.Status.Replicas = replicas
+	m.AzureStackHCIMachinePool.Status.ReadyReplicas = readyReplicas
+	m.AzureStackHCIMachinePool.Status.UpToDateReplicas = upToDateReplicas
+}
+
+// SetInstances sets the virtual machines reported in the status.
+func (m *MachinePoolScope) SetInstances(instances []infrav1.AzureStackHCIMachinePoolInstance) {
+	m.AzureStackHCIMachinePool.Status.Instances = instances
+}
+
+// AddReplica increments the number of virtual machines of the pool.
+func (m *MachinePoolScope) AddReplica() {
+	m.AzureStackHCIMachinePool.Status.Replicas++
+}
+
+// RemoveReplica decrements the number of virtual machines of the pool.
+func (m *MachinePoolScope) RemoveReplica() {
+	m.AzureStackHCIMachinePool.Status.Replicas--
+}
+
+// SetReady sets the AzureStackHCIMachinePool Ready Status.
+func (m *MachinePoolScope) SetReady() {
+	m.AzureStackHCIMachinePool.Status.Ready = true
+	m.AzureStackHCIMachinePool.Status.Initialization = &infrav1.AzureStackHCIMachinePoolInitializationStatus{
+		Provisioned: ptr.To(true),
+	}
+}
+
+// PatchObject persists the machine pool spec and status.
+func (m *MachinePoolScope) PatchObject() error {
+	return m.patchHelper.Patch(
+		m.Context,
+		m.AzureStackHCIMachinePool,
+		patch.WithOwnedConditions{Conditions: []string{
+			infrav1.MachinePoolReplicasReadyCondition,
+			infrav1.MachinePoolReplicasUpToDateCondition,
+		}})
+}
+
+// Close the MachinePoolScope by updating the machine pool spec, machine pool status.
+func (m *MachinePoolScope) Close() error {
+	return m.PatchObject()
+}
+
+// GetBootstrapData returns the bootstrap data from the secret in the MachinePool's bootstrap.dataSecretName.
+func (m *MachinePoolScope) GetBootstrapData() (string, error) {
+	if m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		return "", errors.New("error retrieving bootstrap data: linked MachinePool's bootstrap.dataSecretName is nil")
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.Namespace(), Name: *m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName}
+	if err := m.client.Get(m.Context, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for AzureStackHCIMachinePool %s/%s", m.Namespace(), m.Name())
+	}
+
+	value, ok := secret.Data["value"]
+	if !ok {
+		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+	return base64.StdEncoding.EncodeToString(value), nil
+}
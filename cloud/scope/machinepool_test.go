@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+// TestMachinePoolScopeTemplateHash verifies that only the shape of the virtual machines rolls the pool, a rotated
+// bootstrap secret doesn't.
+func TestMachinePoolScopeTemplateHash(t *testing.T) {
+	newScope := func() *MachinePoolScope {
+		return &MachinePoolScope{
+			MachinePool: &clusterv1.MachinePool{
+				Spec: clusterv1.MachinePoolSpec{
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Version:   "v1.31.0",
+							Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("bootstrap-1")},
+						},
+					},
+				},
+			},
+			AzureStackHCIMachinePool: &infrav1.AzureStackHCIMachinePool{
+				Spec: infrav1.AzureStackHCIMachinePoolSpec{
+					Template: infrav1.AzureStackHCIMachinePoolMachineTemplate{VMSize: "Standard_A4_v2"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		mutate      func(m *MachinePoolScope)
+		wantChanged bool
+	}{
+		{
+			name:        "unchanged",
+			mutate:      func(m *MachinePoolScope) {},
+			wantChanged: false,
+		},
+		{
+			name:        "vm size changed",
+			mutate:      func(m *MachinePoolScope) { m.AzureStackHCIMachinePool.Spec.Template.VMSize = "Standard_A8_v2" },
+			wantChanged: true,
+		},
+		{
+			name:        "kubernetes version changed",
+			mutate:      func(m *MachinePoolScope) { m.MachinePool.Spec.Template.Spec.Version = "v1.32.0" },
+			wantChanged: true,
+		},
+		{
+			name: "bootstrap secret rotated",
+			mutate: func(m *MachinePoolScope) {
+				m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName = ptr.To("bootstrap-2")
+			},
+			wantChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := newScope()
+			before, err := m.TemplateHash()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tt.mutate(m)
+			after, err := m.TemplateHash()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(after != before).To(Equal(tt.wantChanged))
+		})
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/moc/pkg/diagnostics"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// How long a remediated machine has to become healthy before the step is retried or the next step is taken.
+	DefaultRemediationTimeout = 5 * time.Minute
+	// How many times each remediation step is tried.
+	DefaultRemediationRetryLimit = 1
+)
+
+// DefaultRemediationSteps are the steps tried before a Machine is replaced, cheapest first.
+var DefaultRemediationSteps = []infrav1.RemediationStep{
+	infrav1.RemediationStepSoftReboot,
+	infrav1.RemediationStepHardPowerCycle,
+}
+
+// RemediationScopeParams defines the input parameters used to create a new RemediationScope.
+type RemediationScopeParams struct {
+	Client                   client.Client
+	Logger                   *logr.Logger
+	AzureStackHCIRemediation *infrav1.AzureStackHCIRemediation
+	Machine                  *clusterv1.Machine
+	AzureStackHCIMachine     *infrav1.AzureStackHCIMachine
+}
+
+// NewRemediationScope creates a new RemediationScope from the supplied parameters.
+// This is meant to be called for each reconcile iteration.
+func NewRemediationScope(params RemediationScopeParams) (*RemediationScope, error) {
+	if params.Client == nil {
+		return nil, errors.New("client is required when creating a RemediationScope")
+	}
+
+	if params.AzureStackHCIRemediation == nil {
+		return nil, errors.New("azurestackhci remediation is required when creating a RemediationScope")
+	}
+
+	if params.Logger == nil {
+		log := klogr.New()
+		params.Logger = &log
+	}
+
+	helper, err := patch.NewHelper(params.AzureStackHCIRemediation, params.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init patch helper")
+	}
+	scopeContext := diagnostics.NewContextWithCorrelationId(context.Background(), params.AzureStackHCIRemediation.GetAnnotations()[infrav1.AzureCorrelationIDAnnotationKey])
+	return &RemediationScope{
+		client:                   params.Client,
+		AzureStackHCIRemediation: params.AzureStackHCIRemediation,
+		Machine:                  params.Machine,
+		AzureStackHCIMachine:     params.AzureStackHCIMachine,
+		Logger:                   *params.Logger,
+		patchHelper:              helper,
+		Context:                  scopeContext,
+	}, nil
+}
+
+// RemediationScope defines a scope defined around a remediation of a Machine.
+type RemediationScope struct {
+	logr.Logger
+	client      client.Client
+	patchHelper *patch.Helper
+	Context     context.Context
+
+	AzureStackHCIRemediation *infrav1.AzureStackHCIRemediation
+	Machine                  *clusterv1.Machine
+	AzureStackHCIMachine     *infrav1.AzureStackHCIMachine
+}
+
+// Name returns the AzureStackHCIRemediation name.
+func (r *RemediationScope) Name() string {
+	return r.AzureStackHCIRemediation.Name
+}
+
+// Namespace returns the namespace name.
+func (r *RemediationScope) Namespace() string {
+	return r.AzureStackHCIRemediation.Namespace
+}
+
+// Steps returns the remediation steps tried before the Machine is replaced.
+func (r *RemediationScope) Steps() []infrav1.RemediationStep {
+	if strategy := r.AzureStackHCIRemediation.Spec.Strategy; strategy != nil && len(strategy.Steps) > 0 {
+		return strategy.Steps
+	}
+	return DefaultRemediationSteps
+}
+
+// RetryLimit returns the number of times each remediation step is tried.
+func (r *RemediationScope) RetryLimit() int32 {
+	if strategy := r.AzureStackHCIRemediation.Spec.Strategy; strategy != nil && strategy.RetryLimit != nil {
+		return *strategy.RetryLimit
+	}
+	return DefaultRemediationRetryLimit
+}
+
+// Timeout returns how long the machine has to become healthy after a remediation step.
+func (r *RemediationScope) Timeout() time.Duration {
+	if strategy := r.AzureStackHCIRemediation.Spec.Strategy; strategy != nil && strategy.Timeout != nil {
+		return strategy.Timeout.Duration
+	}
+	return DefaultRemediationTimeout
+}
+
+// Step returns the remediation step currently taken, the first step when the remediation has just started.
+func (r *RemediationScope) Step() infrav1.RemediationStep {
+	if step := r.AzureStackHCIRemediation.Status.Step; step != "" {
+		return step
+	}
+	if steps := r.Steps(); len(steps) > 0 {
+		return steps[0]
+	}
+	return infrav1.RemediationStepReplace
+}
+
+// NextStep moves the remediation to the step after the current one, replacing the Machine after the last step.
+func (r *RemediationScope) NextStep() infrav1.RemediationStep {
+	next := infrav1.RemediationStepReplace
+	steps := r.Steps()
+	for i, step := range steps {
+		if step == r.Step() && i+1 < len(steps) {
+			next = steps[i+1]
+			break
+		}
+	}
+	r.AzureStackHCIRemediation.Status.Step = next
+	r.AzureStackHCIRemediation.Status.RetryCount = 0
+	r.AzureStackHCIRemediation.Status.LastRemediated = nil
+	return next
+}
+
+// RetryCount returns the number of times the current step was tried.
+func (r *RemediationScope) RetryCount() int32 {
+	return r.AzureStackHCIRemediation.Status.RetryCount
+}
+
+// LastRemediated returns the time the current step was last applied, if any.
+func (r *RemediationScope) LastRemediated() *metav1.Time {
+	return r.AzureStackHCIRemediation.Status.LastRemediated
+}
+
+// SetRemediated records an attempt of the current step. Failed attempts leave the last remediation time unchanged.
+func (r *RemediationScope) SetRemediated(applied bool) {
+	r.AzureStackHCIRemediation.Status.Step = r.Step()
+	r.AzureStackHCIRemediation.Status.RetryCount++
+	if applied {
+		now := metav1.Now()
+		r.AzureStackHCIRemediation.Status.LastRemediated = &now
+	}
+}
+
+// PatchObject persists the remediation spec and status.
+func (r *RemediationScope) PatchObject() error {
+	return r.patchHelper.Patch(
+		r.Context,
+		r.AzureStackHCIRemediation,
+		patch.WithOwnedConditions{Conditions: []string{
+			infrav1.RemediationInProgressCondition,
+		}})
+}
+
+// Close the RemediationScope by updating the remediation spec and status.
+func (r *RemediationScope) Close() error {
+	return r.PatchObject()
+}
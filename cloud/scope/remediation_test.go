@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+func TestRemediationScopeStrategy(t *testing.T) {
+	g := NewWithT(t)
+	r := &RemediationScope{AzureStackHCIRemediation: &infrav1.AzureStackHCIRemediation{}}
+	g.Expect(r.Steps()).To(Equal(DefaultRemediationSteps))
+	g.Expect(r.RetryLimit()).To(Equal(int32(DefaultRemediationRetryLimit)))
+	g.Expect(r.Timeout()).To(Equal(DefaultRemediationTimeout))
+
+	r.AzureStackHCIRemediation.Spec.Strategy = &infrav1.RemediationStrategy{
+		Steps:      []infrav1.RemediationStep{infrav1.RemediationStepHardPowerCycle},
+		RetryLimit: ptr.To[int32](3),
+		Timeout:    &metav1.Duration{Duration: time.Minute},
+	}
+	g.Expect(r.Steps()).To(Equal([]infrav1.RemediationStep{infrav1.RemediationStepHardPowerCycle}))
+	g.Expect(r.RetryLimit()).To(Equal(int32(3)))
+	g.Expect(r.Timeout()).To(Equal(time.Minute))
+}
+
+// TestRemediationScopeSteps verifies that the steps are taken in order, that every attempt is counted and that the
+// Machine is replaced after the last step.
+func TestRemediationScopeSteps(t *testing.T) {
+	g := NewWithT(t)
+	r := &RemediationScope{AzureStackHCIRemediation: &infrav1.AzureStackHCIRemediation{}}
+	status := &r.AzureStackHCIRemediation.Status
+
+	g.Expect(r.Step()).To(Equal(infrav1.RemediationStepSoftReboot))
+	g.Expect(r.RetryCount()).To(BeZero())
+
+	r.SetRemediated(false)
+	g.Expect(status.Step).To(Equal(infrav1.RemediationStepSoftReboot))
+	g.Expect(r.RetryCount()).To(Equal(int32(1)))
+	g.Expect(r.LastRemediated()).To(BeNil())
+
+	r.SetRemediated(true)
+	g.Expect(r.RetryCount()).To(Equal(int32(2)))
+	g.Expect(r.LastRemediated()).NotTo(BeNil())
+
+	g.Expect(r.NextStep()).To(Equal(infrav1.RemediationStepHardPowerCycle))
+	g.Expect(r.Step()).To(Equal(infrav1.RemediationStepHardPowerCycle))
+	g.Expect(r.RetryCount()).To(BeZero())
+	g.Expect(r.LastRemediated()).To(BeNil())
+
+	g.Expect(r.NextStep()).To(Equal(infrav1.RemediationStepReplace))
+	g.Expect(r.Step()).To(Equal(infrav1.RemediationStepReplace))
+	g.Expect(r.NextStep()).To(Equal(infrav1.RemediationStepReplace))
+}
@@ -21,15 +21,20 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/go-logr/logr"
 	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
 	azhciauth "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/auth"
 	"github.com/microsoft/moc/pkg/auth"
 	"github.com/microsoft/moc/pkg/diagnostics"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2/klogr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -179,11 +184,91 @@ func (m *VirtualMachineScope) SetVMState(v infrav1.VMState) {
 	*m.AzureStackHCIVirtualMachine.Status.VMState = v
 }
 
+// SetAddresses sets the AzureStackHCIVirtualMachine addresses.
+func (m *VirtualMachineScope) SetAddresses(addresses []corev1.NodeAddress) {
+	m.AzureStackHCIVirtualMachine.Status.Addresses = addresses
+}
+
+// SetHost sets the AzureStackHCIVirtualMachine host.
+func (m *VirtualMachineScope) SetHost(host string) {
+	m.AzureStackHCIVirtualMachine.Status.Host = host
+}
+
+// SetPowerState sets the AzureStackHCIVirtualMachine power state.
+func (m *VirtualMachineScope) SetPowerState(v infrav1.VMPowerState) {
+	m.AzureStackHCIVirtualMachine.Status.PowerState = v
+}
+
+// DesiredPowerState returns the power state requested by the spec, nil if the power state isn't managed.
+func (m *VirtualMachineScope) DesiredPowerState() *infrav1.VMPowerState {
+	return m.AzureStackHCIVirtualMachine.Spec.PowerState
+}
+
+// PowerOperation returns the one-off power operation requested with the power-operation annotation, if any.
+func (m *VirtualMachineScope) PowerOperation() (infrav1.VMPowerOperation, bool) {
+	operation, ok := m.AzureStackHCIVirtualMachine.Annotations[infrav1.AnnotationVMPowerOperation]
+	return infrav1.VMPowerOperation(operation), ok
+}
+
+// ClearPowerOperation removes the power-operation annotation once the operation was handled.
+func (m *VirtualMachineScope) ClearPowerOperation() {
+	delete(m.AzureStackHCIVirtualMachine.Annotations, infrav1.AnnotationVMPowerOperation)
+}
+
 // SetReady sets the AzureStackHCIVirtualMachine Ready Status
 func (m *VirtualMachineScope) SetReady() {
 	m.AzureStackHCIVirtualMachine.Status.Ready = true
 }
 
+// SetFailureReason sets the AzureStackHCIVirtualMachine status failure reason.
+func (m *VirtualMachineScope) SetFailureReason(v capierrors.MachineStatusError) {
+	m.AzureStackHCIVirtualMachine.Status.FailureReason = &v
+}
+
+// SetFailureMessage sets the AzureStackHCIVirtualMachine status failure message.
+func (m *VirtualMachineScope) SetFailureMessage(v error) {
+	message := v.Error()
+	m.AzureStackHCIVirtualMachine.Status.FailureMessage = &message
+}
+
+// AddCapacityRetry counts a creation that failed for lack of capacity and returns the number of consecutive failures.
+func (m *VirtualMachineScope) AddCapacityRetry() int32 {
+	m.AzureStackHCIVirtualMachine.Status.CapacityRetries++
+	return m.AzureStackHCIVirtualMachine.Status.CapacityRetries
+}
+
+// AddCapacityCheck counts a capacity check that found no host with the capacity for the virtual machine and returns
+// the number of consecutive checks.
+func (m *VirtualMachineScope) AddCapacityCheck() int32 {
+	m.AzureStackHCIVirtualMachine.Status.CapacityChecks++
+	return m.AzureStackHCIVirtualMachine.Status.CapacityChecks
+}
+
+// ResetCapacityRetries resets the number of creations and capacity checks that failed for lack of capacity.
+func (m *VirtualMachineScope) ResetCapacityRetries() {
+	m.AzureStackHCIVirtualMachine.Status.CapacityRetries = 0
+	m.AzureStackHCIVirtualMachine.Status.CapacityChecks = 0
+	m.AzureStackHCIVirtualMachine.Status.NextCapacityCheckTime = nil
+}
+
+// BackOffCapacityCheck delays the next capacity check after the given number of consecutive lacks of capacity, the
+// delay doubles with each of them. Returns the delay.
+func (m *VirtualMachineScope) BackOffCapacityCheck(retries int32) time.Duration {
+	backoff := azurestackhci.CapacityBackoff(retries)
+	next := metav1.NewTime(time.Now().Add(backoff))
+	m.AzureStackHCIVirtualMachine.Status.NextCapacityCheckTime = &next
+	return backoff
+}
+
+// CapacityCheckDelay returns how long to wait before checking the capacity for the virtual machine again.
+func (m *VirtualMachineScope) CapacityCheckDelay() time.Duration {
+	next := m.AzureStackHCIVirtualMachine.Status.NextCapacityCheckTime
+	if next == nil {
+		return 0
+	}
+	return max(time.Until(next.Time), 0)
+}
+
 // SetAnnotation sets a key value annotation on the AzureStackHCIVirtualMachine.
 func (m *VirtualMachineScope) SetAnnotation(key, value string) {
 	if m.AzureStackHCIVirtualMachine.Annotations == nil {
@@ -199,6 +284,8 @@ func (m *VirtualMachineScope) PatchObject() error {
 		patch.WithOwnedConditions{Conditions: []string{
 			clusterv1.ReadyCondition,
 			infrav1.VMRunningCondition,
+			infrav1.ImageAvailableCondition,
+			infrav1.VMPowerStateSyncedCondition,
 		}})
 
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package availabilitysets
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/telemetry"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Spec input specification for Get/CreateOrUpdate/Delete calls
+type Spec struct {
+	Name                     string
+	PlatformFaultDomainCount int32
+}
+
+// Get provides information about an availability set.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	avsetSpec, ok := spec.(*Spec)
+	if !ok {
+		return compute.AvailabilitySet{}, errors.New("invalid availability set specification")
+	}
+
+	avset, err := s.Client.Get(ctx, s.Scope.GetResourceGroup(), avsetSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+	if avset == nil || len(*avset) == 0 {
+		return nil, status.Errorf(codes.NotFound, "availability set %s not found", avsetSpec.Name)
+	}
+	return (*avset)[0], nil
+}
+
+// Reconcile gets/creates an availability set.
+func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	avsetSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid availability set specification")
+	}
+
+	if _, err := s.Get(ctx, avsetSpec); err == nil {
+		// availability set already exists, no update supported for now
+		return nil
+	}
+
+	avset := compute.AvailabilitySet{
+		Name:                     to.StringPtr(avsetSpec.Name),
+		PlatformFaultDomainCount: to.Int32Ptr(avsetSpec.PlatformFaultDomainCount),
+	}
+
+	logger := s.Scope.GetLogger()
+	logger.Info("creating availability set", "name", avsetSpec.Name, "faultDomains", avsetSpec.PlatformFaultDomainCount)
+	_, err := s.Client.Create(ctx, s.Scope.GetResourceGroup(), avsetSpec.Name, &avset)
+	telemetry.WriteMocOperationLog(logger, telemetry.CreateOrUpdate, s.Scope.GetCustomResourceTypeWithName(), telemetry.AvailabilitySet,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), avsetSpec.Name), &avset, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create availability set %s in resource group %s", avsetSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully created availability set", "name", avsetSpec.Name)
+	return nil
+}
+
+// Delete deletes the availability set with the provided name.
+func (s *Service) Delete(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	avsetSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid availability set specification")
+	}
+
+	logger := s.Scope.GetLogger()
+	logger.Info("deleting availability set", "name", avsetSpec.Name)
+	err := s.Client.Delete(ctx, s.Scope.GetResourceGroup(), avsetSpec.Name)
+	telemetry.WriteMocOperationLog(logger, telemetry.Delete, s.Scope.GetCustomResourceTypeWithName(), telemetry.AvailabilitySet,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), avsetSpec.Name), nil, err)
+	if err != nil && azurestackhci.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete availability set %s in resource group %s", avsetSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully deleted availability set", "name", avsetSpec.Name)
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package galleryimages
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/telemetry"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	TagKeyImageOwner = "ownedBy"
+	TagValImageOwner = "caph"
+)
+
+// Spec input specification for Get/CreateOrUpdate/Delete calls
+type Spec struct {
+	Name             string
+	Location         string
+	URL              string
+	Path             string
+	StorageContainer string
+	OSType           compute.OperatingSystemTypes
+}
+
+// Get provides information about a gallery image.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	imageSpec, ok := spec.(*Spec)
+	if !ok {
+		return compute.GalleryImage{}, errors.New("invalid gallery image specification")
+	}
+
+	images, err := s.Client.Get(ctx, imageSpec.Location, imageSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+	if images == nil || len(*images) == 0 {
+		return nil, status.Errorf(codes.NotFound, "gallery image %s not found", imageSpec.Name)
+	}
+	return (*images)[0], nil
+}
+
+// Reconcile gets/imports a gallery image from a url or a path on the cloud agent.
+func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	imageSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid gallery image specification")
+	}
+
+	if _, err := s.Get(ctx, imageSpec); err == nil {
+		// gallery image already exists, images are immutable
+		return nil
+	}
+
+	// tag the image so that only images imported by caph are deleted
+	owner := TagValImageOwner
+	image := compute.GalleryImage{
+		Name:     to.StringPtr(imageSpec.Name),
+		Location: to.StringPtr(imageSpec.Location),
+		Tags:     map[string]*string{TagKeyImageOwner: &owner},
+		GalleryImageProperties: &compute.GalleryImageProperties{
+			OsType: imageSpec.OSType,
+		},
+	}
+	if imageSpec.StorageContainer != "" {
+		image.ContainerName = to.StringPtr(imageSpec.StorageContainer)
+	}
+
+	logger := s.Scope.GetLogger()
+	logger.Info("importing gallery image", "name", imageSpec.Name, "location", imageSpec.Location, "url", imageSpec.URL, "path", imageSpec.Path)
+	var err error
+	switch {
+	case imageSpec.URL != "":
+		_, err = s.Client.UploadImageFromHttp(ctx, imageSpec.Location, imageSpec.Name, &image, &compute.AzureGalleryImageProperties{
+			SasURI: imageSpec.URL,
+		})
+	case imageSpec.Path != "":
+		_, err = s.Client.UploadImageFromLocal(ctx, imageSpec.Location, imageSpec.Path, imageSpec.Name, &image)
+	default:
+		return errors.Errorf("gallery image %s has neither a url nor a path to import from", imageSpec.Name)
+	}
+	telemetry.WriteMocOperationLog(logger, telemetry.CreateOrUpdate, s.Scope.GetCustomResourceTypeWithName(), telemetry.GalleryImage,
+		telemetry.GenerateMocResourceName(imageSpec.Location, imageSpec.Name), nil, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to import gallery image %s in location %s", imageSpec.Name, imageSpec.Location)
+	}
+
+	logger.Info("successfully imported gallery image", "name", imageSpec.Name)
+	return nil
+}
+
+// Delete deletes a gallery image if it was imported by caph.
+func (s *Service) Delete(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	imageSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid gallery image specification")
+	}
+
+	logger := s.Scope.GetLogger()
+	existing, err := s.Get(ctx, imageSpec)
+	if err != nil && azurestackhci.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get gallery image %s", imageSpec.Name)
+	}
+
+	image, ok := existing.(compute.GalleryImage)
+	if !ok {
+		return errors.New("returned incorrect gallery image interface")
+	}
+	if value, ok := image.Tags[TagKeyImageOwner]; !ok || value == nil || *value != TagValImageOwner {
+		logger.Info("skipping gallery image deletion, since it is not imported by caph", "name", imageSpec.Name)
+		return nil
+	}
+
+	logger.Info("deleting gallery image", "name", imageSpec.Name, "location", imageSpec.Location)
+	err = s.Client.Delete(ctx, imageSpec.Location, imageSpec.Name)
+	telemetry.WriteMocOperationLog(logger, telemetry.Delete, s.Scope.GetCustomResourceTypeWithName(), telemetry.GalleryImage,
+		telemetry.GenerateMocResourceName(imageSpec.Location, imageSpec.Name), nil, err)
+	if err != nil && azurestackhci.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete gallery image %s in location %s", imageSpec.Name, imageSpec.Location)
+	}
+
+	logger.Info("successfully deleted gallery image", "name", imageSpec.Name)
+	return nil
+}
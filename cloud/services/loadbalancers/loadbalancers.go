@@ -19,6 +19,7 @@ package loadbalancers
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
@@ -32,9 +33,34 @@ type Spec struct {
 	Name            string
 	BackendPoolName string
 	VnetName        string
-	FrontendPort    int32
-	BackendPort     int32
+	Rules           []Rule
+	Probes          []Probe
 	Tags            map[string]*string
+	// FrontendIPAddress requests a static frontend address, when empty MOC allocates one.
+	FrontendIPAddress string
+	// FrontendIPVersion is the address family of the frontend, defaults to IPv4.
+	FrontendIPVersion network.IPVersion
+}
+
+// Rule describes a load balancing rule.
+type Rule struct {
+	Name                 string
+	Protocol             network.TransportProtocol
+	FrontendPort         int32
+	BackendPort          int32
+	IdleTimeoutInMinutes *int32
+	EnableFloatingIP     bool
+	ProbeName            string
+}
+
+// Probe describes a health probe of the backend pool.
+type Probe struct {
+	Name              string
+	Protocol          network.ProbeProtocol
+	Port              int32
+	RequestPath       string
+	IntervalInSeconds int32
+	NumberOfProbes    int32
 }
 
 // Get provides information about a load balancer.
@@ -51,6 +77,18 @@ func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error
 	return (*lb)[0], nil
 }
 
+// List returns all load balancers in the resource group.
+func (s *Service) List(ctx context.Context) ([]network.LoadBalancer, error) {
+	lbs, err := s.Client.Get(ctx, s.Scope.GetResourceGroup(), "")
+	if err != nil {
+		return nil, err
+	}
+	if lbs == nil {
+		return nil, nil
+	}
+	return *lbs, nil
+}
+
 // Reconcile gets/creates/updates a load balancer.
 func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	telemetry.WriteMocInfoLog(ctx, s.Scope)
@@ -59,9 +97,17 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		return errors.New("invalid loadbalancer specification")
 	}
 
-	if _, err := s.Get(ctx, lbSpec); err == nil {
-		// loadbalancer already exists, no update supported for now
-		return nil
+	operation := "creating"
+	if existing, err := s.Get(ctx, lbSpec); err == nil {
+		existingLB, _ := existing.(network.LoadBalancer)
+		if rulesMatch(existingLB, lbSpec.Rules) {
+			return nil
+		}
+		// keep the frontend address of the existing loadbalancer when updating its rules
+		if lbSpec.FrontendIPAddress == "" {
+			lbSpec.FrontendIPAddress = FrontendIPAddress(existingLB)
+		}
+		operation = "updating"
 	}
 
 	networkLB := network.LoadBalancer{
@@ -78,25 +124,24 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 						Subnet: &network.Subnet{
 							ID: to.StringPtr(lbSpec.VnetName),
 						},
+						PrivateIPAddressVersion: lbSpec.FrontendIPVersion,
 					},
 				},
 			},
-			LoadBalancingRules: &[]network.LoadBalancingRule{
-				{
-					LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
-						Protocol:     network.TransportProtocolTCP,
-						FrontendPort: to.Int32Ptr(lbSpec.FrontendPort),
-						BackendPort:  to.Int32Ptr(lbSpec.BackendPort),
-					},
-				},
-			},
+			LoadBalancingRules: generateLoadBalancingRules(lbSpec.Rules),
+			Probes:             generateProbes(lbSpec.Probes),
 		},
 		Tags: lbSpec.Tags,
 	}
 
-	// create the load balancer
+	if lbSpec.FrontendIPAddress != "" {
+		frontend := (*networkLB.FrontendIPConfigurations)[0].FrontendIPConfigurationPropertiesFormat
+		frontend.IPAddress = to.StringPtr(lbSpec.FrontendIPAddress)
+		frontend.PrivateIPAllocationMethod = network.Static
+	}
+
 	logger := s.Scope.GetLogger()
-	logger.Info("creating loadbalancer", "name", lbSpec.Name)
+	logger.Info(operation+" loadbalancer", "name", lbSpec.Name)
 	_, err := s.Client.CreateOrUpdate(ctx, s.Scope.GetResourceGroup(), lbSpec.Name, &networkLB)
 	telemetry.WriteMocOperationLog(logger, telemetry.CreateOrUpdate, s.Scope.GetCustomResourceTypeWithName(), telemetry.LoadBalancer,
 		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), lbSpec.Name), &networkLB, err)
@@ -104,7 +149,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		return err
 	}
 
-	logger.Info("successfully created loadbalancer", "name", lbSpec.Name)
+	logger.Info("successfully reconciled loadbalancer", "name", lbSpec.Name)
 	return err
 }
 
@@ -131,3 +176,89 @@ func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	logger.Info("successfully deleted loadbalancer", "name", lbSpec.Name)
 	return err
 }
+
+// generateLoadBalancingRules converts the rules of the spec to MOC load balancing rules.
+// Probes are referenced by name.
+func generateLoadBalancingRules(rules []Rule) *[]network.LoadBalancingRule {
+	lbRules := make([]network.LoadBalancingRule, 0, len(rules))
+	for _, rule := range rules {
+		lbRule := network.LoadBalancingRule{
+			Name: to.StringPtr(rule.Name),
+			LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+				Protocol:             rule.Protocol,
+				FrontendPort:         to.Int32Ptr(rule.FrontendPort),
+				BackendPort:          to.Int32Ptr(rule.BackendPort),
+				IdleTimeoutInMinutes: rule.IdleTimeoutInMinutes,
+				EnableFloatingIP:     to.BoolPtr(rule.EnableFloatingIP),
+			},
+		}
+		if rule.ProbeName != "" {
+			lbRule.Probe = &network.SubResource{ID: to.StringPtr(rule.ProbeName)}
+		}
+		lbRules = append(lbRules, lbRule)
+	}
+	return &lbRules
+}
+
+// generateProbes converts the probes of the spec to MOC probes.
+func generateProbes(probes []Probe) *[]network.Probe {
+	lbProbes := make([]network.Probe, 0, len(probes))
+	for _, probe := range probes {
+		lbProbe := network.Probe{
+			Name: to.StringPtr(probe.Name),
+			ProbePropertiesFormat: &network.ProbePropertiesFormat{
+				Protocol:          probe.Protocol,
+				Port:              to.Int32Ptr(probe.Port),
+				IntervalInSeconds: to.Int32Ptr(probe.IntervalInSeconds),
+				NumberOfProbes:    to.Int32Ptr(probe.NumberOfProbes),
+			},
+		}
+		// a request path is only allowed for http(s) probes
+		if probe.Protocol != network.ProbeProtocolTCP && probe.RequestPath != "" {
+			lbProbe.RequestPath = to.StringPtr(probe.RequestPath)
+		}
+		lbProbes = append(lbProbes, lbProbe)
+	}
+	return &lbProbes
+}
+
+// rulesMatch returns true if the load balancer already forwards the ports of all rules.
+// Only protocol and ports are compared since those are reported back by MOC.
+func rulesMatch(lb network.LoadBalancer, rules []Rule) bool {
+	if lb.LoadBalancerPropertiesFormat == nil || lb.LoadBalancingRules == nil {
+		return len(rules) == 0
+	}
+	existing := *lb.LoadBalancingRules
+	if len(existing) != len(rules) {
+		return false
+	}
+	for _, rule := range rules {
+		found := false
+		for _, lbRule := range existing {
+			if lbRule.LoadBalancingRulePropertiesFormat == nil || lbRule.FrontendPort == nil || lbRule.BackendPort == nil {
+				continue
+			}
+			if strings.EqualFold(string(lbRule.Protocol), string(rule.Protocol)) &&
+				*lbRule.FrontendPort == rule.FrontendPort && *lbRule.BackendPort == rule.BackendPort {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FrontendIPAddress returns the frontend address of the load balancer, if any.
+func FrontendIPAddress(lb network.LoadBalancer) string {
+	if lb.LoadBalancerPropertiesFormat == nil || lb.FrontendIPConfigurations == nil || len(*lb.FrontendIPConfigurations) == 0 {
+		return ""
+	}
+	frontend := (*lb.FrontendIPConfigurations)[0]
+	if frontend.FrontendIPConfigurationPropertiesFormat == nil || frontend.IPAddress == nil {
+		return ""
+	}
+	return *frontend.IPAddress
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/microsoft/moc-sdk-for-go/services/network"
+	. "github.com/onsi/gomega"
+)
+
+func lbWithRules(rules ...network.LoadBalancingRule) network.LoadBalancer {
+	return network.LoadBalancer{LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{LoadBalancingRules: &rules}}
+}
+
+func lbRule(protocol network.TransportProtocol, frontendPort, backendPort int32) network.LoadBalancingRule {
+	return network.LoadBalancingRule{LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
+		Protocol:     protocol,
+		FrontendPort: to.Int32Ptr(frontendPort),
+		BackendPort:  to.Int32Ptr(backendPort),
+	}}
+}
+
+// TestRulesMatch verifies that the load balancer is only updated when the protocol or ports of its rules change.
+func TestRulesMatch(t *testing.T) {
+	apiServer := Rule{Name: "apiserver", Protocol: network.TransportProtocolTCP, FrontendPort: 6443, BackendPort: 6443}
+	ingress := Rule{Name: "ingress", Protocol: network.TransportProtocolTCP, FrontendPort: 443, BackendPort: 30443}
+	tests := []struct {
+		name  string
+		lb    network.LoadBalancer
+		rules []Rule
+		want  bool
+	}{
+		{name: "no rules", lb: network.LoadBalancer{}, want: true},
+		{name: "rules missing", lb: network.LoadBalancer{}, rules: []Rule{apiServer}},
+		{name: "same rules", lb: lbWithRules(lbRule("tcp", 6443, 6443), lbRule("Tcp", 443, 30443)), rules: []Rule{ingress, apiServer}, want: true},
+		{name: "rule added", lb: lbWithRules(lbRule("Tcp", 6443, 6443)), rules: []Rule{apiServer, ingress}},
+		{name: "rule removed", lb: lbWithRules(lbRule("Tcp", 6443, 6443), lbRule("Tcp", 443, 30443)), rules: []Rule{apiServer}},
+		{name: "backend port changed", lb: lbWithRules(lbRule("Tcp", 6443, 6443), lbRule("Tcp", 443, 30080)), rules: []Rule{apiServer, ingress}},
+		{name: "protocol changed", lb: lbWithRules(lbRule("Udp", 6443, 6443)), rules: []Rule{apiServer}},
+		{name: "rule without ports", lb: lbWithRules(network.LoadBalancingRule{}), rules: []Rule{apiServer}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(rulesMatch(tc.lb, tc.rules)).To(Equal(tc.want))
+		})
+	}
+}
+
+// TestGenerateLoadBalancingRules verifies that rules only reference a probe when one is set.
+func TestGenerateLoadBalancingRules(t *testing.T) {
+	g := NewWithT(t)
+
+	rules := *generateLoadBalancingRules([]Rule{
+		{Name: "apiserver", Protocol: network.TransportProtocolTCP, FrontendPort: 6443, BackendPort: 6443, ProbeName: "apiserver-readyz"},
+		{Name: "dns", Protocol: network.TransportProtocolUDP, FrontendPort: 53, BackendPort: 30053, IdleTimeoutInMinutes: to.Int32Ptr(4)},
+	})
+	g.Expect(rules).To(HaveLen(2))
+	g.Expect(*rules[0].Probe.ID).To(Equal("apiserver-readyz"))
+	g.Expect(rules[1].Probe).To(BeNil())
+	g.Expect(rules[1].Protocol).To(Equal(network.TransportProtocolUDP))
+	g.Expect(*rules[1].BackendPort).To(Equal(int32(30053)))
+	g.Expect(*rules[1].IdleTimeoutInMinutes).To(Equal(int32(4)))
+}
+
+// TestGenerateProbes verifies that a request path is only set on http(s) probes.
+func TestGenerateProbes(t *testing.T) {
+	tests := []struct {
+		name     string
+		probe    Probe
+		wantPath *string
+	}{
+		{name: "https probe", probe: Probe{Protocol: network.ProbeProtocolHTTPS, RequestPath: "/readyz"}, wantPath: to.StringPtr("/readyz")},
+		{name: "http probe", probe: Probe{Protocol: network.ProbeProtocolHTTP, RequestPath: "/healthz"}, wantPath: to.StringPtr("/healthz")},
+		{name: "tcp probe", probe: Probe{Protocol: network.ProbeProtocolTCP, RequestPath: "/healthz"}},
+		{name: "no request path", probe: Probe{Protocol: network.ProbeProtocolHTTP}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			probes := *generateProbes([]Probe{tc.probe})
+			g.Expect(probes).To(HaveLen(1))
+			g.Expect(probes[0].RequestPath).To(Equal(tc.wantPath))
+		})
+	}
+}
+
+// TestFrontendIPAddress verifies that the frontend address is read from the first frontend ip configuration.
+func TestFrontendIPAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		lb   network.LoadBalancer
+		want string
+	}{
+		{name: "no properties", lb: network.LoadBalancer{}},
+		{name: "no frontend", lb: network.LoadBalancer{LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{}}},
+		{
+			name: "no address",
+			lb: network.LoadBalancer{LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+				FrontendIPConfigurations: &[]network.FrontendIPConfiguration{{}},
+			}},
+		},
+		{
+			name: "address",
+			lb: network.LoadBalancer{LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
+				FrontendIPConfigurations: &[]network.FrontendIPConfiguration{{
+					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{IPAddress: to.StringPtr("10.0.0.5")},
+				}},
+			}},
+			want: "10.0.0.5",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(FrontendIPAddress(tc.lb)).To(Equal(tc.want))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMetricsUnavailable is returned when the cloud agent does not report traffic metrics for a load balancer.
+var ErrMetricsUnavailable = errors.New("loadbalancer metrics are not available")
+
+// ReplicaMetrics are the traffic metrics of a load balancer replica.
+type ReplicaMetrics struct {
+	ActiveConnections int64
+	BytesPerSecond    int64
+}
+
+// GetReplicaMetrics returns the traffic metrics of the replicas of a load balancer by replica name.
+func (s *Service) GetReplicaMetrics(ctx context.Context, spec interface{}, replicas []string) (map[string]ReplicaMetrics, error) {
+	lbSpec, ok := spec.(*Spec)
+	if !ok {
+		return nil, errors.New("invalid loadbalancer specification")
+	}
+
+	if _, err := s.Get(ctx, lbSpec); err != nil {
+		return nil, errors.Wrapf(err, "failed to get loadbalancer %s", lbSpec.Name)
+	}
+
+	// the cloud agent loadbalancer API reports the number of connected replicas, but no per replica traffic
+	return nil, errors.Wrapf(ErrMetricsUnavailable, "loadbalancer %s", lbSpec.Name)
+}
@@ -29,8 +29,9 @@ import (
 
 // Spec specification for ip configuration
 type IPConfiguration struct {
-	Name    string
-	Primary bool
+	Name      string
+	Primary   bool
+	IPVersion network.IPVersion
 }
 
 type IPConfigurations []*IPConfiguration
@@ -111,6 +112,9 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 					},
 				},
 			}
+			if ipconfig.IPVersion != "" {
+				networkIPConfig.PrivateIPAddressVersion = ipconfig.IPVersion
+			}
 
 			if ipconfig.Primary {
 				networkIPConfig.LoadBalancerBackendAddressPools = &backendAddressPools
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/microsoft/moc-sdk-for-go/services/cloud"
+	"github.com/pkg/errors"
+)
+
+// Spec input specification for Get calls
+type Spec struct {
+	// Name of the host, all the hosts of the location are returned when empty.
+	Name     string
+	Location string
+}
+
+// Get returns the hosts of a location.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	nodeSpec, ok := spec.(*Spec)
+	if !ok {
+		return []cloud.Node{}, errors.New("invalid node specification")
+	}
+
+	nodes, err := s.Client.Get(ctx, nodeSpec.Location, nodeSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+	if nodes == nil {
+		return []cloud.Node{}, nil
+	}
+	return *nodes, nil
+}
+
+// Reconcile is not supported, hosts are managed by the cloud.
+func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
+	s.Scope.GetLogger().Info("Reconciling nodes is not supported")
+	return fmt.Errorf("Reconciling nodes is not supported")
+}
+
+// Delete is not supported, hosts are managed by the cloud.
+func (s *Service) Delete(ctx context.Context, spec interface{}) error {
+	s.Scope.GetLogger().Info("Deleting nodes is not supported")
+	return fmt.Errorf("Deleting nodes is not supported")
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementgroups
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/telemetry"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Spec input specification for Get/CreateOrUpdate/Delete calls
+type Spec struct {
+	Name string
+	Type compute.PlacementGroupType
+}
+
+// Get provides information about a placement group.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	pgroupSpec, ok := spec.(*Spec)
+	if !ok {
+		return compute.PlacementGroup{}, errors.New("invalid placement group specification")
+	}
+
+	pgroup, err := s.Client.Get(ctx, s.Scope.GetResourceGroup(), pgroupSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+	if pgroup == nil || len(*pgroup) == 0 {
+		return nil, status.Errorf(codes.NotFound, "placement group %s not found", pgroupSpec.Name)
+	}
+	return (*pgroup)[0], nil
+}
+
+// Reconcile gets/creates a placement group.
+func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	pgroupSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid placement group specification")
+	}
+
+	if _, err := s.Get(ctx, pgroupSpec); err == nil {
+		// placement group already exists, no update supported for now
+		return nil
+	}
+
+	pgroup := compute.PlacementGroup{
+		Name: to.StringPtr(pgroupSpec.Name),
+		PlacementGroupProperties: &compute.PlacementGroupProperties{
+			Type:  pgroupSpec.Type,
+			Scope: compute.ServerScope,
+		},
+	}
+
+	logger := s.Scope.GetLogger()
+	logger.Info("creating placement group", "name", pgroupSpec.Name, "type", pgroupSpec.Type)
+	_, err := s.Client.Create(ctx, s.Scope.GetResourceGroup(), pgroupSpec.Name, &pgroup)
+	telemetry.WriteMocOperationLog(logger, telemetry.CreateOrUpdate, s.Scope.GetCustomResourceTypeWithName(), telemetry.PlacementGroup,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), pgroupSpec.Name), &pgroup, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create placement group %s in resource group %s", pgroupSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully created placement group", "name", pgroupSpec.Name)
+	return nil
+}
+
+// Delete deletes the placement group with the provided name.
+func (s *Service) Delete(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	pgroupSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid placement group specification")
+	}
+
+	logger := s.Scope.GetLogger()
+	logger.Info("deleting placement group", "name", pgroupSpec.Name)
+	err := s.Client.Delete(ctx, s.Scope.GetResourceGroup(), pgroupSpec.Name)
+	telemetry.WriteMocOperationLog(logger, telemetry.Delete, s.Scope.GetCustomResourceTypeWithName(), telemetry.PlacementGroup,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), pgroupSpec.Name), nil, err)
+	if err != nil && azurestackhci.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete placement group %s in resource group %s", pgroupSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully deleted placement group", "name", pgroupSpec.Name)
+	return nil
+}
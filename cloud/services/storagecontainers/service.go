@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storagecontainers
+
+import (
+	azhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/moc-sdk-for-go/services/storage/container"
+	"github.com/microsoft/moc/pkg/auth"
+)
+
+var _ azhci.Service = (*Service)(nil)
+
+// Service provides operations on storage containers.
+type Service struct {
+	Client container.ContainerClient
+	Scope  scope.ScopeInterface
+}
+
+// getContainersClient creates a new storage containers client.
+func getContainersClient(cloudAgentFqdn string, authorizer auth.Authorizer) container.ContainerClient {
+	containerClient, _ := container.NewContainerClient(cloudAgentFqdn, authorizer)
+	return *containerClient
+}
+
+// NewService creates a new storage containers service.
+func NewService(scope scope.ScopeInterface) *Service {
+	return &Service{
+		Client: getContainersClient(scope.GetCloudAgentFqdn(), scope.GetAuthorizer()),
+		Scope:  scope,
+	}
+}
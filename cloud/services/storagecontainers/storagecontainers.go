@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storagecontainers
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/telemetry"
+	"github.com/microsoft/moc-sdk-for-go/services/storage"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	TagKeyContainerOwner = "ownedBy"
+	TagValContainerOwner = "caph"
+)
+
+// Spec input specification for Get/CreateOrUpdate/Delete calls
+type Spec struct {
+	Name     string
+	Location string
+	Path     string
+}
+
+// Get provides information about a storage container.
+func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	containerSpec, ok := spec.(*Spec)
+	if !ok {
+		return storage.Container{}, errors.New("invalid storage container specification")
+	}
+
+	containers, err := s.Client.Get(ctx, containerSpec.Location, containerSpec.Name)
+	if err != nil {
+		return nil, err
+	}
+	if containers == nil || len(*containers) == 0 {
+		return nil, status.Errorf(codes.NotFound, "storage container %s not found", containerSpec.Name)
+	}
+	return (*containers)[0], nil
+}
+
+// Reconcile gets/creates a storage container. Storage containers without a path are expected to exist.
+func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	containerSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid storage container specification")
+	}
+
+	_, err := s.Get(ctx, containerSpec)
+	if err == nil {
+		// storage container already exists, no update supported for now
+		return nil
+	}
+	if !azurestackhci.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get storage container %s", containerSpec.Name)
+	}
+	if containerSpec.Path == "" {
+		return errors.Wrapf(err, "storage container %s has no path to create it", containerSpec.Name)
+	}
+
+	// tag the storage container so that only storage containers created by caph are deleted
+	owner := TagValContainerOwner
+	container := storage.Container{
+		Name: to.StringPtr(containerSpec.Name),
+		Tags: map[string]*string{TagKeyContainerOwner: &owner},
+		ContainerProperties: &storage.ContainerProperties{
+			Path: to.StringPtr(containerSpec.Path),
+		},
+	}
+
+	logger := s.Scope.GetLogger()
+	logger.Info("creating storage container", "name", containerSpec.Name, "path", containerSpec.Path)
+	_, err = s.Client.CreateOrUpdate(ctx, containerSpec.Location, containerSpec.Name, &container)
+	telemetry.WriteMocOperationLog(logger, telemetry.CreateOrUpdate, s.Scope.GetCustomResourceTypeWithName(), telemetry.StorageContainer,
+		telemetry.GenerateMocResourceName(containerSpec.Location, containerSpec.Name), &container, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create storage container %s in location %s", containerSpec.Name, containerSpec.Location)
+	}
+
+	logger.Info("successfully created storage container", "name", containerSpec.Name)
+	return nil
+}
+
+// Delete deletes a storage container if it was created by caph.
+func (s *Service) Delete(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	containerSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid storage container specification")
+	}
+
+	logger := s.Scope.GetLogger()
+	existing, err := s.Get(ctx, containerSpec)
+	if err != nil && azurestackhci.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get storage container %s", containerSpec.Name)
+	}
+
+	container, ok := existing.(storage.Container)
+	if !ok {
+		return errors.New("returned incorrect storage container interface")
+	}
+	if value, ok := container.Tags[TagKeyContainerOwner]; !ok || value == nil || *value != TagValContainerOwner {
+		logger.Info("skipping storage container deletion, since it is not created by caph", "name", containerSpec.Name)
+		return nil
+	}
+
+	logger.Info("deleting storage container", "name", containerSpec.Name, "location", containerSpec.Location)
+	err = s.Client.Delete(ctx, containerSpec.Location, containerSpec.Name)
+	telemetry.WriteMocOperationLog(logger, telemetry.Delete, s.Scope.GetCustomResourceTypeWithName(), telemetry.StorageContainer,
+		telemetry.GenerateMocResourceName(containerSpec.Location, containerSpec.Name), nil, err)
+	if err != nil && azurestackhci.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete storage container %s in location %s", containerSpec.Name, containerSpec.Location)
+	}
+
+	logger.Info("successfully deleted storage container", "name", containerSpec.Name)
+	return nil
+}
@@ -19,16 +19,29 @@ package vippools
 
 import (
 	"context"
-	"fmt"
+	"net/netip"
 
+	"github.com/Azure/go-autorest/autorest/to"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/telemetry"
 	"github.com/microsoft/moc-sdk-for-go/services/network"
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrVipPoolNotFound is returned when the referenced vip pool does not exist.
+	ErrVipPoolNotFound = errors.New("vip pool not found")
+	// ErrNoFreeAddresses is returned when every address of a vip pool is allocated.
+	ErrNoFreeAddresses = errors.New("vip pool has no free addresses")
+)
+
 // Spec input specification for Get/CreateOrUpdate/Delete calls
 type Spec struct {
 	Name     string
 	Location string
+	// StartIP and EndIP are only required when the vip pool is created by the provider.
+	StartIP string
+	EndIP   string
 }
 
 // Get provides information about a vip pool.
@@ -50,23 +63,151 @@ func (s *Service) Get(ctx context.Context, spec interface{}) (interface{}, error
 	return (*vp)[0], nil
 }
 
-// Reconcile gets/creates/updates a vip pool.
+// Reconcile gets/creates a vip pool. A vip pool is only created when the spec
+// provides its address range, otherwise it is expected to exist already.
 func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
 	vpSpec, ok := spec.(*Spec)
 	if !ok {
 		return errors.New("invalid vippool specification")
 	}
 
-	if _, err := s.Get(ctx, vpSpec); err == nil {
+	vp, err := s.Get(ctx, vpSpec)
+	if err != nil && !azurestackhci.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get vippool %s", vpSpec.Name)
+	}
+	if err == nil && vp != nil {
 		// vippool already exists, no update supported for now
 		return nil
 	}
-	s.Scope.GetLogger().Info("creating a vippool is not supported")
-	return fmt.Errorf("creating a vippool is not supported")
+
+	if vpSpec.StartIP == "" || vpSpec.EndIP == "" {
+		return errors.Wrapf(ErrVipPoolNotFound, "vippool %s does not exist in location %s", vpSpec.Name, vpSpec.Location)
+	}
+
+	networkVP := network.VipPool{
+		Name:     to.StringPtr(vpSpec.Name),
+		Location: to.StringPtr(vpSpec.Location),
+		VipPoolPropertiesFormat: &network.VipPoolPropertiesFormat{
+			StartIP: to.StringPtr(vpSpec.StartIP),
+			EndIP:   to.StringPtr(vpSpec.EndIP),
+		},
+	}
+
+	logger := s.Scope.GetLogger()
+	logger.Info("creating vippool", "name", vpSpec.Name, "location", vpSpec.Location)
+	_, err = s.Client.CreateOrUpdate(ctx, vpSpec.Location, vpSpec.Name, &networkVP)
+	telemetry.WriteMocOperationLog(logger, telemetry.CreateOrUpdate, s.Scope.GetCustomResourceTypeWithName(), telemetry.VipPool,
+		telemetry.GenerateMocResourceName(vpSpec.Location, vpSpec.Name), &networkVP, err)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("successfully created vippool", "name", vpSpec.Name)
+	return nil
 }
 
 // Delete deletes the vip pool with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
-	s.Scope.GetLogger().Info("deleting a vippool is not supported")
-	return fmt.Errorf("deleting a vippool is not supported")
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	vpSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid vippool specification")
+	}
+
+	logger := s.Scope.GetLogger()
+	logger.Info("deleting vippool", "name", vpSpec.Name, "location", vpSpec.Location)
+	err := s.Client.Delete(ctx, vpSpec.Location, vpSpec.Name)
+	telemetry.WriteMocOperationLog(logger, telemetry.Delete, s.Scope.GetCustomResourceTypeWithName(), telemetry.VipPool,
+		telemetry.GenerateMocResourceName(vpSpec.Location, vpSpec.Name), nil, err)
+	if err != nil && azurestackhci.ResourceNotFound(err) {
+		// already deleted
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete vippool %s in location %s", vpSpec.Name, vpSpec.Location)
+	}
+
+	logger.Info("successfully deleted vippool", "name", vpSpec.Name)
+	return nil
+}
+
+// Contains returns true if the address is within the range of the vip pool.
+func Contains(vp network.VipPool, address string) (bool, error) {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid address %s", address)
+	}
+	start, end, err := addressRange(vp)
+	if err != nil {
+		return false, err
+	}
+	return start.Compare(addr) <= 0 && addr.Compare(end) <= 0, nil
+}
+
+// NextFreeAddress returns the first address of the vip pool that is not in use.
+func NextFreeAddress(vp network.VipPool, inUse []string) (string, error) {
+	start, end, err := addressRange(vp)
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[netip.Addr]struct{}, len(inUse))
+	for _, address := range inUse {
+		if addr, err := netip.ParseAddr(address); err == nil {
+			used[addr] = struct{}{}
+		}
+	}
+
+	for addr := start; addr.IsValid() && addr.Compare(end) <= 0; addr = addr.Next() {
+		if _, ok := used[addr]; !ok {
+			return addr.String(), nil
+		}
+	}
+	return "", errors.Wrapf(ErrNoFreeAddresses, "all addresses between %s and %s are in use", start, end)
+}
+
+// addressRange returns the first and last usable address of the vip pool.
+func addressRange(vp network.VipPool) (netip.Addr, netip.Addr, error) {
+	props := vp.VipPoolPropertiesFormat
+	if props == nil {
+		return netip.Addr{}, netip.Addr{}, errors.New("vippool has no properties")
+	}
+
+	if props.StartIP != nil && *props.StartIP != "" && props.EndIP != nil && *props.EndIP != "" {
+		start, err := netip.ParseAddr(*props.StartIP)
+		if err != nil {
+			return netip.Addr{}, netip.Addr{}, errors.Wrapf(err, "invalid vippool start address %s", *props.StartIP)
+		}
+		end, err := netip.ParseAddr(*props.EndIP)
+		if err != nil {
+			return netip.Addr{}, netip.Addr{}, errors.Wrapf(err, "invalid vippool end address %s", *props.EndIP)
+		}
+		if start.BitLen() != end.BitLen() || end.Less(start) {
+			return netip.Addr{}, netip.Addr{}, errors.Errorf("invalid vippool range %s-%s", start, end)
+		}
+		return start, end, nil
+	}
+
+	if props.IPPrefix != nil && *props.IPPrefix != "" {
+		prefix, err := netip.ParsePrefix(*props.IPPrefix)
+		if err != nil {
+			return netip.Addr{}, netip.Addr{}, errors.Wrapf(err, "invalid vippool prefix %s", *props.IPPrefix)
+		}
+		prefix = prefix.Masked()
+		// the last address of the prefix has all host bits set
+		bytes := prefix.Addr().AsSlice()
+		for i := prefix.Bits(); i < len(bytes)*8; i++ {
+			bytes[i/8] |= 0x80 >> (i % 8)
+		}
+		start := prefix.Addr()
+		end, _ := netip.AddrFromSlice(bytes)
+		// the network and broadcast addresses of IPv4 prefixes can't be assigned, /31 and /32 have neither
+		if start.Is4() && prefix.Bits() < 31 {
+			start, end = start.Next(), end.Prev()
+		}
+		return start, end, nil
+	}
+
+	return netip.Addr{}, netip.Addr{}, errors.New("vippool has neither an address range nor a prefix")
 }
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vippools
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/microsoft/moc-sdk-for-go/services/network"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func rangePool(start, end string) network.VipPool {
+	return network.VipPool{VipPoolPropertiesFormat: &network.VipPoolPropertiesFormat{StartIP: to.StringPtr(start), EndIP: to.StringPtr(end)}}
+}
+
+func prefixPool(prefix string) network.VipPool {
+	return network.VipPool{VipPoolPropertiesFormat: &network.VipPoolPropertiesFormat{IPPrefix: to.StringPtr(prefix)}}
+}
+
+// TestContains verifies that both ends of the range or prefix of a vip pool are part of it.
+func TestContains(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    network.VipPool
+		address string
+		want    bool
+		wantErr bool
+	}{
+		{name: "first address", pool: rangePool("10.0.0.10", "10.0.0.20"), address: "10.0.0.10", want: true},
+		{name: "last address", pool: rangePool("10.0.0.10", "10.0.0.20"), address: "10.0.0.20", want: true},
+		{name: "before range", pool: rangePool("10.0.0.10", "10.0.0.20"), address: "10.0.0.9"},
+		{name: "after range", pool: rangePool("10.0.0.10", "10.0.0.20"), address: "10.0.0.21"},
+		{name: "first address of prefix", pool: prefixPool("10.0.1.0/24"), address: "10.0.1.1", want: true},
+		{name: "last address of prefix", pool: prefixPool("10.0.1.0/24"), address: "10.0.1.254", want: true},
+		{name: "network address of prefix", pool: prefixPool("10.0.1.0/24"), address: "10.0.1.0"},
+		{name: "broadcast address of prefix", pool: prefixPool("10.0.1.0/24"), address: "10.0.1.255"},
+		{name: "outside prefix", pool: prefixPool("10.0.1.0/24"), address: "10.0.2.0"},
+		{name: "unmasked prefix", pool: prefixPool("10.0.1.7/24"), address: "10.0.1.1", want: true},
+		{name: "point-to-point prefix", pool: prefixPool("10.0.1.0/31"), address: "10.0.1.0", want: true},
+		{name: "ipv6 range", pool: rangePool("fd00::10", "fd00::20"), address: "fd00::1f", want: true},
+		{name: "ipv6 prefix", pool: prefixPool("fd00:1::/120"), address: "fd00:1::ff", want: true},
+		{name: "ipv4 address in ipv6 pool", pool: rangePool("fd00::10", "fd00::20"), address: "10.0.0.15"},
+		{name: "mixed families", pool: rangePool("10.0.0.10", "fd00::20"), address: "10.0.0.15", wantErr: true},
+		{name: "inverted range", pool: rangePool("10.0.0.20", "10.0.0.10"), address: "10.0.0.15", wantErr: true},
+		{name: "invalid address", pool: rangePool("10.0.0.10", "10.0.0.20"), address: "10.0.0", wantErr: true},
+		{name: "no range", pool: network.VipPool{VipPoolPropertiesFormat: &network.VipPoolPropertiesFormat{}}, address: "10.0.0.15", wantErr: true},
+		{name: "no properties", pool: network.VipPool{}, address: "10.0.0.15", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			contains, err := Contains(tc.pool, tc.address)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(contains).To(Equal(tc.want))
+		})
+	}
+}
+
+// TestNextFreeAddress verifies that the first address of a vip pool that isn't in use is allocated.
+func TestNextFreeAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    network.VipPool
+		inUse   []string
+		want    string
+		wantErr error
+	}{
+		{name: "empty pool", pool: rangePool("10.0.0.10", "10.0.0.12"), want: "10.0.0.10"},
+		{name: "skips used addresses", pool: rangePool("10.0.0.10", "10.0.0.12"), inUse: []string{"10.0.0.10", "10.0.0.11"}, want: "10.0.0.12"},
+		{name: "fills gaps", pool: rangePool("10.0.0.10", "10.0.0.12"), inUse: []string{"10.0.0.10", "10.0.0.12"}, want: "10.0.0.11"},
+		{name: "ignores addresses outside the pool", pool: rangePool("10.0.0.10", "10.0.0.12"), inUse: []string{"10.0.0.9", "invalid"}, want: "10.0.0.10"},
+		{
+			name:    "exhausted pool",
+			pool:    rangePool("10.0.0.10", "10.0.0.12"),
+			inUse:   []string{"10.0.0.10", "10.0.0.11", "10.0.0.12"},
+			wantErr: ErrNoFreeAddresses,
+		},
+		{name: "single address pool", pool: rangePool("10.0.0.10", "10.0.0.10"), want: "10.0.0.10"},
+		{name: "prefix skips the network address", pool: prefixPool("10.0.1.0/30"), want: "10.0.1.1"},
+		{name: "prefix", pool: prefixPool("10.0.1.0/30"), inUse: []string{"10.0.1.1"}, want: "10.0.1.2"},
+		{name: "exhausted prefix skips the broadcast address", pool: prefixPool("10.0.1.0/30"), inUse: []string{"10.0.1.1", "10.0.1.2"}, wantErr: ErrNoFreeAddresses},
+		{name: "single address prefix", pool: prefixPool("10.0.1.7/32"), want: "10.0.1.7"},
+		{name: "ipv6 range", pool: rangePool("fd00::ffff", "fd00::1:1"), inUse: []string{"fd00::ffff"}, want: "fd00::1:0"},
+		{name: "exhausted ipv6 prefix", pool: prefixPool("fd00::/127"), inUse: []string{"fd00::", "fd00::1"}, wantErr: ErrNoFreeAddresses},
+		{name: "end of address space", pool: rangePool("255.255.255.254", "255.255.255.255"), inUse: []string{"255.255.255.254", "255.255.255.255"}, wantErr: ErrNoFreeAddresses},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			address, err := NextFreeAddress(tc.pool, tc.inUse)
+			if tc.wantErr != nil {
+				g.Expect(errors.Is(err, tc.wantErr)).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(address).To(Equal(tc.want))
+		})
+	}
+}
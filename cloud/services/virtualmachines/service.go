@@ -25,6 +25,8 @@ import (
 )
 
 var _ azurestackhci.Service = (*Service)(nil)
+var _ azurestackhci.PowerService = (*Service)(nil)
+var _ azurestackhci.PrecheckService = (*Service)(nil)
 
 // Service provides operations on virtual machines.
 type Service struct {
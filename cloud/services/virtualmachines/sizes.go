@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachines
+
+import (
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	wssdcommon "github.com/microsoft/moc/common"
+	"github.com/pkg/errors"
+)
+
+// GetSize resolves a VM size through the MOC VM size catalog. The catalog is the static list of predefined sizes built
+// into the MOC SDK, it doesn't query the cloud agent. Custom sizes aren't in the catalog, their cpu and memory come
+// from the custom size of the spec instead.
+func GetSize(vmSize string, customSize *infrav1.VMCustomSize) (*wssdcommon.VmSize, error) {
+	if compute.VirtualMachineSizeTypes(vmSize) == compute.VirtualMachineSizeTypesCustom {
+		if customSize == nil {
+			return nil, errors.Errorf("vm size %q requires a custom size", vmSize)
+		}
+		return &wssdcommon.VmSize{CpuCount: int(customSize.CpuCount), MemoryMB: int(customSize.MemoryMB)}, nil
+	}
+
+	sizeType := compute.GetCloudVirtualMachineSizeFromCloudSdkVirtualMachineSize(compute.VirtualMachineSizeTypes(vmSize))
+	size, ok := wssdcommon.VirtualMachineSize_value[sizeType]
+	if !ok {
+		return nil, errors.Errorf("vm size %q isn't in the moc vm size catalog", vmSize)
+	}
+	return &size, nil
+}
@@ -33,8 +33,11 @@ import (
 	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
 	"github.com/microsoft/moc-sdk-for-go/services/compute"
 	"github.com/microsoft/moc-sdk-for-go/services/network"
+	mocerrors "github.com/microsoft/moc/pkg/errors"
+	moccodes "github.com/microsoft/moc/pkg/errors/codes"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -48,8 +51,8 @@ type Spec struct {
 	NICName             string
 	SSHKeyData          []string
 	Size                string
+	CustomSize          *infrav1.VMCustomSize
 	GpuCount            int32
-	Zone                string
 	Image               infrav1.Image
 	OSDisk              infrav1.OSDisk
 	CustomData          string
@@ -168,7 +171,8 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 			},
 			VmType: vmSpec.VMType,
 			HardwareProfile: &compute.HardwareProfile{
-				VMSize: compute.VirtualMachineSizeTypes(vmSpec.Size),
+				VMSize:     compute.VirtualMachineSizeTypes(vmSpec.Size),
+				CustomSize: generateCustomSize(vmSpec.CustomSize),
 			},
 		},
 	}
@@ -188,19 +192,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 			},
 		}
 	}
-	if vmSpec.AvailabilitySetName != "" {
-		virtualMachine.VirtualMachineProperties.AvailabilitySetProfile = &compute.AvailabilitySetReference{
-			Name:      to.StringPtr(vmSpec.AvailabilitySetName),
-			GroupName: to.StringPtr(s.Scope.GetResourceGroup()),
-		}
-	}
-
-	if vmSpec.PlacementGroupName != "" {
-		virtualMachine.VirtualMachineProperties.PlacementGroupProfile = &compute.PlacementGroupReference{
-			Name:      to.StringPtr(vmSpec.PlacementGroupName),
-			GroupName: to.StringPtr(s.Scope.GetResourceGroup()),
-		}
-	}
+	applyPlacementProfiles(&virtualMachine, vmSpec, s.Scope.GetResourceGroup())
 
 	_, err = s.Client.CreateOrUpdate(
 		ctx,
@@ -217,6 +209,60 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 	return err
 }
 
+// Precheck checks that a host has the capacity for the virtual machine before its network interface and disk are
+// created. Returns false with the reason reported by MOC when the virtual machine doesn't fit, and an error when
+// the pre-check couldn't be run.
+func (s *Service) Precheck(ctx context.Context, spec interface{}) (bool, string, error) {
+	vmSpec, ok := spec.(*Spec)
+	if !ok {
+		return false, "", errors.New("invalid vm specification")
+	}
+
+	storageProfile, err := generateStorageProfile(*vmSpec)
+	if err != nil {
+		return false, "", err
+	}
+
+	virtualMachine := compute.VirtualMachine{
+		Name: to.StringPtr(vmSpec.Name),
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			StorageProfile: storageProfile,
+			OsProfile: &compute.OSProfile{
+				OsType: compute.OperatingSystemTypes(vmSpec.OSDisk.OSType),
+			},
+			VmType: vmSpec.VMType,
+			HardwareProfile: &compute.HardwareProfile{
+				VMSize:             compute.VirtualMachineSizeTypes(vmSpec.Size),
+				CustomSize:         generateCustomSize(vmSpec.CustomSize),
+				VirtualMachineGPUs: generateGpuList(vmSpec.GpuCount),
+			},
+		},
+	}
+	applyPlacementProfiles(&virtualMachine, vmSpec, s.Scope.GetResourceGroup())
+
+	fits, err := s.Client.Precheck(ctx, s.Scope.GetResourceGroup(), []*compute.VirtualMachine{&virtualMachine})
+	if err != nil {
+		if reason, ok := placementReason(err); ok {
+			return false, reason, nil
+		}
+		return false, "", errors.Wrapf(err, "failed to precheck vm %s", vmSpec.Name)
+	}
+	return fits, "", nil
+}
+
+// placementReason returns the reason MOC reports when no host has the capacity for the virtual machine. The MOC SDK
+// returns the reason as a plain error built from the precheck response. gRPC failures carry a status, and failures to
+// build the request wrap a MOC error code, neither of them is a placement reason.
+func placementReason(err error) (string, bool) {
+	if _, isRPCError := status.FromError(err); isRPCError {
+		return "", false
+	}
+	if errors.Cause(err) != err || mocerrors.GetMocErrorCode(err) != moccodes.Unknown {
+		return "", false
+	}
+	return err.Error(), true
+}
+
 // Delete deletes the virtual machine with the provided name.
 func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	telemetry.WriteMocInfoLog(ctx, s.Scope)
@@ -241,6 +287,161 @@ func (s *Service) Delete(ctx context.Context, spec interface{}) error {
 	return err
 }
 
+// Start starts the virtual machine.
+func (s *Service) Start(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	vmSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid vm Specification")
+	}
+	logger := s.Scope.GetLogger()
+	logger.Info("starting vm", "vm", vmSpec.Name)
+	err := s.Client.Start(ctx, s.Scope.GetResourceGroup(), vmSpec.Name)
+	telemetry.WriteMocOperationLog(s.Scope.GetLogger(), telemetry.Start, s.Scope.GetCustomResourceTypeWithName(), telemetry.VirtualMachine,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), vmSpec.Name), nil, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to start vm %s in resource group %s", vmSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully started vm", "vm", vmSpec.Name)
+	return nil
+}
+
+// Stop turns the virtual machine off through the cloud agent.
+func (s *Service) Stop(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	vmSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid vm Specification")
+	}
+	logger := s.Scope.GetLogger()
+	logger.Info("stopping vm", "vm", vmSpec.Name)
+	err := s.Client.Stop(ctx, s.Scope.GetResourceGroup(), vmSpec.Name)
+	telemetry.WriteMocOperationLog(s.Scope.GetLogger(), telemetry.Stop, s.Scope.GetCustomResourceTypeWithName(), telemetry.VirtualMachine,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), vmSpec.Name), nil, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to stop vm %s in resource group %s", vmSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully stopped vm", "vm", vmSpec.Name)
+	return nil
+}
+
+// Shutdown shuts the guest operating system of the virtual machine down through the guest agent.
+func (s *Service) Shutdown(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	vmSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid vm Specification")
+	}
+	logger := s.Scope.GetLogger()
+	logger.Info("shutting down vm", "vm", vmSpec.Name)
+	err := s.runGuestCommand(ctx, vmSpec.Name, generateShutdownScript(vmSpec.Image.OSType))
+	telemetry.WriteMocOperationLog(s.Scope.GetLogger(), telemetry.Shutdown, s.Scope.GetCustomResourceTypeWithName(), telemetry.VirtualMachine,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), vmSpec.Name), nil, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to shut down vm %s in resource group %s", vmSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully shut down vm", "vm", vmSpec.Name)
+	return nil
+}
+
+// Restart reboots the guest operating system of the virtual machine through the guest agent.
+func (s *Service) Restart(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	vmSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid vm Specification")
+	}
+	logger := s.Scope.GetLogger()
+	logger.Info("restarting vm", "vm", vmSpec.Name)
+	err := s.runGuestCommand(ctx, vmSpec.Name, generateRestartScript(vmSpec.Image.OSType))
+	telemetry.WriteMocOperationLog(s.Scope.GetLogger(), telemetry.Restart, s.Scope.GetCustomResourceTypeWithName(), telemetry.VirtualMachine,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), vmSpec.Name), nil, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to restart vm %s in resource group %s", vmSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully restarted vm", "vm", vmSpec.Name)
+	return nil
+}
+
+// PowerCycle turns the virtual machine off and on again without involving the guest operating system.
+func (s *Service) PowerCycle(ctx context.Context, spec interface{}) error {
+	telemetry.WriteMocInfoLog(ctx, s.Scope)
+	vmSpec, ok := spec.(*Spec)
+	if !ok {
+		return errors.New("invalid vm Specification")
+	}
+	logger := s.Scope.GetLogger()
+	logger.Info("power cycling vm", "vm", vmSpec.Name)
+	err := s.Client.Restart(ctx, s.Scope.GetResourceGroup(), vmSpec.Name)
+	telemetry.WriteMocOperationLog(s.Scope.GetLogger(), telemetry.PowerCycle, s.Scope.GetCustomResourceTypeWithName(), telemetry.VirtualMachine,
+		telemetry.GenerateMocResourceName(s.Scope.GetResourceGroup(), vmSpec.Name), nil, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to power cycle vm %s in resource group %s", vmSpec.Name, s.Scope.GetResourceGroup())
+	}
+
+	logger.Info("successfully power cycled vm", "vm", vmSpec.Name)
+	return nil
+}
+
+// runGuestCommand runs the script in the guest operating system of the virtual machine through the guest agent.
+func (s *Service) runGuestCommand(ctx context.Context, vmName, script string) error {
+	request := &compute.VirtualMachineRunCommandRequest{
+		Source: &compute.VirtualMachineRunCommandScriptSource{
+			Script: to.StringPtr(script),
+		},
+	}
+	response, err := s.Client.RunCommand(ctx, s.Scope.GetResourceGroup(), vmName, request)
+	if err != nil {
+		return err
+	}
+	if response != nil && response.InstanceView != nil && response.InstanceView.ExecutionState == compute.ExecutionStateFailed {
+		return errors.Errorf("command failed: %s", to.String(response.InstanceView.Error))
+	}
+	return nil
+}
+
+// generateRestartScript returns the command rebooting the guest operating system. The reboot is delayed
+// so that the guest agent can report the command as completed.
+func generateRestartScript(os infrav1.OSType) string {
+	switch os {
+	case infrav1.OSTypeWindows, infrav1.OSTypeWindows2022:
+		return "shutdown /r /t 5"
+	default:
+		return "nohup sh -c 'sleep 5; systemctl reboot' >/dev/null 2>&1 &"
+	}
+}
+
+// generateShutdownScript returns the command shutting the guest operating system down, delayed like a restart.
+func generateShutdownScript(os infrav1.OSType) string {
+	switch os {
+	case infrav1.OSTypeWindows, infrav1.OSTypeWindows2022:
+		return "shutdown /s /t 5"
+	default:
+		return "nohup sh -c 'sleep 5; systemctl poweroff' >/dev/null 2>&1 &"
+	}
+}
+
+// applyPlacementProfiles references the availability set and placement group of the virtual machine.
+func applyPlacementProfiles(virtualMachine *compute.VirtualMachine, vmSpec *Spec, group string) {
+	if vmSpec.AvailabilitySetName != "" {
+		virtualMachine.VirtualMachineProperties.AvailabilitySetProfile = &compute.AvailabilitySetReference{
+			Name:      to.StringPtr(vmSpec.AvailabilitySetName),
+			GroupName: to.StringPtr(group),
+		}
+	}
+
+	if vmSpec.PlacementGroupName != "" {
+		virtualMachine.VirtualMachineProperties.PlacementGroupProfile = &compute.PlacementGroupReference{
+			Name:      to.StringPtr(vmSpec.PlacementGroupName),
+			GroupName: to.StringPtr(group),
+		}
+	}
+}
+
 // generateStorageProfile generates a pointer to a compute.StorageProfile which can utilized for VM creation.
 func generateStorageProfile(vmSpec Spec) (*compute.StorageProfile, error) {
 	osDisk := &compute.OSDisk{
@@ -387,3 +588,13 @@ func generateGpuList(gpuCount int32) []*compute.VirtualMachineGPU {
 	}
 	return gpuList
 }
+
+func generateCustomSize(customSize *infrav1.VMCustomSize) *compute.VirtualMachineCustomSize {
+	if customSize == nil {
+		return nil
+	}
+	return &compute.VirtualMachineCustomSize{
+		CpuCount: to.Int32Ptr(customSize.CpuCount),
+		MemoryMB: to.Int32Ptr(customSize.MemoryMB),
+	}
+}
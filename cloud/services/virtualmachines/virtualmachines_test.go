@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualmachines
+
+import (
+	"testing"
+
+	mocerrors "github.com/microsoft/moc/pkg/errors"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestPlacementReason verifies that only the reason MOC reports for a failed placement is treated as a lack of
+// capacity, and that every other precheck failure is returned as an error.
+func TestPlacementReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+		wantOK     bool
+	}{
+		{
+			name:       "placement failed",
+			err:        errors.New("no node has 16384 MB of memory available"),
+			wantReason: "no node has 16384 MB of memory available",
+			wantOK:     true,
+		},
+		{
+			name: "moc unreachable",
+			err:  status.Error(codes.Unavailable, "connection refused"),
+		},
+		{
+			name: "invalid request",
+			err:  mocerrors.Wrapf(mocerrors.InvalidInput, "Virtual Machine name is missing"),
+		},
+		{
+			name: "moc error code",
+			err:  mocerrors.InvalidGroup,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			reason, ok := placementReason(tc.err)
+			g.Expect(ok).To(Equal(tc.wantOK))
+			g.Expect(reason).To(Equal(tc.wantReason))
+		})
+	}
+}
@@ -35,7 +35,7 @@ const (
 type Spec struct {
 	Name  string
 	Group string
-	CIDR  string
+	CIDRs []string
 }
 
 // Get provides information about a virtual network.
@@ -83,7 +83,7 @@ func (s *Service) Reconcile(ctx context.Context, spec interface{}) error {
 		Type: &networkType,
 		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
 			AddressSpace: &network.AddressSpace{
-				AddressPrefixes: &[]string{vnetSpec.CIDR},
+				AddressPrefixes: &vnetSpec.CIDRs,
 			},
 		},
 		Tags: map[string]*string{OWNER: &caph},
@@ -18,6 +18,10 @@ type MocResourceType string
 const (
 	LoadBalancer     MocResourceType = "LoadBalancer"
 	VipPool          MocResourceType = "VipPool"
+	AvailabilitySet  MocResourceType = "AvailabilitySet"
+	PlacementGroup   MocResourceType = "PlacementGroup"
+	GalleryImage     MocResourceType = "GalleryImage"
+	StorageContainer MocResourceType = "StorageContainer"
 	VirtualNetwork   MocResourceType = "VirtualNetwork"
 	NetworkInterface MocResourceType = "NetworkInterface"
 	Disk             MocResourceType = "Disk"
@@ -42,6 +46,11 @@ const (
 	Update         Operation = "Update"
 	Delete         Operation = "Delete"
 	Get            Operation = "Get"
+	Start          Operation = "Start"
+	Stop           Operation = "Stop"
+	Shutdown       Operation = "Shutdown"
+	Restart        Operation = "Restart"
+	PowerCycle     Operation = "PowerCycle"
 )
 
 type OperationLog struct {
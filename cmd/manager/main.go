@@ -66,16 +66,20 @@ func init() {
 }
 
 var (
-	enableLeaderElection                   bool
-	watchNamespace                         string
-	profilerAddress                        string
-	azureStackHCIClusterConcurrency        int
-	azureStackHCIMachineConcurrency        int
-	azureStackHCIloadBalancerConcurrency   int
-	azureStackHCIVirtualMachineConcurrency int
-	syncPeriod                             time.Duration
-	healthAddr                             string
-	webhookPort                            int
+	enableLeaderElection                    bool
+	watchNamespace                          string
+	profilerAddress                         string
+	azureStackHCIClusterConcurrency         int
+	azureStackHCIMachineConcurrency         int
+	azureStackHCIloadBalancerConcurrency    int
+	azureStackHCIVirtualMachineConcurrency  int
+	azureStackHCIRemediationConcurrency     int
+	azureStackHCIMachinePoolConcurrency     int
+	azureStackHCIMachineTemplateConcurrency int
+	azureStackHCIImageConcurrency           int
+	syncPeriod                              time.Duration
+	healthAddr                              string
+	webhookPort                             int
 )
 
 func InitFlags(fs *pflag.FlagSet) {
@@ -125,6 +129,30 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Number of AzureStackHCIVirtualMachines to process simultaneously",
 	)
 
+	flag.IntVar(&azureStackHCIRemediationConcurrency,
+		"azurestackhci-remediation-concurrency",
+		10,
+		"Number of AzureStackHCIRemediations to process simultaneously",
+	)
+
+	flag.IntVar(&azureStackHCIMachinePoolConcurrency,
+		"azurestackhci-machinepool-concurrency",
+		10,
+		"Number of AzureStackHCIMachinePools to process simultaneously",
+	)
+
+	flag.IntVar(&azureStackHCIMachineTemplateConcurrency,
+		"azurestackhci-machinetemplate-concurrency",
+		10,
+		"Number of AzureStackHCIMachineTemplates to process simultaneously",
+	)
+
+	flag.IntVar(&azureStackHCIImageConcurrency,
+		"azurestackhci-image-concurrency",
+		10,
+		"Number of AzureStackHCIImages to process simultaneously",
+	)
+
 	flag.DurationVar(&syncPeriod,
 		"sync-period",
 		10*time.Minute,
@@ -247,6 +275,53 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureStackHCIVirtualMachine")
 		os.Exit(1)
 	}
+	if err = (&controllers.AzureStackHCIRemediationReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("AzureStackHCIRemediation"),
+		Recorder: mgr.GetEventRecorderFor("azurestackhciremediation-reconciler"),
+	}).SetupWithManager(mgr, controller.Options{
+		MaxConcurrentReconciles: azureStackHCIRemediationConcurrency,
+		RateLimiter:             workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
+	}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AzureStackHCIRemediation")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.AzureStackHCIMachinePoolReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("AzureStackHCIMachinePool"),
+		Recorder: mgr.GetEventRecorderFor("azurestackhcimachinepool-reconciler"),
+	}).SetupWithManager(mgr, controller.Options{
+		MaxConcurrentReconciles: azureStackHCIMachinePoolConcurrency,
+		RateLimiter:             workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
+	}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AzureStackHCIMachinePool")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.AzureStackHCIMachineTemplateReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("AzureStackHCIMachineTemplate"),
+		Recorder: mgr.GetEventRecorderFor("azurestackhcimachinetemplate-reconciler"),
+	}).SetupWithManager(mgr, controller.Options{
+		MaxConcurrentReconciles: azureStackHCIMachineTemplateConcurrency,
+		RateLimiter:             workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
+	}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AzureStackHCIMachineTemplate")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.AzureStackHCIImageReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("AzureStackHCIImage"),
+		Recorder: mgr.GetEventRecorderFor("azurestackhciimage-reconciler"),
+	}).SetupWithManager(mgr, controller.Options{
+		MaxConcurrentReconciles: azureStackHCIImageConcurrency,
+		RateLimiter:             workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
+	}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AzureStackHCIImage")
+		os.Exit(1)
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -265,6 +340,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := (&infrav1beta2.AzureStackHCIClusterTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "AzureStackHCIClusterTemplate")
+		os.Exit(1)
+	}
+
 	if err := (&infrav1beta2.AzureStackHCIVirtualMachine{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AzureStackHCIVirtualMachine")
 		os.Exit(1)
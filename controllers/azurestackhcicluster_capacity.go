@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"slices"
+	"time"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/converters"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/nodes"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/virtualmachines"
+	"github.com/microsoft/moc-sdk-for-go/services/cloud"
+	wssdcommon "github.com/microsoft/moc/rpc/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// clusterCapacityRefreshInterval is the age after which the capacity summary of the cluster is collected again.
+	// The cluster is only requeued to refresh it while virtual machines wait for capacity.
+	clusterCapacityRefreshInterval = 5 * time.Minute
+)
+
+// reconcileCapacity collects the capacity summary of the cluster when it is older than clusterCapacityRefreshInterval.
+// The summary is informational, so failing to collect it doesn't fail the reconciliation. Returns true while virtual
+// machines of the cluster wait for a host to have the capacity for them.
+func (r *AzureStackHCIClusterReconciler) reconcileCapacity(clusterScope *scope.ClusterScope) bool {
+	vmList := &infrav1.AzureStackHCIVirtualMachineList{}
+	if err := r.Client.List(clusterScope.Context, vmList, client.InNamespace(clusterScope.Namespace())); err != nil {
+		clusterScope.Error(err, "Unable to list the virtual machines of the cluster")
+		return false
+	}
+	var vmSizes []string
+	waitingForCapacity := false
+	for _, vm := range vmList.Items {
+		if vm.Spec.ClusterName != clusterScope.Name() {
+			continue
+		}
+		if !slices.Contains(vmSizes, vm.Spec.VMSize) {
+			vmSizes = append(vmSizes, vm.Spec.VMSize)
+		}
+		// the next capacity check is only scheduled while the virtual machine isn't created yet
+		if vm.Status.NextCapacityCheckTime != nil && vm.Status.FailureReason == nil {
+			waitingForCapacity = true
+		}
+	}
+	slices.Sort(vmSizes)
+
+	status := &clusterScope.AzureStackHCICluster.Status
+	if status.Capacity != nil && status.Capacity.LastUpdateTime != nil && time.Since(status.Capacity.LastUpdateTime.Time) < clusterCapacityRefreshInterval {
+		return waitingForCapacity
+	}
+
+	capacity, err := r.collectCapacity(clusterScope, vmSizes)
+	if err != nil {
+		clusterScope.Error(err, "Unable to collect the capacity of the cluster", "reason", azurestackhci.ClassifyError(err, infrav1.ClusterReconciliationFailedReason).Reason)
+		return waitingForCapacity
+	}
+	now := metav1.Now()
+	capacity.LastUpdateTime = &now
+	status.Capacity = capacity
+	return waitingForCapacity
+}
+
+// collectCapacity summarizes the resources available on the hosts of the location of the cluster, and how many
+// more virtual machines of the VM sizes used by the cluster fit on them.
+func (r *AzureStackHCIClusterReconciler) collectCapacity(clusterScope *scope.ClusterScope, vmSizes []string) (*infrav1.ClusterCapacity, error) {
+	nodesInterface, err := nodes.NewService(clusterScope).Get(clusterScope.Context, &nodes.Spec{Location: clusterScope.Location()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the hosts of location %s", clusterScope.Location())
+	}
+	nodeList, ok := nodesInterface.([]cloud.Node)
+	if !ok {
+		return nil, errors.New("returned incorrect nodes interface")
+	}
+
+	hosts := make([]*wssdcommon.NodeInfo, 0, len(nodeList))
+	for _, node := range nodeList {
+		info, err := converters.SDKToNodeInfo(node)
+		if err != nil {
+			clusterScope.Info("Skipping host in the capacity of the cluster", "error", err.Error())
+			continue
+		}
+		hosts = append(hosts, info)
+	}
+
+	return clusterCapacity(hosts, vmSizes), nil
+}
+
+// clusterCapacity sums the resources available on the hosts, and how many virtual machines of each VM size fit on them.
+// VM sizes that aren't in the MOC VM size catalog are left out, so are Custom sizes whose shape differs per virtual machine.
+func clusterCapacity(hosts []*wssdcommon.NodeInfo, vmSizes []string) *infrav1.ClusterCapacity {
+	var cpu, gpus int64
+	var memory uint64
+	for _, host := range hosts {
+		available := host.GetAvailability()
+		cpu += int64(available.GetProcessor().GetLogicalprocessors())
+		memory += available.GetMemory().GetSizeBytes()
+		gpus += int64(len(available.GetHostGPUs()))
+	}
+
+	capacity := &infrav1.ClusterCapacity{
+		Hosts: int32(len(hosts)), //nolint:gosec // G115
+		Available: corev1.ResourceList{
+			corev1.ResourceCPU:    *resource.NewQuantity(cpu, resource.DecimalSI),
+			corev1.ResourceMemory: *resource.NewQuantity(int64(memory), resource.BinarySI), //nolint:gosec // G115
+		},
+	}
+	if gpus > 0 {
+		capacity.Available[gpuResourceName] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	}
+
+	for _, vmSize := range vmSizes {
+		size, err := virtualmachines.GetSize(vmSize, nil)
+		if err != nil {
+			continue
+		}
+		var fit int32
+		for _, host := range hosts {
+			fit += vmsThatFit(host.GetAvailability(), size.MemoryMB, size.GpuCount)
+		}
+		capacity.VMSizes = append(capacity.VMSizes, infrav1.VMSizeCapacity{VMSize: vmSize, Available: fit})
+	}
+	return capacity
+}
+
+// vmsThatFit returns how many virtual machines with the memory and GPUs fit in the resources available on a host.
+// Processors aren't accounted for since Hyper-V overcommits them.
+func vmsThatFit(available *wssdcommon.Resources, memoryMB, gpuCount int) int32 {
+	if memoryMB <= 0 {
+		return 0
+	}
+	fit := available.GetMemory().GetSizeBytes() / (uint64(memoryMB) * 1024 * 1024)
+	if gpuCount > 0 {
+		fit = min(fit, uint64(len(available.GetHostGPUs())/gpuCount))
+	}
+	return int32(fit) //nolint:gosec // G115
+}
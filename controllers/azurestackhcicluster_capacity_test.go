@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	wssdcommon "github.com/microsoft/moc/rpc/common"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+)
+
+// TestClusterCapacity verifies that the resources available on the hosts are summed, and that the virtual machines
+// of each VM size that fit are counted per host.
+func TestClusterCapacity(t *testing.T) {
+	g := NewWithT(t)
+
+	const gib = 1024 * 1024 * 1024
+	host := func(memoryGiB uint64, gpus int) *wssdcommon.NodeInfo {
+		available := &wssdcommon.Resources{
+			Processor: &wssdcommon.Processor{Logicalprocessors: 16},
+			Memory:    &wssdcommon.PhysicalMemory{SizeBytes: memoryGiB * gib},
+		}
+		for i := 0; i < gpus; i++ {
+			available.HostGPUs = append(available.HostGPUs, &wssdcommon.HostGPU{})
+		}
+		return &wssdcommon.NodeInfo{Availability: available}
+	}
+	hosts := []*wssdcommon.NodeInfo{host(20, 0), host(9, 1)}
+
+	// Standard_A4_v2 has 8GiB of memory, Standard_NK6 has 12GiB and a GPU
+	capacity := clusterCapacity(hosts, []string{"Standard_A4_v2", "Standard_NK6", "Custom_Size"})
+	g.Expect(capacity.Hosts).To(Equal(int32(2)))
+	g.Expect(capacity.Available.Cpu().Value()).To(Equal(int64(32)))
+	g.Expect(capacity.Available.Memory().Equal(*resource.NewQuantity(29*gib, resource.BinarySI))).To(BeTrue())
+	g.Expect(capacity.Available).To(HaveKeyWithValue(gpuResourceName, *resource.NewQuantity(1, resource.DecimalSI)))
+	g.Expect(capacity.VMSizes).To(Equal([]infrav1.VMSizeCapacity{
+		{VMSize: "Standard_A4_v2", Available: 3},
+		{VMSize: "Standard_NK6", Available: 0},
+	}))
+}
+
+// TestVMsThatFit verifies that the virtual machines that fit on a host are limited by its memory and GPUs.
+func TestVMsThatFit(t *testing.T) {
+	const mib = 1024 * 1024
+	tests := []struct {
+		name     string
+		memory   uint64
+		gpus     int
+		memoryMB int
+		gpuCount int
+		want     int32
+	}{
+		{name: "memory bound", memory: 10240 * mib, memoryMB: 4096, want: 2},
+		{name: "gpu bound", memory: 10240 * mib, gpus: 1, memoryMB: 1024, gpuCount: 1, want: 1},
+		{name: "no gpu", memory: 10240 * mib, memoryMB: 1024, gpuCount: 1, want: 0},
+		{name: "unknown memory", memory: 10240 * mib, want: 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			available := &wssdcommon.Resources{Memory: &wssdcommon.PhysicalMemory{SizeBytes: tc.memory}}
+			for i := 0; i < tc.gpus; i++ {
+				available.HostGPUs = append(available.HostGPUs, &wssdcommon.HostGPU{})
+			}
+			g.Expect(vmsThatFit(available, tc.memoryMB, tc.gpuCount)).To(Equal(tc.want))
+		})
+	}
+}
+
+// TestReconcileCapacityWaiting verifies that the cluster is only requeued to refresh its capacity summary while
+// virtual machines of the cluster wait for capacity.
+func TestReconcileCapacityWaiting(t *testing.T) {
+	nextCheck := &metav1.Time{Time: time.Now().Add(time.Minute)}
+	insufficientResources := capierrors.InsufficientResourcesMachineError
+	vm := func(name, clusterName string, status infrav1.AzureStackHCIVirtualMachineStatus) *infrav1.AzureStackHCIVirtualMachine {
+		return &infrav1.AzureStackHCIVirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       infrav1.AzureStackHCIVirtualMachineSpec{ClusterName: clusterName, VMSize: "Standard_A4_v2"},
+			Status:     status,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		vmList      []client.Object
+		wantWaiting bool
+	}{
+		{
+			name: "no virtual machines",
+		},
+		{
+			name:   "virtual machines created",
+			vmList: []client.Object{vm("created", "cluster", infrav1.AzureStackHCIVirtualMachineStatus{})},
+		},
+		{
+			name:        "virtual machine waiting for capacity",
+			vmList:      []client.Object{vm("waiting", "cluster", infrav1.AzureStackHCIVirtualMachineStatus{NextCapacityCheckTime: nextCheck})},
+			wantWaiting: true,
+		},
+		{
+			name: "virtual machine out of capacity",
+			vmList: []client.Object{vm("failed", "cluster", infrav1.AzureStackHCIVirtualMachineStatus{
+				NextCapacityCheckTime: nextCheck,
+				FailureReason:         &insufficientResources,
+			})},
+		},
+		{
+			name:   "virtual machine of another cluster waiting for capacity",
+			vmList: []client.Object{vm("other", "other", infrav1.AzureStackHCIVirtualMachineStatus{NextCapacityCheckTime: nextCheck})},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.vmList...).Build()
+
+			// the summary is current, so MOC isn't queried
+			now := metav1.Now()
+			clusterScope := &scope.ClusterScope{
+				Logger:  logr.Discard(),
+				Client:  c,
+				Context: context.Background(),
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}},
+				AzureStackHCICluster: &infrav1.AzureStackHCICluster{
+					Status: infrav1.AzureStackHCIClusterStatus{Capacity: &infrav1.ClusterCapacity{LastUpdateTime: &now}},
+				},
+			}
+			r := &AzureStackHCIClusterReconciler{Client: c}
+
+			g.Expect(r.reconcileCapacity(clusterScope)).To(Equal(tc.wantWaiting))
+		})
+	}
+}
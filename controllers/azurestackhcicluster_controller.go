@@ -20,6 +20,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -28,20 +29,24 @@ import (
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/telemetry"
 	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
-	mocerrors "github.com/microsoft/moc/pkg/errors"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/paused"
+	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -57,6 +62,11 @@ func (r *AzureStackHCIClusterReconciler) SetupWithManager(mgr ctrl.Manager, opti
 		WithOptions(options).
 		WithLogConstructor(r.ConstructLogger).
 		For(&infrav1.AzureStackHCICluster{}).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(util.ClusterToInfrastructureMapFunc(context.Background(), infrav1.GroupVersion.WithKind("AzureStackHCICluster"), mgr.GetClient(), &infrav1.AzureStackHCICluster{})),
+			builder.WithPredicates(predicates.ClusterPausedTransitions(mgr.GetScheme(), r.Log)),
+		).
 		Complete(r)
 }
 
@@ -111,6 +121,11 @@ func (r *AzureStackHCIClusterReconciler) Reconcile(ctx context.Context, req ctrl
 
 	log = log.WithValues("cluster", cluster.Name)
 
+	// Return early if the object or Cluster is paused.
+	if isPaused, requeue, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, azureStackHCICluster); err != nil || isPaused || requeue {
+		return reconcile.Result{}, err
+	}
+
 	// Create the scope.
 	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
 		Client:               r.Client,
@@ -153,41 +168,39 @@ func (r *AzureStackHCIClusterReconciler) reconcileNormal(clusterScope *scope.Clu
 		return reconcile.Result{}, err
 	}
 
+	// Publish the failure domains so that Cluster API can spread machines across them.
+	clusterScope.SetFailureDomains(clusterFailureDomains(azureStackHCICluster.Spec.FailureDomains))
+
 	err := newAzureStackHCIClusterReconciler(clusterScope).Reconcile()
 	if err != nil {
-		switch mocerrors.GetErrorCode(err) {
-		case mocerrors.OutOfMemory.Error():
-			notReady := metav1.Condition{
-				Type:    infrav1.NetworkInfrastructureReadyCondition,
-				Status:  metav1.ConditionFalse,
-				Reason:  infrav1.OutOfMemoryReason,
-				Message: err.Error(),
-			}
-			conditions.Set(azureStackHCICluster, notReady)
-		case mocerrors.OutOfCapacity.Error():
-			outOfCapacityCondition := metav1.Condition{
-				Type:    infrav1.NetworkInfrastructureReadyCondition,
-				Status:  metav1.ConditionFalse,
-				Reason:  infrav1.OutOfCapacityReason,
-				Message: err.Error(),
-			}
-			conditions.Set(azureStackHCICluster, outOfCapacityCondition)
-		default:
-			generalErrorCondition := metav1.Condition{
-				Type:    infrav1.NetworkInfrastructureReadyCondition,
-				Status:  metav1.ConditionFalse,
-				Reason:  infrav1.ClusterReconciliationFailedReason,
-				Message: err.Error(),
-			}
-			conditions.Set(azureStackHCICluster, generalErrorCondition)
-		}
+		class := classifyError(err, infrav1.ClusterReconciliationFailedReason, clusterScope)
+		conditions.Set(azureStackHCICluster, metav1.Condition{
+			Type:    infrav1.NetworkInfrastructureReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
 
 		wrappedErr := errors.Wrap(err, "failed to reconcile cluster services")
 		r.Recorder.Eventf(azureStackHCICluster, corev1.EventTypeWarning, "ClusterReconcileFailed", wrappedErr.Error())
 
-		return reconcile.Result{}, wrappedErr
+		return reconcileResultForError(class, wrappedErr)
 	}
 
+	placementsPending, err := r.reconcilePlacements(clusterScope)
+	if err != nil {
+		class := classifyError(err, infrav1.ClusterReconciliationFailedReason, clusterScope)
+		conditions.Set(azureStackHCICluster, metav1.Condition{
+			Type:    infrav1.NetworkInfrastructureReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
+		r.Recorder.Eventf(azureStackHCICluster, corev1.EventTypeWarning, "ClusterReconcileFailed", err.Error())
+		return reconcileResultForError(class, err)
+	}
+	clusterScope.ResetCapacityRetries()
+
 	if ready, err := r.reconcileAzureStackHCILoadBalancer(clusterScope); !ready {
 		if err != nil {
 			return reconcile.Result{}, err
@@ -222,6 +235,17 @@ func (r *AzureStackHCIClusterReconciler) reconcileNormal(clusterScope *scope.Clu
 		Reason: infrav1.InfrastructureReadyReason,
 	})
 
+	waitingForCapacity := r.reconcileCapacity(clusterScope)
+
+	if placementsPending {
+		clusterScope.Info("Waiting for virtual machines to leave the removed availability sets, placement groups and storage containers")
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	if waitingForCapacity {
+		// Keep the capacity summary current while virtual machines wait for capacity.
+		return reconcile.Result{RequeueAfter: clusterCapacityRefreshInterval}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -240,7 +264,8 @@ func (r *AzureStackHCIClusterReconciler) reconcileDelete(clusterScope *scope.Clu
 	// 1. Wait for machines in the cluster to be deleted
 	// 2. Delete the AzureStackHCILoadBalancer
 	// 3. Wait for AzureStackHCILoadBalancer Deletion
-	// 4. Delete the Cluster
+	// 4. Delete the availability sets and placement groups once no virtual machine is in them
+	// 5. Delete the Cluster
 	azhciMachines, err := infrav1util.GetAzureStackHCIMachinesInCluster(clusterScope.Context, clusterScope.Client, clusterScope.AzureStackHCICluster.Namespace, clusterScope.AzureStackHCICluster.Name)
 	if err != nil {
 		wrappedErr := errors.Wrapf(err, "unable to list AzureStackHCIMachines part of AzureStackHCIClusters %s/%s", clusterScope.AzureStackHCICluster.Namespace, clusterScope.AzureStackHCICluster.Name)
@@ -300,15 +325,30 @@ func (r *AzureStackHCIClusterReconciler) reconcileDelete(clusterScope *scope.Clu
 		return reconcile.Result{RequeueAfter: 20 * time.Second}, nil
 	}
 
+	if deleted, err := r.reconcileDeletePlacements(clusterScope); !deleted {
+		if err != nil {
+			class := classifyError(err, infrav1.DeletionFailedReason, nil)
+			conditions.Set(azureStackHCICluster, metav1.Condition{
+				Type:    infrav1.NetworkInfrastructureReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  class.Reason,
+				Message: err.Error(),
+			})
+			return reconcileResultForError(class, err)
+		}
+		return reconcile.Result{RequeueAfter: 20 * time.Second}, nil
+	}
+
 	if err := newAzureStackHCIClusterReconciler(clusterScope).Delete(); err != nil {
+		class := classifyError(err, infrav1.DeletionFailedReason, nil)
 		wrappedErr := errors.Wrapf(err, "error deleting AzureStackHCICluster %s/%s", azureStackHCICluster.Namespace, azureStackHCICluster.Name)
 		r.Recorder.Eventf(azureStackHCICluster, corev1.EventTypeWarning, "FailureClusterDelete", wrappedErr.Error())
 		conditions.Set(azureStackHCICluster, metav1.Condition{
 			Type:    infrav1.NetworkInfrastructureReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  infrav1.DeletionFailedReason,
+			Reason:  class.Reason,
 			Message: err.Error()})
-		return reconcile.Result{}, wrappedErr
+		return reconcileResultForError(class, wrappedErr)
 	}
 
 	r.Recorder.Eventf(azureStackHCICluster, corev1.EventTypeNormal, "SuccessfulDeleteCluster", "Successfully deleted AzureStackHCICluster %s/%s", azureStackHCICluster.Namespace, azureStackHCICluster.Name)
@@ -382,9 +422,28 @@ func (r *AzureStackHCIClusterReconciler) reconcileAzureStackHCILoadBalancer(clus
 		if clusterScope.AzureStackHCILoadBalancer().Image != nil {
 			azureStackHCILoadBalancer.Spec.Image = clusterScope.AzureStackHCILoadBalancer().Image.DeepCopy()
 		}
+		azureStackHCILoadBalancer.Spec.Version = clusterScope.AzureStackHCILoadBalancer().Version
 		azureStackHCILoadBalancer.Spec.SSHPublicKey = clusterScope.AzureStackHCILoadBalancer().SSHPublicKey
 		azureStackHCILoadBalancer.Spec.VMSize = clusterScope.AzureStackHCILoadBalancer().VMSize
-		azureStackHCILoadBalancer.Spec.Replicas = clusterScope.AzureStackHCILoadBalancer().Replicas
+		// the autoscaler owns the replicas once the loadbalancer exists
+		if clusterScope.AzureStackHCILoadBalancer().Autoscaling == nil || azureStackHCILoadBalancer.Spec.Replicas == nil {
+			azureStackHCILoadBalancer.Spec.Replicas = clusterScope.AzureStackHCILoadBalancer().Replicas
+		}
+		if clusterScope.AzureStackHCILoadBalancer().VipPool != nil {
+			azureStackHCILoadBalancer.Spec.VipPool = clusterScope.AzureStackHCILoadBalancer().VipPool.DeepCopy()
+		}
+		azureStackHCILoadBalancer.Spec.StaticVIP = clusterScope.AzureStackHCILoadBalancer().StaticVIP
+		azureStackHCILoadBalancer.Spec.Rules = append([]infrav1.LoadBalancingRule(nil), clusterScope.AzureStackHCILoadBalancer().Rules...)
+		azureStackHCILoadBalancer.Spec.Probes = append([]infrav1.LoadBalancerProbe(nil), clusterScope.AzureStackHCILoadBalancer().Probes...)
+		azureStackHCILoadBalancer.Spec.Listeners = append([]infrav1.LoadBalancerListener(nil), clusterScope.AzureStackHCILoadBalancer().Listeners...)
+		azureStackHCILoadBalancer.Spec.Strategy = clusterScope.AzureStackHCILoadBalancer().Strategy.DeepCopy()
+		azureStackHCILoadBalancer.Spec.HealthCheck = clusterScope.AzureStackHCILoadBalancer().HealthCheck.DeepCopy()
+		azureStackHCILoadBalancer.Spec.Placement = clusterScope.AzureStackHCILoadBalancer().Placement.DeepCopy()
+		azureStackHCILoadBalancer.Spec.Autoscaling = clusterScope.AzureStackHCILoadBalancer().Autoscaling.DeepCopy()
+		// request the control plane endpoint as the frontend address so a recreated loadbalancer keeps it
+		if endpointHost := clusterScope.AzureStackHCICluster.Spec.ControlPlaneEndpoint.Host; azureStackHCILoadBalancer.Spec.StaticVIP == "" && net.ParseIP(endpointHost) != nil {
+			azureStackHCILoadBalancer.Spec.StaticVIP = endpointHost
+		}
 		infrav1util.CopyCorrelationID(clusterScope.AzureStackHCICluster, azureStackHCILoadBalancer)
 		return nil
 	}
@@ -425,15 +484,53 @@ func (r *AzureStackHCIClusterReconciler) reconcileAzureStackHCILoadBalancer(clus
 		return false, nil
 	}
 
-	if azureStackHCILoadBalancer.Status.Address != "" {
+	r.reconcileControlPlaneEndpoint(clusterScope, azureStackHCILoadBalancer)
+
+	return true, nil
+}
+
+// reconcileControlPlaneEndpoint sets the ControlPlaneEndpoint from the loadbalancer address once and
+// reports drift between the endpoint and the loadbalancer frontend address afterwards.
+func (r *AzureStackHCIClusterReconciler) reconcileControlPlaneEndpoint(clusterScope *scope.ClusterScope, azureStackHCILoadBalancer *infrav1.AzureStackHCILoadBalancer) {
+	address := azureStackHCILoadBalancer.Status.Address
+	if address == "" {
+		return
+	}
+
+	endpoint := &clusterScope.AzureStackHCICluster.Spec.ControlPlaneEndpoint
+	if endpoint.Host == "" {
 		// Set APIEndpoints so the Cluster API Cluster Controller can pull them
-		clusterScope.AzureStackHCICluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
-			Host: azureStackHCILoadBalancer.Status.Address,
+		*endpoint = clusterv1.APIEndpoint{
+			Host: address,
 			Port: clusterScope.APIServerPort(),
 		}
 	}
 
-	return true, nil
+	message := ""
+	if net.ParseIP(endpoint.Host) != nil && endpoint.Host != address {
+		message = fmt.Sprintf("loadbalancer address %s does not match the control plane endpoint %s", address, endpoint.Host)
+	} else if cond := conditions.Get(azureStackHCILoadBalancer, infrav1.LoadBalancerAddressPinnedCondition); cond != nil && cond.Status == metav1.ConditionFalse {
+		message = cond.Message
+	}
+
+	if message != "" {
+		if !conditions.IsFalse(clusterScope.AzureStackHCICluster, infrav1.ControlPlaneEndpointReadyCondition) {
+			r.Recorder.Eventf(clusterScope.AzureStackHCICluster, corev1.EventTypeWarning, "ControlPlaneEndpointDrifted", message)
+		}
+		conditions.Set(clusterScope.AzureStackHCICluster, metav1.Condition{
+			Type:    infrav1.ControlPlaneEndpointReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.ControlPlaneEndpointDriftedReason,
+			Message: message,
+		})
+		return
+	}
+
+	conditions.Set(clusterScope.AzureStackHCICluster, metav1.Condition{
+		Type:   infrav1.ControlPlaneEndpointReadyCondition,
+		Status: metav1.ConditionTrue,
+		Reason: infrav1.InfrastructureReadyReason,
+	})
 }
 
 func (r *AzureStackHCIClusterReconciler) reconcileDeleteAzureStackHCILoadBalancer(clusterScope *scope.ClusterScope) error {
@@ -516,3 +613,22 @@ func (r *AzureStackHCIClusterReconciler) reconcilePhase(clusterScope *scope.Clus
 		azureStackHCICluster.Status.SetTypedPhase(infrav1.AzureStackHCIClusterPhaseDeleting)
 	}
 }
+
+// clusterFailureDomains converts the failure domains declared on the AzureStackHCICluster spec to the
+// failure domains published to Cluster API. Failure domains are suitable for control plane machines unless
+// declared otherwise.
+func clusterFailureDomains(specs []infrav1.FailureDomainSpec) []clusterv1.FailureDomain {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	failureDomains := make([]clusterv1.FailureDomain, 0, len(specs))
+	for _, spec := range specs {
+		failureDomains = append(failureDomains, clusterv1.FailureDomain{
+			Name:         spec.Name,
+			ControlPlane: ptr.To(ptr.Deref(spec.ControlPlane, true)),
+			Attributes:   spec.Attributes,
+		})
+	}
+	return failureDomains
+}
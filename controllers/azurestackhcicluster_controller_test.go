@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+)
+
+// TestClusterFailureDomains verifies that the failure domains declared on the spec are published to Cluster API,
+// and that they are suitable for control plane machines unless declared otherwise.
+func TestClusterFailureDomains(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []infrav1.FailureDomainSpec
+		want  []clusterv1.FailureDomain
+	}{
+		{
+			name: "no failure domains",
+		},
+		{
+			name: "control plane defaults to true",
+			specs: []infrav1.FailureDomainSpec{
+				{Name: "fd-1", AvailabilitySetName: "as-1"},
+				{Name: "fd-2", PlacementGroupName: "pg-2", Attributes: map[string]string{"rack": "2"}},
+			},
+			want: []clusterv1.FailureDomain{
+				{Name: "fd-1", ControlPlane: ptr.To(true)},
+				{Name: "fd-2", ControlPlane: ptr.To(true), Attributes: map[string]string{"rack": "2"}},
+			},
+		},
+		{
+			name: "workers only",
+			specs: []infrav1.FailureDomainSpec{
+				{Name: "fd-1", ControlPlane: ptr.To(false), AvailabilitySetName: "as-1"},
+			},
+			want: []clusterv1.FailureDomain{
+				{Name: "fd-1", ControlPlane: ptr.To(false)},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(clusterFailureDomains(tc.specs)).To(Equal(tc.want))
+		})
+	}
+}
+
+// TestApplyFailureDomain verifies that the availability set and placement group of the failure domain take
+// precedence over the ones of the AzureStackHCIMachine.
+func TestApplyFailureDomain(t *testing.T) {
+	tests := []struct {
+		name          string
+		failureDomain *infrav1.FailureDomainSpec
+		wantSet       string
+		wantGroup     string
+	}{
+		{
+			name:      "no failure domain",
+			wantSet:   "machine-as",
+			wantGroup: "machine-pg",
+		},
+		{
+			name:          "availability set",
+			failureDomain: &infrav1.FailureDomainSpec{Name: "fd-1", AvailabilitySetName: "as-1"},
+			wantSet:       "as-1",
+			wantGroup:     "machine-pg",
+		},
+		{
+			name:          "availability set and placement group",
+			failureDomain: &infrav1.FailureDomainSpec{Name: "fd-1", AvailabilitySetName: "as-1", PlacementGroupName: "pg-1"},
+			wantSet:       "as-1",
+			wantGroup:     "pg-1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			spec := &infrav1.AzureStackHCIVirtualMachineSpec{
+				AvailabilitySetName: "machine-as",
+				PlacementGroupName:  "machine-pg",
+			}
+			applyFailureDomain(spec, tc.failureDomain)
+			g.Expect(spec.AvailabilitySetName).To(Equal(tc.wantSet))
+			g.Expect(spec.PlacementGroupName).To(Equal(tc.wantGroup))
+		})
+	}
+}
+
+// TestReconcileControlPlaneEndpoint verifies that the control plane endpoint is set from the loadbalancer address once,
+// and that a loadbalancer address that no longer matches it is reported as drifted.
+func TestReconcileControlPlaneEndpoint(t *testing.T) {
+	drifted := metav1.Condition{
+		Type:    infrav1.LoadBalancerAddressPinnedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrav1.LoadBalancerAddressDriftedReason,
+		Message: "frontend address 10.0.0.9 does not match the pinned address 10.0.0.5",
+	}
+	tests := []struct {
+		name         string
+		endpoint     clusterv1.APIEndpoint
+		address      string
+		lbConditions []metav1.Condition
+		wantEndpoint clusterv1.APIEndpoint
+		wantStatus   metav1.ConditionStatus
+		wantEvents   int
+	}{
+		{
+			name: "no loadbalancer address",
+		},
+		{
+			name:         "endpoint set from address",
+			address:      "10.0.0.5",
+			wantEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.5", Port: 6443},
+			wantStatus:   metav1.ConditionTrue,
+		},
+		{
+			name:         "endpoint matches",
+			endpoint:     clusterv1.APIEndpoint{Host: "10.0.0.5", Port: 443},
+			address:      "10.0.0.5",
+			wantEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.5", Port: 443},
+			wantStatus:   metav1.ConditionTrue,
+		},
+		{
+			name:         "endpoint is a host name",
+			endpoint:     clusterv1.APIEndpoint{Host: "api.example.com", Port: 6443},
+			address:      "10.0.0.5",
+			wantEndpoint: clusterv1.APIEndpoint{Host: "api.example.com", Port: 6443},
+			wantStatus:   metav1.ConditionTrue,
+		},
+		{
+			name:         "endpoint drifted",
+			endpoint:     clusterv1.APIEndpoint{Host: "10.0.0.4", Port: 6443},
+			address:      "10.0.0.5",
+			wantEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.4", Port: 6443},
+			wantStatus:   metav1.ConditionFalse,
+			wantEvents:   1,
+		},
+		{
+			name:         "loadbalancer address drifted",
+			endpoint:     clusterv1.APIEndpoint{Host: "10.0.0.5", Port: 6443},
+			address:      "10.0.0.5",
+			lbConditions: []metav1.Condition{drifted},
+			wantEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.5", Port: 6443},
+			wantStatus:   metav1.ConditionFalse,
+			wantEvents:   1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope := &scope.ClusterScope{
+				AzureStackHCICluster: &infrav1.AzureStackHCICluster{
+					Spec: infrav1.AzureStackHCIClusterSpec{ControlPlaneEndpoint: tc.endpoint},
+				},
+			}
+			lb := &infrav1.AzureStackHCILoadBalancer{
+				Status: infrav1.AzureStackHCILoadBalancerStatus{Address: tc.address, Conditions: tc.lbConditions},
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &AzureStackHCIClusterReconciler{Recorder: recorder}
+
+			r.reconcileControlPlaneEndpoint(clusterScope, lb)
+			g.Expect(clusterScope.AzureStackHCICluster.Spec.ControlPlaneEndpoint).To(Equal(tc.wantEndpoint))
+			cond := conditions.Get(clusterScope.AzureStackHCICluster, infrav1.ControlPlaneEndpointReadyCondition)
+			if tc.wantStatus == "" {
+				g.Expect(cond).To(BeNil())
+			} else {
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(tc.wantStatus))
+			}
+			g.Expect(recorder.Events).To(HaveLen(tc.wantEvents))
+		})
+	}
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/availabilitysets"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/placementgroups"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/storagecontainers"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcilePlacements creates the availability sets, placement groups and storage containers declared on the cluster,
+// and deletes the ones removed from the spec once no virtual machine is in them. Returns true while removed ones are
+// still in use.
+func (r *AzureStackHCIClusterReconciler) reconcilePlacements(clusterScope *scope.ClusterScope) (bool, error) {
+	availabilitySetRefs, placementGroupRefs, storageContainerRefs, err := r.placementReferences(clusterScope)
+	if err != nil {
+		return false, err
+	}
+
+	spec := clusterScope.AzureStackHCICluster.Spec
+	status := &clusterScope.AzureStackHCICluster.Status
+
+	avsetSvc := availabilitysets.NewService(clusterScope)
+	availabilitySets := make(map[string]infrav1.AvailabilitySetSpec, len(spec.AvailabilitySets))
+	declared := make([]string, 0, len(spec.AvailabilitySets))
+	for _, avset := range spec.AvailabilitySets {
+		availabilitySets[avset.Name] = avset
+		declared = append(declared, avset.Name)
+	}
+	status.AvailabilitySets, err = reconcilePlacementResources(declared, status.AvailabilitySets, availabilitySetRefs,
+		func(name string) error {
+			return avsetSvc.Reconcile(clusterScope.Context, &availabilitysets.Spec{
+				Name:                     name,
+				PlatformFaultDomainCount: ptr.Deref(availabilitySets[name].PlatformFaultDomainCount, azurestackhci.DefaultPlatformFaultDomainCount),
+			})
+		},
+		func(name string) error {
+			return avsetSvc.Delete(clusterScope.Context, &availabilitysets.Spec{Name: name})
+		})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to reconcile availability sets for cluster %s", clusterScope.Name())
+	}
+
+	pgroupSvc := placementgroups.NewService(clusterScope)
+	placementGroups := make(map[string]infrav1.PlacementGroupSpec, len(spec.PlacementGroups))
+	declared = make([]string, 0, len(spec.PlacementGroups))
+	for _, pgroup := range spec.PlacementGroups {
+		placementGroups[pgroup.Name] = pgroup
+		declared = append(declared, pgroup.Name)
+	}
+	status.PlacementGroups, err = reconcilePlacementResources(declared, status.PlacementGroups, placementGroupRefs,
+		func(name string) error {
+			policy := placementGroups[name].Policy
+			if policy == "" {
+				policy = infrav1.PlacementGroupPolicyAntiAffinity
+			}
+			return pgroupSvc.Reconcile(clusterScope.Context, &placementgroups.Spec{
+				Name: name,
+				Type: compute.PlacementGroupType(policy),
+			})
+		},
+		func(name string) error {
+			return pgroupSvc.Delete(clusterScope.Context, &placementgroups.Spec{Name: name})
+		})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to reconcile placement groups for cluster %s", clusterScope.Name())
+	}
+
+	containerSvc := storagecontainers.NewService(clusterScope)
+	storageContainers := make(map[string]infrav1.StorageContainerSpec, len(spec.StorageContainers))
+	declared = make([]string, 0, len(spec.StorageContainers))
+	for _, container := range spec.StorageContainers {
+		storageContainers[container.Name] = container
+		declared = append(declared, container.Name)
+	}
+	status.StorageContainers, err = reconcilePlacementResources(declared, status.StorageContainers, storageContainerRefs,
+		func(name string) error {
+			return containerSvc.Reconcile(clusterScope.Context, &storagecontainers.Spec{
+				Name:     name,
+				Location: clusterScope.Location(),
+				Path:     storageContainers[name].Path,
+			})
+		},
+		func(name string) error {
+			return containerSvc.Delete(clusterScope.Context, &storagecontainers.Spec{Name: name, Location: clusterScope.Location()})
+		})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to reconcile storage containers for cluster %s", clusterScope.Name())
+	}
+
+	pending := len(status.AvailabilitySets) > len(spec.AvailabilitySets) || len(status.PlacementGroups) > len(spec.PlacementGroups) ||
+		len(status.StorageContainers) > len(spec.StorageContainers)
+	return pending, nil
+}
+
+// reconcileDeletePlacements deletes the availability sets, placement groups and storage containers created with the
+// cluster. Returns false while virtual machines are still in them.
+func (r *AzureStackHCIClusterReconciler) reconcileDeletePlacements(clusterScope *scope.ClusterScope) (bool, error) {
+	availabilitySetRefs, placementGroupRefs, storageContainerRefs, err := r.placementReferences(clusterScope)
+	if err != nil {
+		return false, err
+	}
+
+	spec := clusterScope.AzureStackHCICluster.Spec
+	status := &clusterScope.AzureStackHCICluster.Status
+
+	// the declared resources are deleted too, in case the cluster is deleted before their status was recorded
+	avsetSvc := availabilitysets.NewService(clusterScope)
+	created := status.AvailabilitySets
+	for _, avset := range spec.AvailabilitySets {
+		created = appendPlacementStatus(created, avset.Name)
+	}
+	status.AvailabilitySets, err = reconcilePlacementResources(nil, created, availabilitySetRefs, nil,
+		func(name string) error {
+			return avsetSvc.Delete(clusterScope.Context, &availabilitysets.Spec{Name: name})
+		})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to delete availability sets for cluster %s", clusterScope.Name())
+	}
+
+	pgroupSvc := placementgroups.NewService(clusterScope)
+	created = status.PlacementGroups
+	for _, pgroup := range spec.PlacementGroups {
+		created = appendPlacementStatus(created, pgroup.Name)
+	}
+	status.PlacementGroups, err = reconcilePlacementResources(nil, created, placementGroupRefs, nil,
+		func(name string) error {
+			return pgroupSvc.Delete(clusterScope.Context, &placementgroups.Spec{Name: name})
+		})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to delete placement groups for cluster %s", clusterScope.Name())
+	}
+
+	// storage containers that weren't created by caph are left in place by the service
+	containerSvc := storagecontainers.NewService(clusterScope)
+	created = status.StorageContainers
+	for _, container := range spec.StorageContainers {
+		created = appendPlacementStatus(created, container.Name)
+	}
+	status.StorageContainers, err = reconcilePlacementResources(nil, created, storageContainerRefs, nil,
+		func(name string) error {
+			return containerSvc.Delete(clusterScope.Context, &storagecontainers.Spec{Name: name, Location: clusterScope.Location()})
+		})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to delete storage containers for cluster %s", clusterScope.Name())
+	}
+
+	if len(status.AvailabilitySets) > 0 || len(status.PlacementGroups) > 0 || len(status.StorageContainers) > 0 {
+		clusterScope.Info("Waiting for virtual machines to leave the availability sets, placement groups and storage containers",
+			"availabilitySets", len(status.AvailabilitySets), "placementGroups", len(status.PlacementGroups),
+			"storageContainers", len(status.StorageContainers))
+		return false, nil
+	}
+	return true, nil
+}
+
+// placementReferences counts the virtual machines of the cluster in each availability set, placement group and
+// storage container.
+func (r *AzureStackHCIClusterReconciler) placementReferences(clusterScope *scope.ClusterScope) (availabilitySets, placementGroups, storageContainers map[string]int32, err error) {
+	vmList := &infrav1.AzureStackHCIVirtualMachineList{}
+	if err := r.Client.List(clusterScope.Context, vmList, client.InNamespace(clusterScope.Namespace())); err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "failed to list virtual machines")
+	}
+
+	availabilitySets, placementGroups = countPlacementReferences(vmList.Items, clusterScope.Name())
+	storageContainers = countStorageContainerReferences(vmList.Items, clusterScope.Name())
+	return availabilitySets, placementGroups, storageContainers, nil
+}
+
+// countPlacementReferences counts the virtual machines of the cluster in each availability set and placement group.
+func countPlacementReferences(vms []infrav1.AzureStackHCIVirtualMachine, clusterName string) (availabilitySets, placementGroups map[string]int32) {
+	availabilitySets = map[string]int32{}
+	placementGroups = map[string]int32{}
+	for _, vm := range vms {
+		if vm.Spec.ClusterName != clusterName {
+			continue
+		}
+		if vm.Spec.AvailabilitySetName != "" {
+			availabilitySets[vm.Spec.AvailabilitySetName]++
+		}
+		if vm.Spec.PlacementGroupName != "" {
+			placementGroups[vm.Spec.PlacementGroupName]++
+		}
+	}
+	return availabilitySets, placementGroups
+}
+
+// countStorageContainerReferences counts the virtual machines of the cluster in each storage container.
+func countStorageContainerReferences(vms []infrav1.AzureStackHCIVirtualMachine, clusterName string) map[string]int32 {
+	storageContainers := map[string]int32{}
+	for _, vm := range vms {
+		if vm.Spec.ClusterName == clusterName && vm.Spec.StorageContainer != "" {
+			storageContainers[vm.Spec.StorageContainer]++
+		}
+	}
+	return storageContainers
+}
+
+// reconcilePlacementResources creates the declared availability sets, placement groups or storage containers, and
+// deletes the previously created ones that are no longer declared once no virtual machine is in them. Returns the
+// status of the ones that exist.
+func reconcilePlacementResources(declared []string, created []infrav1.PlacementStatus, refs map[string]int32,
+	create func(name string) error, remove func(name string) error) ([]infrav1.PlacementStatus, error) {
+	var statuses []infrav1.PlacementStatus
+	isDeclared := make(map[string]bool, len(declared))
+	for _, name := range declared {
+		isDeclared[name] = true
+		if err := create(name); err != nil {
+			return created, errors.Wrapf(err, "failed to reconcile %s", name)
+		}
+		statuses = append(statuses, infrav1.PlacementStatus{Name: name, VirtualMachines: refs[name]})
+	}
+
+	for _, previous := range created {
+		if isDeclared[previous.Name] {
+			continue
+		}
+		// still in use, keep it until the virtual machines in it are deleted
+		if refs[previous.Name] > 0 {
+			statuses = append(statuses, infrav1.PlacementStatus{Name: previous.Name, VirtualMachines: refs[previous.Name]})
+			continue
+		}
+		if err := remove(previous.Name); err != nil {
+			return created, errors.Wrapf(err, "failed to delete %s", previous.Name)
+		}
+	}
+	return statuses, nil
+}
+
+// appendPlacementStatus appends an availability set, placement group or storage container to the list unless it is already in it.
+func appendPlacementStatus(statuses []infrav1.PlacementStatus, name string) []infrav1.PlacementStatus {
+	for _, status := range statuses {
+		if status.Name == name {
+			return statuses
+		}
+	}
+	return append(statuses, infrav1.PlacementStatus{Name: name})
+}
+
+// storageContainerFor returns the storage container of a virtual machine of the cluster. The requested storage
+// container is used when set, otherwise one of the storage containers of the cluster is selected with its
+// StorageContainerPolicy when the virtual machine is created, and kept afterwards.
+func storageContainerFor(ctx context.Context, c client.Reader, clusterScope *scope.ClusterScope, vm *infrav1.AzureStackHCIVirtualMachine, requested string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+	if vm.Spec.StorageContainer != "" || !vm.CreationTimestamp.IsZero() {
+		return vm.Spec.StorageContainer, nil
+	}
+
+	spec := clusterScope.AzureStackHCICluster.Spec
+	if len(spec.StorageContainers) == 0 || spec.StorageContainerPolicy == "" || spec.StorageContainerPolicy == infrav1.StorageContainerPolicyExplicit {
+		return "", nil
+	}
+
+	vmList := &infrav1.AzureStackHCIVirtualMachineList{}
+	if err := c.List(ctx, vmList, client.InNamespace(clusterScope.Namespace())); err != nil {
+		return "", errors.Wrapf(err, "failed to list virtual machines")
+	}
+	refs := countStorageContainerReferences(vmList.Items, clusterScope.Name())
+	return selectStorageContainer(spec.StorageContainerPolicy, spec.StorageContainers, refs), nil
+}
+
+// selectStorageContainer selects one of the declared storage containers with the policy, given the number of
+// virtual machines in each of them.
+func selectStorageContainer(policy infrav1.StorageContainerPolicy, containers []infrav1.StorageContainerSpec, refs map[string]int32) string {
+	if len(containers) == 0 {
+		return ""
+	}
+
+	switch policy {
+	case infrav1.StorageContainerPolicyLeastUsed:
+		selected := containers[0].Name
+		for _, container := range containers[1:] {
+			if refs[container.Name] < refs[selected] {
+				selected = container.Name
+			}
+		}
+		return selected
+	case infrav1.StorageContainerPolicyRoundRobin:
+		// the virtual machines already placed determine whose turn it is
+		var placed int32
+		for _, container := range containers {
+			placed += refs[container.Name]
+		}
+		return containers[int(placed)%len(containers)].Name
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+// TestCountPlacementReferences verifies that only the virtual machines of the cluster are counted.
+func TestCountPlacementReferences(t *testing.T) {
+	g := NewWithT(t)
+
+	vm := func(clusterName, availabilitySetName, placementGroupName string) infrav1.AzureStackHCIVirtualMachine {
+		return infrav1.AzureStackHCIVirtualMachine{Spec: infrav1.AzureStackHCIVirtualMachineSpec{
+			ClusterName:         clusterName,
+			AvailabilitySetName: availabilitySetName,
+			PlacementGroupName:  placementGroupName,
+		}}
+	}
+	vms := []infrav1.AzureStackHCIVirtualMachine{
+		vm("test-cluster", "as-1", ""),
+		vm("test-cluster", "as-1", "pg-1"),
+		vm("test-cluster", "", ""),
+		vm("other-cluster", "as-1", "pg-1"),
+	}
+
+	availabilitySets, placementGroups := countPlacementReferences(vms, "test-cluster")
+	g.Expect(availabilitySets).To(Equal(map[string]int32{"as-1": 2}))
+	g.Expect(placementGroups).To(Equal(map[string]int32{"pg-1": 1}))
+}
+
+// TestReconcilePlacementResources verifies that declared resources are created, and that resources removed from
+// the spec are deleted only once no virtual machine is in them.
+func TestReconcilePlacementResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		declared    []string
+		created     []infrav1.PlacementStatus
+		refs        map[string]int32
+		createErr   error
+		wantCreated []string
+		wantDeleted []string
+		want        []infrav1.PlacementStatus
+		wantErr     bool
+	}{
+		{
+			name:        "declared resources are created",
+			declared:    []string{"as-1", "as-2"},
+			refs:        map[string]int32{"as-1": 3},
+			wantCreated: []string{"as-1", "as-2"},
+			want: []infrav1.PlacementStatus{
+				{Name: "as-1", VirtualMachines: 3},
+				{Name: "as-2"},
+			},
+		},
+		{
+			name:        "unused removed resource is deleted",
+			declared:    []string{"as-1"},
+			created:     []infrav1.PlacementStatus{{Name: "as-1"}, {Name: "as-2"}},
+			wantCreated: []string{"as-1"},
+			wantDeleted: []string{"as-2"},
+			want:        []infrav1.PlacementStatus{{Name: "as-1"}},
+		},
+		{
+			name:    "used removed resource is kept",
+			created: []infrav1.PlacementStatus{{Name: "as-1"}, {Name: "as-2"}},
+			refs:    map[string]int32{"as-2": 1},
+			want: []infrav1.PlacementStatus{
+				{Name: "as-2", VirtualMachines: 1},
+			},
+			wantDeleted: []string{"as-1"},
+		},
+		{
+			name:        "creation failure keeps the previous status",
+			declared:    []string{"as-1"},
+			created:     []infrav1.PlacementStatus{{Name: "as-2"}},
+			createErr:   errors.New("moc unavailable"),
+			wantCreated: []string{"as-1"},
+			want:        []infrav1.PlacementStatus{{Name: "as-2"}},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var created, deleted []string
+			got, err := reconcilePlacementResources(tc.declared, tc.created, tc.refs,
+				func(name string) error {
+					created = append(created, name)
+					return tc.createErr
+				},
+				func(name string) error {
+					deleted = append(deleted, name)
+					return nil
+				})
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(got).To(Equal(tc.want))
+			g.Expect(created).To(Equal(tc.wantCreated))
+			g.Expect(deleted).To(Equal(tc.wantDeleted))
+		})
+	}
+}
+
+// TestCountStorageContainerReferences verifies that only the virtual machines of the cluster are counted.
+func TestCountStorageContainerReferences(t *testing.T) {
+	g := NewWithT(t)
+
+	vm := func(clusterName, storageContainer string) infrav1.AzureStackHCIVirtualMachine {
+		return infrav1.AzureStackHCIVirtualMachine{Spec: infrav1.AzureStackHCIVirtualMachineSpec{
+			ClusterName:      clusterName,
+			StorageContainer: storageContainer,
+		}}
+	}
+	vms := []infrav1.AzureStackHCIVirtualMachine{
+		vm("test-cluster", "csv-1"),
+		vm("test-cluster", "csv-1"),
+		vm("test-cluster", "csv-2"),
+		vm("test-cluster", ""),
+		vm("other-cluster", "csv-2"),
+	}
+
+	g.Expect(countStorageContainerReferences(vms, "test-cluster")).To(Equal(map[string]int32{"csv-1": 2, "csv-2": 1}))
+}
+
+// TestSelectStorageContainer verifies that virtual machines are spread across the storage containers of the cluster.
+func TestSelectStorageContainer(t *testing.T) {
+	containers := []infrav1.StorageContainerSpec{{Name: "csv-1"}, {Name: "csv-2"}, {Name: "csv-3"}}
+	tests := []struct {
+		name       string
+		policy     infrav1.StorageContainerPolicy
+		containers []infrav1.StorageContainerSpec
+		refs       map[string]int32
+		want       string
+	}{
+		{
+			name:       "explicit",
+			policy:     infrav1.StorageContainerPolicyExplicit,
+			containers: containers,
+			refs:       map[string]int32{},
+		},
+		{
+			name:       "least used picks the emptiest",
+			policy:     infrav1.StorageContainerPolicyLeastUsed,
+			containers: containers,
+			refs:       map[string]int32{"csv-1": 3, "csv-2": 1, "csv-3": 2},
+			want:       "csv-2",
+		},
+		{
+			name:       "least used breaks ties in declaration order",
+			policy:     infrav1.StorageContainerPolicyLeastUsed,
+			containers: containers,
+			refs:       map[string]int32{"csv-1": 1, "csv-2": 0, "csv-3": 0},
+			want:       "csv-2",
+		},
+		{
+			name:       "round robin starts with the first",
+			policy:     infrav1.StorageContainerPolicyRoundRobin,
+			containers: containers,
+			refs:       map[string]int32{},
+			want:       "csv-1",
+		},
+		{
+			name:       "round robin continues with the next",
+			policy:     infrav1.StorageContainerPolicyRoundRobin,
+			containers: containers,
+			refs:       map[string]int32{"csv-1": 2, "csv-2": 2, "csv-3": 1, "other": 5},
+			want:       "csv-3",
+		},
+		{
+			name:   "no storage container",
+			policy: infrav1.StorageContainerPolicyLeastUsed,
+			refs:   map[string]int32{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(selectStorageContainer(tc.policy, tc.containers, tc.refs)).To(Equal(tc.want))
+		})
+	}
+}
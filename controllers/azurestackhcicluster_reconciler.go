@@ -18,10 +18,12 @@ limitations under the License.
 package controllers
 
 import (
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
 	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/groups"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/keyvaults"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/vippools"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/virtualnetworks"
 	"github.com/pkg/errors"
 )
@@ -37,6 +39,7 @@ type azureStackHCIClusterReconciler struct {
 	vnetSvc     azurestackhci.Service
 	keyvaultSvc azurestackhci.Service
 	groupSvc    azurestackhci.Service
+	vipPoolSvc  azurestackhci.Service
 }
 
 // newAzureStackHCIClusterReconciler populates all the services based on input scope
@@ -46,6 +49,7 @@ func newAzureStackHCIClusterReconciler(scope *scope.ClusterScope) *azureStackHCI
 		vnetSvc:     virtualnetworks.NewService(scope),
 		keyvaultSvc: keyvaults.NewService(scope),
 		groupSvc:    groups.NewService(scope),
+		vipPoolSvc:  vippools.NewService(scope),
 	}
 }
 
@@ -56,8 +60,8 @@ func (r *azureStackHCIClusterReconciler) Reconcile() error {
 	r.createOrUpdateVnetName()
 
 	vnetSpec := &virtualnetworks.Spec{
-		Name: r.scope.Vnet().Name,
-		CIDR: azurestackhci.DefaultVnetCIDR,
+		Name:  r.scope.Vnet().Name,
+		CIDRs: r.scope.VnetCidrBlocks(),
 	}
 	if r.scope.Vnet().Group != "" {
 		vnetSpec.Group = r.scope.Vnet().Group
@@ -85,12 +89,36 @@ func (r *azureStackHCIClusterReconciler) Reconcile() error {
 		return errors.Wrapf(err, "failed to reconcile keyvault for cluster %s", r.scope.Name())
 	}
 
+	// a vip pool is only created when it is owned by the cluster, a referenced
+	// pool is validated by the loadbalancer controller before it is used
+	if vipPool := r.vipPool(); vipPool.IsOwned() {
+		vipPoolSpec := &vippools.Spec{
+			Name:     vipPool.Name,
+			Location: r.scope.Location(),
+			StartIP:  vipPool.StartIP,
+			EndIP:    vipPool.EndIP,
+		}
+		if err := r.vipPoolSvc.Reconcile(r.scope.Context, vipPoolSpec); err != nil {
+			return errors.Wrapf(err, "failed to reconcile vippool %s for cluster %s", vipPool.Name, r.scope.Name())
+		}
+	}
+
 	return nil
 }
 
 // Delete reconciles all the services in pre determined order
 func (r *azureStackHCIClusterReconciler) Delete() error {
 	r.scope.Info("deleting cluster", "name", r.scope.Name())
+	if vipPool := r.vipPool(); vipPool.IsOwned() {
+		vipPoolSpec := &vippools.Spec{
+			Name:     vipPool.Name,
+			Location: r.scope.Location(),
+		}
+		if err := r.vipPoolSvc.Delete(r.scope.Context, vipPoolSpec); err != nil {
+			return errors.Wrapf(err, "failed to delete vippool %s for cluster %s", vipPool.Name, r.scope.Name())
+		}
+	}
+
 	vaultSpec := &keyvaults.Spec{
 		Name: r.scope.Name(),
 	}
@@ -101,8 +129,8 @@ func (r *azureStackHCIClusterReconciler) Delete() error {
 	}
 
 	vnetSpec := &virtualnetworks.Spec{
-		Name: r.scope.Vnet().Name,
-		CIDR: azurestackhci.DefaultVnetCIDR,
+		Name:  r.scope.Vnet().Name,
+		CIDRs: r.scope.VnetCidrBlocks(),
 	}
 	if r.scope.Vnet().Group != "" {
 		vnetSpec.Group = r.scope.Vnet().Group
@@ -136,3 +164,11 @@ func (r *azureStackHCIClusterReconciler) createOrUpdateVnetName() {
 		r.scope.Vnet().Name = azurestackhci.GenerateVnetName(r.scope.Name())
 	}
 }
+
+// vipPool returns the vip pool referenced by the cluster load balancer, if any
+func (r *azureStackHCIClusterReconciler) vipPool() *infrav1.VipPoolSpec {
+	if r.scope.AzureStackHCILoadBalancer() == nil {
+		return nil
+	}
+	return r.scope.AzureStackHCILoadBalancer().VipPool
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/converters"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/galleryimages"
+	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// imageImportPollInterval is how often the import state of a gallery image is checked while it is imported.
+	imageImportPollInterval = 30 * time.Second
+)
+
+// AzureStackHCIImageReconciler reconciles a AzureStackHCIImage object
+type AzureStackHCIImageReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+func (r *AzureStackHCIImageReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureStackHCIImage{}).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhciimages,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhciimages/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+
+// Reconcile imports the VHDX of an AzureStackHCIImage into the image gallery and reports the import state.
+func (r *AzureStackHCIImageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	logger := r.Log.WithValues("azureStackHCIImage", req.NamespacedName, "reconcileID", infrav1util.GetReconcileID(ctx))
+	logger.Info("Attempt to reconcile resource")
+
+	azureStackHCIImage := &infrav1.AzureStackHCIImage{}
+	err := r.Get(ctx, req.NamespacedName, azureStackHCIImage)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	imageScope, err := scope.NewImageScope(scope.ImageScopeParams{
+		Logger:             &logger,
+		Client:             r.Client,
+		AzureStackHCIImage: azureStackHCIImage,
+	})
+	if err != nil {
+		r.Recorder.Eventf(azureStackHCIImage, corev1.EventTypeWarning, "FailureCreateImageScope", errors.Wrapf(err, "failed to create image scope").Error())
+		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	// Always close the scope when exiting this function so we can persist any AzureStackHCIImage changes.
+	defer func() {
+		if err := imageScope.Close(); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	imageSvc := galleryimages.NewService(imageScope)
+	if !azureStackHCIImage.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(imageScope, imageSvc)
+	}
+
+	return r.reconcileNormal(imageScope, imageSvc)
+}
+
+func (r *AzureStackHCIImageReconciler) reconcileNormal(imageScope *scope.ImageScope, imageSvc azurestackhci.GetterService) (reconcile.Result, error) {
+	imageScope.Info("Reconciling AzureStackHCIImage")
+	azureStackHCIImage := imageScope.AzureStackHCIImage
+
+	// If the AzureStackHCIImage doesn't have our finalizer, add it.
+	controllerutil.AddFinalizer(azureStackHCIImage, infrav1.ImageFinalizer)
+	// Register the finalizer immediately to avoid orphaning the gallery image on delete
+	if err := imageScope.PatchObject(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	imageSpec := galleryImageSpec(imageScope)
+
+	existing, err := imageSvc.Get(imageScope.Context, imageSpec)
+	if err != nil && !azurestackhci.ResourceNotFound(err) {
+		return r.reconcileGetFailed(imageScope, imageSpec, err)
+	}
+	if err != nil {
+		// report the import before it starts, the cloud agent only returns once the VHDX was imported
+		imageScope.SetState(infrav1.ImageStateImporting, 0)
+		conditions.Set(azureStackHCIImage, metav1.Condition{
+			Type:    infrav1.ImageReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.ImageImportingReason,
+			Message: fmt.Sprintf("importing gallery image %s", imageSpec.Name),
+		})
+		if err := imageScope.PatchObject(); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if err := imageSvc.Reconcile(imageScope.Context, imageSpec); err != nil {
+			return reconcileResultForError(r.setImportFailed(imageScope, err), err)
+		}
+		r.Recorder.Eventf(azureStackHCIImage, corev1.EventTypeNormal, "GalleryImageImported", "Imported gallery image %s", imageSpec.Name)
+
+		existing, err = imageSvc.Get(imageScope.Context, imageSpec)
+		if err != nil {
+			return r.reconcileGetFailed(imageScope, imageSpec, err)
+		}
+	}
+
+	image, ok := existing.(compute.GalleryImage)
+	if !ok {
+		return reconcile.Result{}, errors.New("returned incorrect gallery image interface")
+	}
+
+	state, progress := converters.SDKToImageState(image)
+	imageScope.SetState(state, progress)
+	switch state {
+	case infrav1.ImageStateSucceeded:
+		conditions.Set(azureStackHCIImage, metav1.Condition{
+			Type:   infrav1.ImageReadyCondition,
+			Status: metav1.ConditionTrue,
+			Reason: infrav1.ImageImportedReason,
+		})
+		return reconcile.Result{}, nil
+	case infrav1.ImageStateFailed:
+		// delete the failed import so that it is retried from scratch
+		err := errors.Errorf("gallery image %s failed to import", imageSpec.Name)
+		r.setImportFailed(imageScope, err)
+		if deleteErr := imageSvc.Delete(imageScope.Context, imageSpec); deleteErr != nil {
+			return reconcileResultForError(azurestackhci.ClassifyError(deleteErr, infrav1.DeletionFailedReason),
+				errors.Wrapf(deleteErr, "failed to delete gallery image %s", imageSpec.Name))
+		}
+		return reconcile.Result{}, err
+	default:
+		conditions.Set(azureStackHCIImage, metav1.Condition{
+			Type:    infrav1.ImageReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.ImageImportingReason,
+			Message: fmt.Sprintf("gallery image %s is %s, %d%% downloaded", imageSpec.Name, state, progress),
+		})
+		return reconcile.Result{RequeueAfter: imageImportPollInterval}, nil
+	}
+}
+
+func (r *AzureStackHCIImageReconciler) reconcileDelete(imageScope *scope.ImageScope, imageSvc azurestackhci.GetterService) (reconcile.Result, error) {
+	imageScope.Info("Handling deleted AzureStackHCIImage")
+	azureStackHCIImage := imageScope.AzureStackHCIImage
+
+	imageScope.SetState(infrav1.ImageStateDeleting, azureStackHCIImage.Status.DownloadProgress)
+	// only gallery images imported by the provider are deleted, machines created from the image keep their os disk
+	if err := imageSvc.Delete(imageScope.Context, galleryImageSpec(imageScope)); err != nil {
+		class := azurestackhci.ClassifyError(err, infrav1.DeletionFailedReason)
+		r.Recorder.Eventf(azureStackHCIImage, corev1.EventTypeWarning, "FailureDeleteGalleryImage", err.Error())
+		conditions.Set(azureStackHCIImage, metav1.Condition{
+			Type:    infrav1.ImageReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
+		return reconcileResultForError(class, err)
+	}
+
+	controllerutil.RemoveFinalizer(azureStackHCIImage, infrav1.ImageFinalizer)
+	return reconcile.Result{}, nil
+}
+
+// setImportFailed records a failed import on the AzureStackHCIImage and returns the class of the error.
+func (r *AzureStackHCIImageReconciler) setImportFailed(imageScope *scope.ImageScope, err error) azurestackhci.ErrorClass {
+	class := azurestackhci.ClassifyError(err, infrav1.ImageImportFailedReason)
+	imageScope.SetState(infrav1.ImageStateFailed, 0)
+	r.Recorder.Eventf(imageScope.AzureStackHCIImage, corev1.EventTypeWarning, infrav1.ImageImportFailedReason, err.Error())
+	conditions.Set(imageScope.AzureStackHCIImage, metav1.Condition{
+		Type:    infrav1.ImageReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  class.Reason,
+		Message: err.Error(),
+	})
+	return class
+}
+
+// reconcileGetFailed reports that the state of the gallery image can't be read, the import state is left unchanged.
+func (r *AzureStackHCIImageReconciler) reconcileGetFailed(imageScope *scope.ImageScope, imageSpec *galleryimages.Spec, err error) (reconcile.Result, error) {
+	class := azurestackhci.ClassifyError(err, infrav1.ImageImportFailedReason)
+	wrappedErr := errors.Wrapf(err, "failed to get gallery image %s", imageSpec.Name)
+	conditions.Set(imageScope.AzureStackHCIImage, metav1.Condition{
+		Type:    infrav1.ImageReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  class.Reason,
+		Message: wrappedErr.Error(),
+	})
+	return reconcileResultForError(class, wrappedErr)
+}
+
+// galleryImageSpec returns the gallery image specification of the AzureStackHCIImage.
+func galleryImageSpec(imageScope *scope.ImageScope) *galleryimages.Spec {
+	spec := imageScope.AzureStackHCIImage.Spec
+	osType := compute.Linux
+	if spec.OSType == infrav1.OSTypeWindows || spec.OSType == infrav1.OSTypeWindows2022 {
+		osType = compute.Windows
+	}
+	return &galleryimages.Spec{
+		Name:             imageScope.GalleryImageName(),
+		Location:         imageScope.Location(),
+		URL:              spec.Source.URL,
+		Path:             spec.Source.Path,
+		StorageContainer: spec.StorageContainer,
+		OSType:           osType,
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/microsoft/moc-sdk-for-go/services/compute"
+	mocerrors "github.com/microsoft/moc/pkg/errors"
+	mocstatus "github.com/microsoft/moc/pkg/status"
+	wssdcommon "github.com/microsoft/moc/rpc/common"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+)
+
+// fakeAuthorizer stands in for the MOC session, the gallery is faked so it is never used.
+type fakeAuthorizer struct{}
+
+func (fakeAuthorizer) WithTransportAuthorization() credentials.TransportCredentials { return nil }
+func (fakeAuthorizer) WithRPCAuthorization() credentials.PerRPCCredentials          { return nil }
+
+// fakeImageGallery holds at most one gallery image. Reconcile imports it in importState, Delete removes it.
+type fakeImageGallery struct {
+	image       *compute.GalleryImage
+	getErr      error
+	importErr   error
+	importState wssdcommon.ProvisionState
+	deleteErr   error
+	imported    bool
+	deleted     bool
+}
+
+func (f *fakeImageGallery) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	if f.image == nil {
+		return nil, status.Error(codes.NotFound, "gallery image not found")
+	}
+	return *f.image, nil
+}
+
+func (f *fakeImageGallery) Reconcile(ctx context.Context, spec interface{}) error {
+	f.imported = true
+	if f.importErr != nil {
+		return f.importErr
+	}
+	f.image = newTestGalleryImage(f.importState, 100)
+	return nil
+}
+
+func (f *fakeImageGallery) Delete(ctx context.Context, spec interface{}) error {
+	f.deleted = true
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.image = nil
+	return nil
+}
+
+func newTestGalleryImage(state wssdcommon.ProvisionState, progress int64) *compute.GalleryImage {
+	imageStatus := mocstatus.InitStatus()
+	imageStatus.ProvisioningStatus = &wssdcommon.ProvisionStatus{CurrentState: state}
+	imageStatus.DownloadStatus = &wssdcommon.DownloadStatus{ProgressPercentage: progress}
+	return &compute.GalleryImage{
+		GalleryImageProperties: &compute.GalleryImageProperties{Statuses: mocstatus.GetStatuses(imageStatus)},
+	}
+}
+
+// newTestImageScope returns the scope of an AzureStackHCIImage stored in a fake client.
+func newTestImageScope(g *WithT, image *infrav1.AzureStackHCIImage) (*scope.ImageScope, client.Client) {
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(image).WithStatusSubresource(image).Build()
+
+	logger := logr.Discard()
+	imageScope, err := scope.NewImageScope(scope.ImageScopeParams{
+		AzureStackHCIClients: scope.AzureStackHCIClients{CloudAgentFqdn: "cloudagent", Authorizer: fakeAuthorizer{}},
+		Client:               c,
+		Logger:               &logger,
+		AzureStackHCIImage:   image,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	return imageScope, c
+}
+
+func newTestImage() *infrav1.AzureStackHCIImage {
+	return &infrav1.AzureStackHCIImage{
+		ObjectMeta: metav1.ObjectMeta{Name: "ubuntu", Namespace: "default"},
+		Spec: infrav1.AzureStackHCIImageSpec{
+			Location: "westus",
+			Source:   infrav1.ImageSource{URL: "https://images.example.com/ubuntu.vhdx"},
+			OSType:   infrav1.OSTypeLinux,
+		},
+	}
+}
+
+// TestReconcileImageNormal verifies that a missing gallery image is imported, that an existing one is adopted, and
+// that the import state and the class of MOC errors are reported on the status and the ImageReady condition.
+func TestReconcileImageNormal(t *testing.T) {
+	tests := []struct {
+		name          string
+		gallery       *fakeImageGallery
+		wantErr       bool
+		wantTerminal  bool
+		wantImported  bool
+		wantDeleted   bool
+		wantResult    reconcile.Result
+		wantState     infrav1.ImageState
+		wantProgress  int64
+		wantCondition metav1.ConditionStatus
+		wantReason    string
+		wantEvents    int
+	}{
+		{
+			name:          "missing gallery image is imported",
+			gallery:       &fakeImageGallery{importState: wssdcommon.ProvisionState_IMPORTED},
+			wantImported:  true,
+			wantState:     infrav1.ImageStateSucceeded,
+			wantProgress:  100,
+			wantCondition: metav1.ConditionTrue,
+			wantReason:    infrav1.ImageImportedReason,
+			wantEvents:    1,
+		},
+		{
+			name:          "existing gallery image is adopted",
+			gallery:       &fakeImageGallery{image: newTestGalleryImage(wssdcommon.ProvisionState_CREATED, 100)},
+			wantState:     infrav1.ImageStateSucceeded,
+			wantProgress:  100,
+			wantCondition: metav1.ConditionTrue,
+			wantReason:    infrav1.ImageImportedReason,
+		},
+		{
+			name:          "import in progress",
+			gallery:       &fakeImageGallery{image: newTestGalleryImage(wssdcommon.ProvisionState_IMPORTING, 42)},
+			wantResult:    reconcile.Result{RequeueAfter: imageImportPollInterval},
+			wantState:     infrav1.ImageStateImporting,
+			wantProgress:  42,
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.ImageImportingReason,
+		},
+		{
+			name:          "import fails",
+			gallery:       &fakeImageGallery{importErr: status.Error(codes.Internal, "download failed")},
+			wantErr:       true,
+			wantImported:  true,
+			wantState:     infrav1.ImageStateFailed,
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.ImageImportFailedReason,
+			wantEvents:    1,
+		},
+		{
+			name:          "invalid image source",
+			gallery:       &fakeImageGallery{importErr: errors.Wrap(mocerrors.InvalidInput, "invalid source path")},
+			wantErr:       true,
+			wantTerminal:  true,
+			wantImported:  true,
+			wantState:     infrav1.ImageStateFailed,
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.InvalidConfigurationReason,
+			wantEvents:    1,
+		},
+		{
+			name:          "failed import is deleted to be retried",
+			gallery:       &fakeImageGallery{image: newTestGalleryImage(wssdcommon.ProvisionState_IMPORT_FAILED, 10)},
+			wantErr:       true,
+			wantDeleted:   true,
+			wantState:     infrav1.ImageStateFailed,
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.ImageImportFailedReason,
+			wantEvents:    1,
+		},
+		{
+			name:          "gallery unreachable",
+			gallery:       &fakeImageGallery{getErr: status.Error(codes.Unavailable, "connection refused")},
+			wantResult:    reconcile.Result{RequeueAfter: azurestackhci.DefaultMOCUnreachableRetryInterval},
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.MOCUnreachableReason,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			imageScope, c := newTestImageScope(g, newTestImage())
+			recorder := record.NewFakeRecorder(10)
+			r := &AzureStackHCIImageReconciler{Client: c, Recorder: recorder}
+
+			result, err := r.reconcileNormal(imageScope, tc.gallery)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(Equal(tc.wantTerminal))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(result).To(Equal(tc.wantResult))
+			g.Expect(tc.gallery.imported).To(Equal(tc.wantImported))
+			g.Expect(tc.gallery.deleted).To(Equal(tc.wantDeleted))
+			g.Expect(recorder.Events).To(HaveLen(tc.wantEvents))
+
+			// the finalizer is persisted before the gallery is called
+			stored := &infrav1.AzureStackHCIImage{}
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(imageScope.AzureStackHCIImage), stored)).To(Succeed())
+			g.Expect(controllerutil.ContainsFinalizer(stored, infrav1.ImageFinalizer)).To(BeTrue())
+
+			image := imageScope.AzureStackHCIImage
+			g.Expect(image.Status.State).To(Equal(tc.wantState))
+			g.Expect(image.Status.DownloadProgress).To(Equal(tc.wantProgress))
+			g.Expect(image.Status.Ready).To(Equal(tc.wantState == infrav1.ImageStateSucceeded))
+			if tc.wantCondition == "" {
+				g.Expect(conditions.Get(image, infrav1.ImageReadyCondition)).To(BeNil())
+				return
+			}
+			condition := conditions.Get(image, infrav1.ImageReadyCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.wantCondition))
+			g.Expect(condition.Reason).To(Equal(tc.wantReason))
+		})
+	}
+}
+
+// TestReconcileImageDelete verifies that the finalizer is only removed once the gallery image was deleted.
+func TestReconcileImageDelete(t *testing.T) {
+	tests := []struct {
+		name          string
+		deleteErr     error
+		wantErr       bool
+		wantResult    reconcile.Result
+		wantReason    string
+		wantFinalizer bool
+	}{
+		{
+			name: "gallery image deleted",
+		},
+		{
+			name:          "gallery image deletion fails",
+			deleteErr:     status.Error(codes.Internal, "disk in use"),
+			wantErr:       true,
+			wantReason:    infrav1.DeletionFailedReason,
+			wantFinalizer: true,
+		},
+		{
+			name:          "gallery unreachable",
+			deleteErr:     status.Error(codes.Unavailable, "connection refused"),
+			wantResult:    reconcile.Result{RequeueAfter: azurestackhci.DefaultMOCUnreachableRetryInterval},
+			wantReason:    infrav1.MOCUnreachableReason,
+			wantFinalizer: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			image := newTestImage()
+			image.Finalizers = []string{infrav1.ImageFinalizer}
+			imageScope, c := newTestImageScope(g, image)
+			gallery := &fakeImageGallery{image: newTestGalleryImage(wssdcommon.ProvisionState_CREATED, 100), deleteErr: tc.deleteErr}
+			recorder := record.NewFakeRecorder(10)
+			r := &AzureStackHCIImageReconciler{Client: c, Recorder: recorder}
+
+			result, err := r.reconcileDelete(imageScope, gallery)
+			g.Expect(gallery.deleted).To(BeTrue())
+			g.Expect(imageScope.AzureStackHCIImage.Status.State).To(Equal(infrav1.ImageStateDeleting))
+			g.Expect(controllerutil.ContainsFinalizer(imageScope.AzureStackHCIImage, infrav1.ImageFinalizer)).To(Equal(tc.wantFinalizer))
+			g.Expect(result).To(Equal(tc.wantResult))
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.wantReason == "" {
+				g.Expect(recorder.Events).To(BeEmpty())
+				return
+			}
+			g.Expect(recorder.Events).To(HaveLen(1))
+			g.Expect(conditions.GetReason(imageScope.AzureStackHCIImage, infrav1.ImageReadyCondition)).To(Equal(tc.wantReason))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/loadbalancers"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileAutoscaling gathers the replica metrics and adjusts spec.Replicas once a recommendation held for the
+// stabilization window. Scale ups apply the lowest and scale downs the highest recommendation of the window.
+// Failing to gather metrics is reported on the AutoscalingActive condition and leaves the replicas unchanged.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileAutoscaling(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope) {
+	autoscaling := lbs.AzureStackHCILoadBalancer.Spec.Autoscaling
+	if autoscaling == nil {
+		lbs.AzureStackHCILoadBalancer.Status.Autoscaling = nil
+		conditions.Delete(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerAutoscalingActiveCondition)
+		return
+	}
+
+	status := lbs.GetAutoscalingStatus()
+	activeConnections, err := r.reconcileReplicaMetrics(lbs, clusterScope, status)
+	if err != nil {
+		lbs.Info("Unable to gather loadbalancer metrics", "error", err.Error())
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerAutoscalingActiveCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.LoadBalancerMetricsUnavailableReason,
+			Message: err.Error(),
+		})
+		return
+	}
+	conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+		Type:   infrav1.LoadBalancerAutoscalingActiveCondition,
+		Status: metav1.ConditionTrue,
+		Reason: "MetricsAvailable",
+	})
+
+	r.stabilizeReplicas(lbs, status, recommendedReplicas(autoscaling, activeConnections), activeConnections)
+}
+
+// stabilizeReplicas sets spec.Replicas to the recommended replicas once the recommendation held for the
+// stabilization window of its direction.
+func (r *AzureStackHCILoadBalancerReconciler) stabilizeReplicas(lbs *scope.LoadBalancerScope, status *infrav1.LoadBalancerAutoscalingStatus, recommended int32, activeConnections int64) {
+	current := lbs.GetDesiredReplicas()
+	if recommended == current {
+		status.RecommendedReplicas = current
+		status.RecommendedSince = nil
+		return
+	}
+
+	scaleUp := recommended > current
+	if status.RecommendedSince == nil || (status.RecommendedReplicas > current) != scaleUp {
+		// a new recommendation, or one in the other direction, restarts the stabilization window
+		now := metav1.Now()
+		status.RecommendedReplicas = recommended
+		status.RecommendedSince = &now
+		return
+	}
+	if scaleUp {
+		status.RecommendedReplicas = min(status.RecommendedReplicas, recommended)
+	} else {
+		status.RecommendedReplicas = max(status.RecommendedReplicas, recommended)
+	}
+
+	if time.Since(status.RecommendedSince.Time) < lbs.GetStabilizationWindow(scaleUp) {
+		return
+	}
+
+	r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "AutoscalingLoadBalancer",
+		"Scaling AzureStackHCILoadBalancer %s from %d to %d replicas for %d active connections", lbs.Name(), current, status.RecommendedReplicas, activeConnections)
+	lbs.SetDesiredReplicas(status.RecommendedReplicas)
+	now := metav1.Now()
+	status.LastScaleTime = &now
+	status.RecommendedSince = nil
+}
+
+// reconcileReplicaMetrics records the metrics of every replica in the status and returns the active connections of all replicas.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileReplicaMetrics(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, status *infrav1.LoadBalancerAutoscalingStatus) (int64, error) {
+	vmList, err := r.getVirtualMachinesForLoadBalancer(lbs, clusterScope)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get loadbalancer virtual machine list")
+	}
+
+	replicas := make([]string, 0, len(vmList))
+	for _, vm := range vmList {
+		replicas = append(replicas, vm.Name)
+	}
+	metrics, err := loadbalancers.NewService(clusterScope).GetReplicaMetrics(clusterScope.Context, &loadbalancers.Spec{Name: lbs.Name()}, replicas)
+	if err != nil {
+		return 0, err
+	}
+
+	var activeConnections int64
+	status.Replicas = make([]infrav1.LoadBalancerReplicaMetrics, 0, len(replicas))
+	for _, name := range replicas {
+		replicaMetrics := metrics[name]
+		status.Replicas = append(status.Replicas, infrav1.LoadBalancerReplicaMetrics{
+			Name:              name,
+			ActiveConnections: replicaMetrics.ActiveConnections,
+			BytesPerSecond:    replicaMetrics.BytesPerSecond,
+		})
+		activeConnections += replicaMetrics.ActiveConnections
+	}
+	now := metav1.Now()
+	status.ActiveConnections = activeConnections
+	status.LastMetricsTime = &now
+	return activeConnections, nil
+}
+
+// recommendedReplicas returns the number of replicas serving the target connections per replica, within the limits.
+func recommendedReplicas(autoscaling *infrav1.LoadBalancerAutoscaling, activeConnections int64) int32 {
+	replicas := (activeConnections + autoscaling.TargetConnectionsPerReplica - 1) / autoscaling.TargetConnectionsPerReplica
+	// Assume that overflow will not happen for G115
+	return int32(min(max(replicas, int64(autoscaling.MinReplicas)), int64(autoscaling.MaxReplicas))) //nolint
+}
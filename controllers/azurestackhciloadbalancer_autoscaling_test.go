@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+func TestRecommendedReplicas(t *testing.T) {
+	autoscaling := &infrav1.LoadBalancerAutoscaling{MinReplicas: 2, MaxReplicas: 5, TargetConnectionsPerReplica: 100}
+	tests := []struct {
+		name              string
+		activeConnections int64
+		want              int32
+	}{
+		{name: "idle keeps the minimum", activeConnections: 0, want: 2},
+		{name: "exact multiple", activeConnections: 300, want: 3},
+		{name: "rounds up", activeConnections: 301, want: 4},
+		{name: "capped at the maximum", activeConnections: 10000, want: 5},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(recommendedReplicas(autoscaling, tc.activeConnections)).To(Equal(tc.want))
+		})
+	}
+}
+
+// TestStabilizeReplicas verifies that a recommendation is only applied once it held for the stabilization window
+// of its direction, and that a recommendation in the other direction restarts the window.
+func TestStabilizeReplicas(t *testing.T) {
+	since := func(d time.Duration) *metav1.Time {
+		ts := metav1.NewTime(time.Now().Add(-d))
+		return &ts
+	}
+
+	tests := []struct {
+		name              string
+		status            infrav1.LoadBalancerAutoscalingStatus
+		recommended       int32
+		wantReplicas      int32
+		wantRecommended   int32
+		wantWindowRunning bool
+		wantScaled        bool
+	}{
+		{
+			name:            "recommendation matches the replicas",
+			status:          infrav1.LoadBalancerAutoscalingStatus{RecommendedReplicas: 4, RecommendedSince: since(time.Hour)},
+			recommended:     3,
+			wantReplicas:    3,
+			wantRecommended: 3,
+		},
+		{
+			name:              "new scale up starts the window",
+			recommended:       5,
+			wantReplicas:      3,
+			wantRecommended:   5,
+			wantWindowRunning: true,
+		},
+		{
+			name:              "scale up within the window keeps the lowest recommendation",
+			status:            infrav1.LoadBalancerAutoscalingStatus{RecommendedReplicas: 4, RecommendedSince: since(time.Second)},
+			recommended:       5,
+			wantReplicas:      3,
+			wantRecommended:   4,
+			wantWindowRunning: true,
+		},
+		{
+			name:            "scale up after the window",
+			status:          infrav1.LoadBalancerAutoscalingStatus{RecommendedReplicas: 4, RecommendedSince: since(2 * time.Minute)},
+			recommended:     5,
+			wantReplicas:    4,
+			wantRecommended: 4,
+			wantScaled:      true,
+		},
+		{
+			name:              "scale down within the window",
+			status:            infrav1.LoadBalancerAutoscalingStatus{RecommendedReplicas: 2, RecommendedSince: since(2 * time.Minute)},
+			recommended:       1,
+			wantReplicas:      3,
+			wantRecommended:   2,
+			wantWindowRunning: true,
+		},
+		{
+			name:            "scale down after the window keeps the highest recommendation",
+			status:          infrav1.LoadBalancerAutoscalingStatus{RecommendedReplicas: 2, RecommendedSince: since(10 * time.Minute)},
+			recommended:     1,
+			wantReplicas:    2,
+			wantRecommended: 2,
+			wantScaled:      true,
+		},
+		{
+			name:              "change of direction restarts the window",
+			status:            infrav1.LoadBalancerAutoscalingStatus{RecommendedReplicas: 2, RecommendedSince: since(10 * time.Minute)},
+			recommended:       5,
+			wantReplicas:      3,
+			wantRecommended:   5,
+			wantWindowRunning: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			lbs := newTestLoadBalancerScope(3, "v2")
+			status := tc.status.DeepCopy()
+			r := &AzureStackHCILoadBalancerReconciler{Recorder: record.NewFakeRecorder(10)}
+
+			r.stabilizeReplicas(lbs, status, tc.recommended, 0)
+
+			g.Expect(lbs.GetDesiredReplicas()).To(Equal(tc.wantReplicas))
+			g.Expect(status.RecommendedReplicas).To(Equal(tc.wantRecommended))
+			g.Expect(status.RecommendedSince != nil).To(Equal(tc.wantWindowRunning))
+			g.Expect(status.LastScaleTime != nil).To(Equal(tc.wantScaled))
+		})
+	}
+}
+
+// TestReconcileAutoscalingDisabled verifies that the autoscaling status is cleared when autoscaling is disabled.
+func TestReconcileAutoscalingDisabled(t *testing.T) {
+	g := NewWithT(t)
+	lbs := newTestLoadBalancerScope(3, "v2")
+	lbs.AzureStackHCILoadBalancer.Status.Autoscaling = &infrav1.LoadBalancerAutoscalingStatus{RecommendedReplicas: 5}
+	conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+		Type:   infrav1.LoadBalancerAutoscalingActiveCondition,
+		Status: metav1.ConditionTrue,
+		Reason: "MetricsAvailable",
+	})
+	r := &AzureStackHCILoadBalancerReconciler{Recorder: record.NewFakeRecorder(10)}
+
+	r.reconcileAutoscaling(lbs, nil)
+
+	g.Expect(lbs.AzureStackHCILoadBalancer.Status.Autoscaling).To(BeNil())
+	g.Expect(conditions.Has(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerAutoscalingActiveCondition)).To(BeFalse())
+	g.Expect(lbs.GetDesiredReplicas()).To(Equal(int32(3)))
+}
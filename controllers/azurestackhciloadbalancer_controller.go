@@ -27,17 +27,21 @@ import (
 	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/loadbalancers"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/vippools"
 	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
 	"github.com/microsoft/moc-sdk-for-go/services/network"
-	mocerrors "github.com/microsoft/moc/pkg/errors"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/paused"
+	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -62,6 +66,11 @@ func (r *AzureStackHCILoadBalancerReconciler) SetupWithManager(mgr ctrl.Manager,
 			&infrav1.AzureStackHCIVirtualMachine{},
 			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrav1.AzureStackHCILoadBalancer{}),
 		).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.ClusterToAzureStackHCILoadBalancer),
+			builder.WithPredicates(predicates.ClusterPausedTransitions(mgr.GetScheme(), r.Log)),
+		).
 		Complete(r)
 }
 
@@ -114,6 +123,11 @@ func (r *AzureStackHCILoadBalancerReconciler) Reconcile(ctx context.Context, req
 
 	logger = logger.WithValues("cluster", cluster.Name)
 
+	// Return early if the object or Cluster is paused.
+	if isPaused, requeue, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, azureStackHCILoadBalancer); err != nil || isPaused || requeue {
+		return reconcile.Result{}, err
+	}
+
 	azureStackHCICluster := &infrav1.AzureStackHCICluster{}
 	azureStackHCIClusterName := client.ObjectKey{
 		Namespace: azureStackHCILoadBalancer.Namespace,
@@ -185,60 +199,77 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileNormal(lbs *scope.LoadBal
 		return reconcile.Result{}, err
 	}
 
+	// adjust the desired replicas to the load before the replicas are reconciled
+	r.reconcileAutoscaling(lbs, clusterScope)
+
+	// spread the replicas across hosts before they are created
+	if err := r.reconcilePlacement(lbs, clusterScope); err != nil {
+		class := classifyError(err, infrav1.LoadBalancerMachineReconciliationFailedReason, lbs)
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureReconcileLBPlacement", errors.Wrapf(err, "Failed to reconcile LoadBalancer placement").Error())
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
+		return reconcileResultForError(class, err)
+	}
+
 	result, err := r.reconcileVirtualMachines(lbs, clusterScope)
 	if err != nil {
+		class := classifyError(err, infrav1.LoadBalancerMachineReconciliationFailedReason, lbs)
 		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureReconcileLBMachines", errors.Wrapf(err, "Failed to reconcile LoadBalancer machines").Error())
 		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
 			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  infrav1.LoadBalancerMachineReconciliationFailedReason,
+			Reason:  class.Reason,
 			Message: err.Error(),
 		})
-		return reconcile.Result{}, err
+		return reconcileResultForError(class, err)
+	}
+
+	// resolve the frontend address before the loadbalancer service is created
+	frontendAddress, err := r.reconcileFrontendAddress(lbs, clusterScope, lbs.Name(), lbs.Address(), lbs.AzureStackHCILoadBalancer.Spec.StaticVIP)
+	if err != nil {
+		class := classifyError(err, infrav1.LoadBalancerVipPoolUnavailableReason, lbs)
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureReconcileVipPool", errors.Wrapf(err, "Failed to reserve a frontend address for LoadBalancer").Error())
+		return reconcileResultForError(class, err)
 	}
 
 	// reconcile the loadbalancer service and the lb frontend ip address
-	err = r.reconcileLoadBalancerService(lbs, clusterScope)
+	err = r.reconcileLoadBalancerService(lbs, clusterScope, frontendAddress)
 	if err != nil {
-		switch mocerrors.GetErrorCode(err) {
-		case mocerrors.OutOfMemory.Error():
-			conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
-				Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
-				Status:  metav1.ConditionFalse,
-				Reason:  infrav1.OutOfMemoryReason,
-				Message: err.Error(),
-			})
-		case mocerrors.OutOfCapacity.Error():
-			conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
-				Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
-				Status:  metav1.ConditionFalse,
-				Reason:  infrav1.OutOfCapacityReason,
-				Message: err.Error(),
-			})
-		default:
-			conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
-				Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
-				Status:  metav1.ConditionFalse,
-				Reason:  infrav1.LoadBalancerServiceReconciliationFailedReason,
-				Message: err.Error(),
-			})
-		}
+		class := classifyError(err, infrav1.LoadBalancerServiceReconciliationFailedReason, lbs)
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
 
 		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureReconcileLB", errors.Wrapf(err, "Failed to reconcile LoadBalancer service").Error())
-		return reconcile.Result{}, err
+		return reconcileResultForError(class, err)
 	}
+	lbs.ResetCapacityRetries()
 
 	if lbs.Address() == "" {
 		err := r.reconcileLoadBalancerServiceStatus(lbs, clusterScope)
 		if err != nil {
+			class := classifyError(err, infrav1.LoadBalancerServiceStatusFailedReason, nil)
 			r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureReconcileLBStatus", errors.Wrapf(err, "Failed to reconcile LoadBalancer service status").Error())
 			conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
 				Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
 				Status:  metav1.ConditionFalse,
-				Reason:  infrav1.LoadBalancerServiceStatusFailedReason,
+				Reason:  class.Reason,
 				Message: err.Error(),
 			})
-			return reconcile.Result{}, err
+			return reconcileResultForError(class, err)
 		}
 		if lbs.Address() == "" {
 			lbs.Info("LoadBalancer service address is not available yet")
@@ -252,6 +283,19 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileNormal(lbs *scope.LoadBal
 		}
 	}
 
+	// reconcile the loadbalancer services of the workload listeners
+	if err := r.reconcileListeners(lbs, clusterScope); err != nil {
+		class := classifyError(err, infrav1.LoadBalancerServiceReconciliationFailedReason, lbs)
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureReconcileLBListeners", errors.Wrapf(err, "Failed to reconcile LoadBalancer listeners").Error())
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
+		return reconcileResultForError(class, err)
+	}
+
 	// When a SDN integration is present, LB replica count will be 0 as the loadbalancing is handled by SDN.
 	// So fail only if the configured replica count is not 0.
 	if lbs.GetReplicas() != 0 && lbs.GetReadyReplicas() < 1 {
@@ -298,8 +342,32 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileLoadBalancerServiceStatus
 		return errors.New("error getting status for loadbalancer service")
 	}
 
-	if loadBalancerScope.Address() == "" {
-		loadBalancerScope.SetAddress(*((*lb.FrontendIPConfigurations)[0].IPAddress))
+	frontendAddress := loadbalancers.FrontendIPAddress(lb)
+
+	// the address is pinned once assigned, a different frontend address means the
+	// loadbalancer service was recreated outside of this controller
+	switch {
+	case frontendAddress == "":
+	case loadBalancerScope.Address() == "":
+		loadBalancerScope.SetAddress(frontendAddress)
+	case loadBalancerScope.Address() != frontendAddress:
+		if !conditions.IsFalse(loadBalancerScope.AzureStackHCILoadBalancer, infrav1.LoadBalancerAddressPinnedCondition) {
+			r.Recorder.Eventf(loadBalancerScope.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "LoadBalancerAddressDrifted",
+				"LoadBalancer frontend address %s does not match the pinned address %s", frontendAddress, loadBalancerScope.Address())
+		}
+		conditions.Set(loadBalancerScope.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerAddressPinnedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.LoadBalancerAddressDriftedReason,
+			Message: fmt.Sprintf("frontend address %s does not match the pinned address %s", frontendAddress, loadBalancerScope.Address()),
+		})
+	}
+	if frontendAddress != "" && frontendAddress == loadBalancerScope.Address() {
+		conditions.Set(loadBalancerScope.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:   infrav1.LoadBalancerAddressPinnedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: infrav1.InfrastructureReadyReason,
+		})
 	}
 
 	// Assume that overflow will not happen for G115
@@ -307,50 +375,247 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileLoadBalancerServiceStatus
 	return nil
 }
 
-func (r *AzureStackHCILoadBalancerReconciler) reconcileLoadBalancerService(loadBalancerScope *scope.LoadBalancerScope, clusterScope *scope.ClusterScope) error {
+// reconcileFrontendAddress returns the frontend address to request for a loadbalancer service that does not exist yet.
+// An empty address lets MOC allocate one. When a vip pool is referenced it is validated to have a free address.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileFrontendAddress(loadBalancerScope *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, name, address, staticVIP string) (string, error) {
+	lbSpec := loadBalancerScope.AzureStackHCILoadBalancer.Spec
+	if address != "" {
+		return address, nil
+	}
+
+	lbSvc := loadbalancers.NewService(clusterScope)
+	_, err := lbSvc.Get(clusterScope.Context, &loadbalancers.Spec{Name: name})
+	if err == nil {
+		// the loadbalancer service already exists, its address is reported by the service status
+		return "", nil
+	}
+	if !azurestackhci.ResourceNotFound(err) {
+		// don't allocate a second address for a loadbalancer that may exist
+		return "", errors.Wrapf(err, "failed to get loadbalancer %s", name)
+	}
+
+	if lbSpec.VipPool == nil {
+		return staticVIP, nil
+	}
+
+	vpInterface, err := vippools.NewService(clusterScope).Get(clusterScope.Context, &vippools.Spec{
+		Name:     lbSpec.VipPool.Name,
+		Location: clusterScope.Location(),
+	})
+	if err != nil && !azurestackhci.ResourceNotFound(err) {
+		return "", errors.Wrapf(err, "failed to get vippool %s", lbSpec.VipPool.Name)
+	}
+	vp, ok := vpInterface.(network.VipPool)
+	if !ok {
+		return "", errors.Wrapf(vippools.ErrVipPoolNotFound, "vippool %s does not exist", lbSpec.VipPool.Name)
+	}
+
+	// Only the loadbalancers of the cluster resource group are listed, a vip pool shared with other resource
+	// groups may hand out an address already used there.
+	existing, err := lbSvc.List(clusterScope.Context)
+	if err != nil && !azurestackhci.ResourceNotFound(err) {
+		return "", errors.Wrapf(err, "failed to list loadbalancers")
+	}
+	inUse := []string{}
+	for _, lb := range existing {
+		if address := loadbalancers.FrontendIPAddress(lb); address != "" {
+			inUse = append(inUse, address)
+		}
+	}
+
+	if staticVIP != "" {
+		contains, err := vippools.Contains(vp, staticVIP)
+		if err != nil {
+			return "", err
+		}
+		if !contains {
+			return "", errors.Errorf("static vip %s is not within vippool %s", staticVIP, lbSpec.VipPool.Name)
+		}
+		for _, inUseAddress := range inUse {
+			if inUseAddress == staticVIP {
+				return "", errors.Errorf("static vip %s is already in use by another loadbalancer", staticVIP)
+			}
+		}
+		return staticVIP, nil
+	}
+
+	freeAddress, err := vippools.NextFreeAddress(vp, inUse)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to allocate an address from vippool %s", lbSpec.VipPool.Name)
+	}
+	return freeAddress, nil
+}
+
+func (r *AzureStackHCILoadBalancerReconciler) reconcileLoadBalancerService(loadBalancerScope *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, frontendAddress string) error {
 	backendPoolName := azurestackhci.GenerateControlPlaneBackendPoolName(clusterScope.Name())
 	loadBalancerScope.SetPort(clusterScope.APIServerPort())
 	role := azurestackhci.LBRoleAksHciApiServer
 	tags := map[string]*string{azurestackhci.LBRoleTagName: &role}
 	lbSpec := &loadbalancers.Spec{
-		Name:            loadBalancerScope.AzureStackHCILoadBalancer.Name,
-		BackendPoolName: backendPoolName,
-		FrontendPort:    loadBalancerScope.GetPort(),
-		BackendPort:     clusterScope.APIServerPort(),
-		VnetName:        clusterScope.AzureStackHCICluster.Spec.NetworkSpec.Vnet.Name,
-		Tags:            tags,
+		Name:              loadBalancerScope.AzureStackHCILoadBalancer.Name,
+		BackendPoolName:   backendPoolName,
+		VnetName:          clusterScope.AzureStackHCICluster.Spec.NetworkSpec.Vnet.Name,
+		Tags:              tags,
+		FrontendIPAddress: frontendAddress,
 	}
+	lbSpec.Rules, lbSpec.Probes = generateLoadBalancingRules(loadBalancerScope.AzureStackHCILoadBalancer, loadBalancerScope.GetPort(), clusterScope.APIServerPort())
 
-	if err := loadbalancers.NewService(clusterScope).Reconcile(clusterScope.Context, lbSpec); err != nil {
+	lbSvc := loadbalancers.NewService(clusterScope)
+	if err := lbSvc.Reconcile(clusterScope.Context, lbSpec); err != nil {
 		return errors.Wrapf(err, "failed to reconcile loadbalancer %s", loadBalancerScope.AzureStackHCILoadBalancer.Name)
 	}
 
+	if !clusterScope.Vnet().IsIPv6Enabled() {
+		return nil
+	}
+
+	// a loadbalancer service has a single frontend, the IPv6 frontend of a dual-stack
+	// cluster is served by a second loadbalancer service with the same rules
+	ipv6Spec := *lbSpec
+	ipv6Spec.Name = azurestackhci.GenerateAzureStackHCILoadBalancerIPv6Name(lbSpec.Name)
+	ipv6Spec.FrontendIPAddress = loadBalancerScope.AzureStackHCILoadBalancer.Status.IPv6Address
+	ipv6Spec.FrontendIPVersion = network.IPv6
+	if err := lbSvc.Reconcile(clusterScope.Context, &ipv6Spec); err != nil {
+		return errors.Wrapf(err, "failed to reconcile loadbalancer %s", ipv6Spec.Name)
+	}
+
+	if loadBalancerScope.AzureStackHCILoadBalancer.Status.IPv6Address == "" {
+		lbInterface, err := lbSvc.Get(clusterScope.Context, &loadbalancers.Spec{Name: ipv6Spec.Name})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get loadbalancer %s", ipv6Spec.Name)
+		}
+		if lb, ok := lbInterface.(network.LoadBalancer); ok {
+			loadBalancerScope.AzureStackHCILoadBalancer.Status.IPv6Address = loadbalancers.FrontendIPAddress(lb)
+		}
+	}
+
 	return nil
 }
 
+// generateLoadBalancingRules returns the rules and probes of the loadbalancer. The api server rule probes
+// /readyz by default and is replaced by a rule of the spec that uses the same frontend port.
+func generateLoadBalancingRules(lb *infrav1.AzureStackHCILoadBalancer, frontendPort, apiServerPort int32) ([]loadbalancers.Rule, []loadbalancers.Probe) {
+	rules, probes := convertLoadBalancingRules(lb.Spec.Rules, lb.Spec.Probes)
+
+	for _, rule := range rules {
+		if rule.FrontendPort == frontendPort {
+			return rules, probes
+		}
+	}
+
+	rules = append([]loadbalancers.Rule{{
+		Name:         azurestackhci.APIServerRuleName,
+		Protocol:     network.TransportProtocolTCP,
+		FrontendPort: frontendPort,
+		BackendPort:  apiServerPort,
+		ProbeName:    azurestackhci.APIServerProbeName,
+	}}, rules...)
+	probes = append([]loadbalancers.Probe{{
+		Name:              azurestackhci.APIServerProbeName,
+		Protocol:          network.ProbeProtocolHTTPS,
+		Port:              apiServerPort,
+		RequestPath:       azurestackhci.DefaultAPIServerProbePath,
+		IntervalInSeconds: azurestackhci.DefaultProbeIntervalInSeconds,
+		NumberOfProbes:    azurestackhci.DefaultProbeThreshold,
+	}}, probes...)
+	return rules, probes
+}
+
+// convertLoadBalancingRules converts the rules and probes of the api to the loadbalancers service specification.
+func convertLoadBalancingRules(specRules []infrav1.LoadBalancingRule, specProbes []infrav1.LoadBalancerProbe) ([]loadbalancers.Rule, []loadbalancers.Probe) {
+	rules := []loadbalancers.Rule{}
+	for _, rule := range specRules {
+		protocol := rule.Protocol
+		if protocol == "" {
+			protocol = infrav1.LoadBalancerProtocolTCP
+		}
+		rules = append(rules, loadbalancers.Rule{
+			Name:                 rule.Name,
+			Protocol:             network.TransportProtocol(protocol),
+			FrontendPort:         rule.FrontendPort,
+			BackendPort:          rule.BackendPort,
+			IdleTimeoutInMinutes: rule.IdleTimeoutInMinutes,
+			EnableFloatingIP:     rule.EnableFloatingIP,
+			ProbeName:            rule.ProbeName,
+		})
+	}
+
+	probes := []loadbalancers.Probe{}
+	for _, probe := range specProbes {
+		interval := int32(azurestackhci.DefaultProbeIntervalInSeconds)
+		if probe.IntervalInSeconds != nil {
+			interval = *probe.IntervalInSeconds
+		}
+		threshold := int32(azurestackhci.DefaultProbeThreshold)
+		if probe.NumberOfProbes != nil {
+			threshold = *probe.NumberOfProbes
+		}
+		probes = append(probes, loadbalancers.Probe{
+			Name:              probe.Name,
+			Protocol:          network.ProbeProtocol(probe.Protocol),
+			Port:              probe.Port,
+			RequestPath:       probe.RequestPath,
+			IntervalInSeconds: interval,
+			NumberOfProbes:    threshold,
+		})
+	}
+
+	return rules, probes
+}
+
 func (r *AzureStackHCILoadBalancerReconciler) reconcileDelete(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	lbs.Info("Handling deleted AzureStackHCILoadBalancer", "LoadBalancer", lbs.AzureStackHCILoadBalancer.Name)
 
+	if err := r.reconcileDeleteListeners(lbs, clusterScope); err != nil {
+		class := classifyError(err, infrav1.DeletionFailedReason, nil)
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureDeleteLoadBalancerListeners", errors.Wrapf(err, "Error deleting listeners of AzureStackHCILoadBalancer %s", lbs.Name()).Error())
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
+		return reconcileResultForError(class, err)
+	}
+
 	if err := r.reconcileDeleteLoadBalancerService(lbs, clusterScope); err != nil {
+		class := classifyError(err, infrav1.DeletionFailedReason, nil)
 		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureDeleteLoadBalancer", errors.Wrapf(err, "Error deleting AzureStackHCILoadBalancer %s", lbs.Name()).Error())
 		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
 			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  infrav1.DeletionFailedReason,
+			Reason:  class.Reason,
 			Message: err.Error(),
 		})
-		return reconcile.Result{}, err
+		return reconcileResultForError(class, err)
 	}
 
 	if err := r.reconcileDeleteVirtualMachines(lbs, clusterScope); err != nil {
+		class := classifyError(err, infrav1.DeletionFailedReason, nil)
 		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureDeleteLoadBalancerMachines", errors.Wrapf(err, "Error deleting machines for AzureStackHCILoadBalancer %s", lbs.Name()).Error())
 		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
 			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  infrav1.DeletionFailedReason,
+			Reason:  class.Reason,
 			Message: err.Error(),
 		})
-		return reconcile.Result{}, err
+		return reconcileResultForError(class, err)
+	}
+
+	deleted, err := r.reconcileDeletePlacement(lbs, clusterScope)
+	if err != nil {
+		class := classifyError(err, infrav1.DeletionFailedReason, nil)
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "FailureDeleteLoadBalancerPlacement", errors.Wrapf(err, "Error deleting placement of AzureStackHCILoadBalancer %s", lbs.Name()).Error())
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerInfrastructureReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
+		return reconcileResultForError(class, err)
+	}
+	if !deleted {
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
 	}
 
 	controllerutil.RemoveFinalizer(lbs.AzureStackHCILoadBalancer, infrav1.AzureStackHCILoadBalancerFinalizer)
@@ -369,12 +634,23 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileDeleteLoadBalancerService
 	lbSpec := &loadbalancers.Spec{
 		Name: loadBalancerScope.AzureStackHCILoadBalancer.Name,
 	}
-	if err := loadbalancers.NewService(clusterScope).Delete(clusterScope.Context, lbSpec); err != nil {
+	lbSvc := loadbalancers.NewService(clusterScope)
+	if err := lbSvc.Delete(clusterScope.Context, lbSpec); err != nil {
 		if !azurestackhci.ResourceNotFound(err) {
 			return errors.Wrapf(err, "failed to delete loadbalancer %s", loadBalancerScope.AzureStackHCILoadBalancer.Name)
 		}
 	}
 
+	ipv6Spec := &loadbalancers.Spec{
+		Name: azurestackhci.GenerateAzureStackHCILoadBalancerIPv6Name(loadBalancerScope.AzureStackHCILoadBalancer.Name),
+	}
+	if err := lbSvc.Delete(clusterScope.Context, ipv6Spec); err != nil {
+		if !azurestackhci.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete loadbalancer %s", ipv6Spec.Name)
+		}
+	}
+	loadBalancerScope.AzureStackHCILoadBalancer.Status.IPv6Address = ""
+
 	return nil
 }
 
@@ -429,3 +705,16 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileStatus(lbs *scope.LoadBal
 
 	lbs.SetPhase(infrav1.AzureStackHCILoadBalancerPhaseProvisioned)
 }
+
+// ClusterToAzureStackHCILoadBalancer is a handler.ToRequestsFunc to be used to enqueue a request for reconciliation
+// of the AzureStackHCILoadBalancer of a Cluster.
+func (r *AzureStackHCILoadBalancerReconciler) ClusterToAzureStackHCILoadBalancer(ctx context.Context, o client.Object) []ctrl.Request {
+	c, ok := o.(*clusterv1.Cluster)
+	if !ok {
+		r.Log.Error(errors.Errorf("expected a Cluster but got a %T", o), "failed to get AzureStackHCILoadBalancer for Cluster")
+		return nil
+	}
+
+	name := client.ObjectKey{Namespace: c.Namespace, Name: azurestackhci.GenerateAzureStackHCILoadBalancerName(c.Name)}
+	return []ctrl.Request{{NamespacedName: name}}
+}
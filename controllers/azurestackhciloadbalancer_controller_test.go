@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/microsoft/moc-sdk-for-go/services/network"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/loadbalancers"
+)
+
+// TestGenerateLoadBalancingRules verifies that the api server rule and probe are added unless a rule of the spec
+// already forwards the api server port, and that the probe defaults are applied.
+func TestGenerateLoadBalancingRules(t *testing.T) {
+	apiServerRule := loadbalancers.Rule{
+		Name:         azurestackhci.APIServerRuleName,
+		Protocol:     network.TransportProtocolTCP,
+		FrontendPort: 443,
+		BackendPort:  6443,
+		ProbeName:    azurestackhci.APIServerProbeName,
+	}
+	apiServerProbe := loadbalancers.Probe{
+		Name:              azurestackhci.APIServerProbeName,
+		Protocol:          network.ProbeProtocolHTTPS,
+		Port:              6443,
+		RequestPath:       azurestackhci.DefaultAPIServerProbePath,
+		IntervalInSeconds: azurestackhci.DefaultProbeIntervalInSeconds,
+		NumberOfProbes:    azurestackhci.DefaultProbeThreshold,
+	}
+	tests := []struct {
+		name       string
+		rules      []infrav1.LoadBalancingRule
+		probes     []infrav1.LoadBalancerProbe
+		wantRules  []loadbalancers.Rule
+		wantProbes []loadbalancers.Probe
+	}{
+		{
+			name:       "default api server rule",
+			wantRules:  []loadbalancers.Rule{apiServerRule},
+			wantProbes: []loadbalancers.Probe{apiServerProbe},
+		},
+		{
+			name:   "additional rule",
+			rules:  []infrav1.LoadBalancingRule{{Name: "dns", Protocol: infrav1.LoadBalancerProtocolUDP, FrontendPort: 53, BackendPort: 30053}},
+			probes: []infrav1.LoadBalancerProbe{{Name: "dns", Protocol: infrav1.LoadBalancerProbeProtocolTCP, Port: 30053, IntervalInSeconds: ptr.To[int32](10)}},
+			wantRules: []loadbalancers.Rule{
+				apiServerRule,
+				{Name: "dns", Protocol: network.TransportProtocolUDP, FrontendPort: 53, BackendPort: 30053},
+			},
+			wantProbes: []loadbalancers.Probe{
+				apiServerProbe,
+				{Name: "dns", Protocol: network.ProbeProtocolTCP, Port: 30053, IntervalInSeconds: 10, NumberOfProbes: azurestackhci.DefaultProbeThreshold},
+			},
+		},
+		{
+			name:       "api server rule replaced",
+			rules:      []infrav1.LoadBalancingRule{{Name: "custom", FrontendPort: 443, BackendPort: 6443}},
+			wantRules:  []loadbalancers.Rule{{Name: "custom", Protocol: network.TransportProtocolTCP, FrontendPort: 443, BackendPort: 6443}},
+			wantProbes: []loadbalancers.Probe{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			lb := &infrav1.AzureStackHCILoadBalancer{
+				Spec: infrav1.AzureStackHCILoadBalancerSpec{Rules: tc.rules, Probes: tc.probes},
+			}
+			rules, probes := generateLoadBalancingRules(lb, 443, 6443)
+			g.Expect(rules).To(Equal(tc.wantRules))
+			g.Expect(probes).To(Equal(tc.wantProbes))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileReplicaHealth deletes unhealthy replicas so they are recreated by the next scale up. Nothing is
+// remediated when more replicas than MaxUnhealthy are unhealthy. Returns true if replicas were remediated.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileReplicaHealth(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, vmList []*infrav1.AzureStackHCIVirtualMachine) (bool, error) {
+	unhealthy := r.getUnhealthyVirtualMachines(lbs, vmList)
+	// Assume that overflow will not happen for G115
+	unhealthyReplicas := int32(len(unhealthy)) //nolint
+	lbs.SetUnhealthyReplicas(unhealthyReplicas)
+
+	if len(unhealthy) == 0 {
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:   infrav1.LoadBalancerReplicasHealthyCondition,
+			Status: metav1.ConditionTrue,
+			Reason: "AllReplicasHealthy",
+		})
+		return false, nil
+	}
+
+	if !lbs.HealthCheckEnabled() {
+		return false, nil
+	}
+
+	if unhealthyReplicas > lbs.GetMaxUnhealthy() {
+		if conditions.GetReason(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerReplicasHealthyCondition) != infrav1.LoadBalancerRemediationNotAllowedReason {
+			r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "RemediationNotAllowed",
+				"%d replicas of AzureStackHCILoadBalancer %s are unhealthy, which exceeds the budget of %d", unhealthyReplicas, lbs.Name(), lbs.GetMaxUnhealthy())
+		}
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerReplicasHealthyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.LoadBalancerRemediationNotAllowedReason,
+			Message: fmt.Sprintf("%d replicas are unhealthy, remediation is allowed for up to %d", unhealthyReplicas, lbs.GetMaxUnhealthy()),
+		})
+		return false, nil
+	}
+
+	conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+		Type:    infrav1.LoadBalancerReplicasHealthyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrav1.LoadBalancerRemediatingReplicasReason,
+		Message: fmt.Sprintf("remediating %d unhealthy replicas", unhealthyReplicas),
+	})
+	for _, vm := range unhealthy {
+		lbs.Info("Remediating unhealthy replica", "vmName", vm.Name)
+		if err := r.deleteVirtualMachine(lbs, clusterScope, vm); err != nil {
+			return false, errors.Wrapf(err, "failed to remediate loadbalancer VM %s", vm.Name)
+		}
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "RemediatedReplica", "Deleted unhealthy replica %s of AzureStackHCILoadBalancer %s", vm.Name, lbs.Name())
+		lbs.RemoveReplica()
+	}
+
+	return true, nil
+}
+
+// getUnhealthyVirtualMachines returns the replicas whose virtual machine failed, or which are not running or not
+// connected to the loadbalancer service for longer than the unhealthy timeout. MOC only reports how many replicas
+// are connected, so the most recently created running replicas are the ones considered disconnected: older
+// replicas were connected before a scale up or upgrade was allowed to continue.
+func (r *AzureStackHCILoadBalancerReconciler) getUnhealthyVirtualMachines(lbs *scope.LoadBalancerScope, vmList []*infrav1.AzureStackHCIVirtualMachine) []*infrav1.AzureStackHCIVirtualMachine {
+	timeout := lbs.GetUnhealthyTimeout()
+	expired := func(since metav1.Time) bool {
+		return !since.IsZero() && time.Since(since.Time) > timeout
+	}
+
+	unhealthy := []*infrav1.AzureStackHCIVirtualMachine{}
+	running := []*infrav1.AzureStackHCIVirtualMachine{}
+	for _, vm := range vmList {
+		switch {
+		case vm.Status.FailureReason != nil:
+			// the virtual machine isn't retried, it is replaced right away unless its configuration is invalid: a
+			// new replica would fail the same way until the image or vm size is updated
+			if *vm.Status.FailureReason != capierrors.InvalidConfigurationMachineError {
+				unhealthy = append(unhealthy, vm)
+			}
+		case vm.Status.VMState != nil && *vm.Status.VMState == infrav1.VMStateFailed:
+			unhealthy = append(unhealthy, vm)
+		case conditions.IsFalse(vm, infrav1.VMRunningCondition):
+			if cond := conditions.Get(vm, infrav1.VMRunningCondition); cond != nil && expired(cond.LastTransitionTime) {
+				unhealthy = append(unhealthy, vm)
+			}
+		case !vm.Status.Ready:
+			if expired(vm.CreationTimestamp) {
+				unhealthy = append(unhealthy, vm)
+			}
+		default:
+			running = append(running, vm)
+		}
+	}
+
+	// Assume that overflow will not happen for G115
+	disconnected := int32(len(running)) - lbs.GetReadyReplicas() //nolint
+	if disconnected <= 0 {
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:   infrav1.LoadBalancerReplicasServiceConnectedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: "AllReplicasConnected",
+		})
+		return unhealthy
+	}
+
+	conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+		Type:    infrav1.LoadBalancerReplicasServiceConnectedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrav1.LoadBalancerReplicasDisconnectedReason,
+		Message: fmt.Sprintf("%d of %d running replicas are not connected to the loadbalancer service", disconnected, len(running)),
+	})
+	cond := conditions.Get(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerReplicasServiceConnectedCondition)
+	if cond == nil || !expired(cond.LastTransitionTime) {
+		return unhealthy
+	}
+
+	sort.Sort(sort.Reverse(infrav1.VirtualMachinesByCreationTimestamp(running)))
+	return append(unhealthy, running[:disconnected]...)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+func TestGetUnhealthyVirtualMachines(t *testing.T) {
+	failed := func(name string) *infrav1.AzureStackHCIVirtualMachine {
+		vm := newTestReplica(name, time.Hour, "v2")
+		vm.Status.VMState = ptr.To(infrav1.VMStateFailed)
+		return vm
+	}
+	notRunning := func(name string, since time.Duration) *infrav1.AzureStackHCIVirtualMachine {
+		vm := newTestReplica(name, time.Hour, "v2")
+		conditions.Set(vm, metav1.Condition{
+			Type:               infrav1.VMRunningCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Stopped",
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+		})
+		return vm
+	}
+	terminal := func(name string, failure capierrors.MachineStatusError) *infrav1.AzureStackHCIVirtualMachine {
+		vm := notRunning(name, time.Minute)
+		vm.Status.FailureReason = ptr.To(failure)
+		return vm
+	}
+	notReady := func(name string, age time.Duration) *infrav1.AzureStackHCIVirtualMachine {
+		vm := newTestReplica(name, age, "v2")
+		vm.Status.Ready = false
+		return vm
+	}
+
+	tests := []struct {
+		name             string
+		vmList           []*infrav1.AzureStackHCIVirtualMachine
+		readyReplicas    int32
+		disconnectedFor  time.Duration
+		wantUnhealthy    []string
+		wantDisconnected bool
+	}{
+		{
+			name: "healthy",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("a", time.Hour, "v2"),
+				newTestReplica("b", time.Hour, "v2"),
+			},
+			readyReplicas: 2,
+			wantUnhealthy: []string{},
+		},
+		{
+			name: "failed and expired replicas",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				failed("failed"),
+				notRunning("stopped", time.Hour),
+				notRunning("stopping", time.Minute),
+				notReady("stuck", time.Hour),
+				notReady("creating", time.Minute),
+			},
+			wantUnhealthy: []string{"failed", "stopped", "stuck"},
+		},
+		{
+			name: "terminally failed replicas",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				terminal("out-of-capacity", capierrors.InsufficientResourcesMachineError),
+				terminal("invalid", capierrors.InvalidConfigurationMachineError),
+			},
+			wantUnhealthy: []string{"out-of-capacity"},
+		},
+		{
+			name: "youngest running replica is disconnected",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("old", time.Hour, "v2"),
+				newTestReplica("young", time.Minute*30, "v2"),
+			},
+			readyReplicas:    1,
+			disconnectedFor:  time.Hour,
+			wantUnhealthy:    []string{"young"},
+			wantDisconnected: true,
+		},
+		{
+			name: "recently disconnected replica is not yet unhealthy",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("old", time.Hour, "v2"),
+				newTestReplica("young", time.Minute*30, "v2"),
+			},
+			readyReplicas:    1,
+			wantUnhealthy:    []string{},
+			wantDisconnected: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			lbs := newTestLoadBalancerScope(int32(len(tc.vmList)), "v2") //nolint:gosec // G115
+			lbs.SetReadyReplicas(tc.readyReplicas)
+			if tc.disconnectedFor > 0 {
+				conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+					Type:               infrav1.LoadBalancerReplicasServiceConnectedCondition,
+					Status:             metav1.ConditionFalse,
+					Reason:             infrav1.LoadBalancerReplicasDisconnectedReason,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-tc.disconnectedFor)),
+				})
+			}
+			r := &AzureStackHCILoadBalancerReconciler{}
+
+			unhealthy := r.getUnhealthyVirtualMachines(lbs, tc.vmList)
+
+			names := []string{}
+			for _, vm := range unhealthy {
+				names = append(names, vm.Name)
+			}
+			g.Expect(names).To(Equal(tc.wantUnhealthy))
+			g.Expect(conditions.IsFalse(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerReplicasServiceConnectedCondition)).To(Equal(tc.wantDisconnected))
+		})
+	}
+}
+
+// TestReconcileReplicaHealth verifies that unhealthy replicas are only deleted while the health check is enabled
+// and the number of unhealthy replicas is within the MaxUnhealthy budget.
+func TestReconcileReplicaHealth(t *testing.T) {
+	failed := func(name string) *infrav1.AzureStackHCIVirtualMachine {
+		vm := newTestReplica(name, time.Hour, "v2")
+		vm.Status.VMState = ptr.To(infrav1.VMStateFailed)
+		return vm
+	}
+
+	tests := []struct {
+		name           string
+		healthCheck    *infrav1.LoadBalancerHealthCheck
+		vmList         []*infrav1.AzureStackHCIVirtualMachine
+		wantRemediated bool
+		wantReason     string
+		wantRemaining  int
+	}{
+		{
+			name:          "all healthy",
+			healthCheck:   &infrav1.LoadBalancerHealthCheck{},
+			vmList:        []*infrav1.AzureStackHCIVirtualMachine{newTestReplica("a", time.Hour, "v2")},
+			wantReason:    "AllReplicasHealthy",
+			wantRemaining: 1,
+		},
+		{
+			name:          "health check disabled",
+			vmList:        []*infrav1.AzureStackHCIVirtualMachine{failed("a"), newTestReplica("b", time.Hour, "v2")},
+			wantRemaining: 2,
+		},
+		{
+			name:          "budget exceeded",
+			healthCheck:   &infrav1.LoadBalancerHealthCheck{MaxUnhealthy: ptr.To(intstr.FromInt32(1))},
+			vmList:        []*infrav1.AzureStackHCIVirtualMachine{failed("a"), failed("b"), newTestReplica("c", time.Hour, "v2")},
+			wantReason:    infrav1.LoadBalancerRemediationNotAllowedReason,
+			wantRemaining: 3,
+		},
+		{
+			name:           "remediate within budget",
+			healthCheck:    &infrav1.LoadBalancerHealthCheck{MaxUnhealthy: ptr.To(intstr.FromInt32(1))},
+			vmList:         []*infrav1.AzureStackHCIVirtualMachine{failed("a"), newTestReplica("b", time.Hour, "v2")},
+			wantRemediated: true,
+			wantReason:     infrav1.LoadBalancerRemediatingReplicasReason,
+			wantRemaining:  1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r, clusterScope := newTestLoadBalancerReconciler(g, tc.vmList)
+
+			replicas := int32(len(tc.vmList)) //nolint:gosec // G115
+			lbs := newTestLoadBalancerScope(replicas, "v2")
+			lbs.AzureStackHCILoadBalancer.Spec.HealthCheck = tc.healthCheck
+			lbs.SetReplicas(replicas)
+			lbs.SetReadyReplicas(replicas)
+
+			remediated, err := r.reconcileReplicaHealth(lbs, clusterScope, tc.vmList)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(remediated).To(Equal(tc.wantRemediated))
+			g.Expect(conditions.GetReason(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerReplicasHealthyCondition)).To(Equal(tc.wantReason))
+
+			remaining := &infrav1.AzureStackHCIVirtualMachineList{}
+			g.Expect(r.Client.List(context.Background(), remaining)).To(Succeed())
+			g.Expect(remaining.Items).To(HaveLen(tc.wantRemaining))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/loadbalancers"
+	"github.com/microsoft/moc-sdk-for-go/services/network"
+	"github.com/pkg/errors"
+)
+
+// reconcileListeners creates a loadbalancer service for every listener of the spec and deletes the
+// services of listeners that were removed. Listener services forward to the backend pool all nodes join.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileListeners(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope) error {
+	lb := lbs.AzureStackHCILoadBalancer
+	lbSvc := loadbalancers.NewService(clusterScope)
+
+	previous := map[string]string{}
+	for _, listener := range lb.Status.Listeners {
+		previous[listener.Name] = listener.Address
+	}
+
+	statuses := []infrav1.LoadBalancerListenerStatus{}
+	for _, listener := range lb.Spec.Listeners {
+		name := azurestackhci.GenerateAzureStackHCILoadBalancerListenerName(lbs.Name(), listener.Name)
+		frontendAddress, err := r.reconcileFrontendAddress(lbs, clusterScope, name, previous[listener.Name], listener.StaticVIP)
+		if err != nil {
+			return errors.Wrapf(err, "failed to reserve a frontend address for listener %s", listener.Name)
+		}
+
+		lbSpec := &loadbalancers.Spec{
+			Name:              name,
+			BackendPoolName:   azurestackhci.GenerateBackendPoolName(clusterScope.Name()),
+			VnetName:          clusterScope.AzureStackHCICluster.Spec.NetworkSpec.Vnet.Name,
+			FrontendIPAddress: frontendAddress,
+		}
+		lbSpec.Rules, lbSpec.Probes = convertLoadBalancingRules(listener.Rules, listener.Probes)
+		if err := lbSvc.Reconcile(clusterScope.Context, lbSpec); err != nil {
+			return errors.Wrapf(err, "failed to reconcile loadbalancer %s for listener %s", name, listener.Name)
+		}
+
+		status := infrav1.LoadBalancerListenerStatus{
+			Name:    listener.Name,
+			Address: previous[listener.Name],
+		}
+		if status.Address == "" {
+			if lbInterface, err := lbSvc.Get(clusterScope.Context, &loadbalancers.Spec{Name: name}); err == nil {
+				if mocLB, ok := lbInterface.(network.LoadBalancer); ok {
+					status.Address = loadbalancers.FrontendIPAddress(mocLB)
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, listenerName := range removedListeners(lb) {
+		name := azurestackhci.GenerateAzureStackHCILoadBalancerListenerName(lbs.Name(), listenerName)
+		if err := lbSvc.Delete(clusterScope.Context, &loadbalancers.Spec{Name: name}); err != nil {
+			return errors.Wrapf(err, "failed to delete loadbalancer %s for listener %s", name, listenerName)
+		}
+		lbs.Info("Deleted loadbalancer for removed listener", "listener", listenerName)
+	}
+
+	lb.Status.Listeners = statuses
+	return nil
+}
+
+// reconcileDeleteListeners deletes the loadbalancer services of all listeners.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileDeleteListeners(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope) error {
+	lb := lbs.AzureStackHCILoadBalancer
+	lbSvc := loadbalancers.NewService(clusterScope)
+
+	listenerNames := map[string]struct{}{}
+	for _, listener := range lb.Spec.Listeners {
+		listenerNames[listener.Name] = struct{}{}
+	}
+	for _, listener := range lb.Status.Listeners {
+		listenerNames[listener.Name] = struct{}{}
+	}
+
+	for listenerName := range listenerNames {
+		name := azurestackhci.GenerateAzureStackHCILoadBalancerListenerName(lbs.Name(), listenerName)
+		if err := lbSvc.Delete(clusterScope.Context, &loadbalancers.Spec{Name: name}); err != nil {
+			return errors.Wrapf(err, "failed to delete loadbalancer %s for listener %s", name, listenerName)
+		}
+	}
+
+	lb.Status.Listeners = nil
+	return nil
+}
+
+// removedListeners returns the names of the listeners in the status that were removed from the spec.
+func removedListeners(lb *infrav1.AzureStackHCILoadBalancer) []string {
+	removed := []string{}
+	for _, status := range lb.Status.Listeners {
+		found := false
+		for _, listener := range lb.Spec.Listeners {
+			if listener.Name == status.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, status.Name)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/microsoft/moc-sdk-for-go/services/network"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/loadbalancers"
+)
+
+// TestRemovedListeners verifies that only the listeners of the status that are no longer in the spec are deleted.
+func TestRemovedListeners(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     []string
+		statuses []string
+		want     []string
+	}{
+		{name: "no listeners", want: []string{}},
+		{name: "new listener", spec: []string{"ingress"}, want: []string{}},
+		{name: "unchanged", spec: []string{"ingress", "dns"}, statuses: []string{"dns", "ingress"}, want: []string{}},
+		{name: "listener removed", spec: []string{"ingress"}, statuses: []string{"ingress", "dns"}, want: []string{"dns"}},
+		{name: "all removed", statuses: []string{"ingress", "dns"}, want: []string{"ingress", "dns"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			lb := &infrav1.AzureStackHCILoadBalancer{}
+			for _, name := range tc.spec {
+				lb.Spec.Listeners = append(lb.Spec.Listeners, infrav1.LoadBalancerListener{Name: name})
+			}
+			for _, name := range tc.statuses {
+				lb.Status.Listeners = append(lb.Status.Listeners, infrav1.LoadBalancerListenerStatus{Name: name})
+			}
+			g.Expect(removedListeners(lb)).To(Equal(tc.want))
+		})
+	}
+}
+
+// TestConvertLoadBalancingRules verifies that the rules of a listener default to tcp and that the probe
+// defaults are applied, without adding the api server rule.
+func TestConvertLoadBalancingRules(t *testing.T) {
+	g := NewWithT(t)
+
+	rules, probes := convertLoadBalancingRules(
+		[]infrav1.LoadBalancingRule{
+			{Name: "https", FrontendPort: 443, BackendPort: 30443, ProbeName: "https", EnableFloatingIP: true},
+			{Name: "dns", Protocol: infrav1.LoadBalancerProtocolUDP, FrontendPort: 53, BackendPort: 30053, IdleTimeoutInMinutes: ptr.To[int32](4)},
+		},
+		[]infrav1.LoadBalancerProbe{
+			{Name: "https", Protocol: infrav1.LoadBalancerProbeProtocolHTTPS, Port: 30443, RequestPath: "/healthz", NumberOfProbes: ptr.To[int32](3)},
+		},
+	)
+	g.Expect(rules).To(Equal([]loadbalancers.Rule{
+		{Name: "https", Protocol: network.TransportProtocolTCP, FrontendPort: 443, BackendPort: 30443, ProbeName: "https", EnableFloatingIP: true},
+		{Name: "dns", Protocol: network.TransportProtocolUDP, FrontendPort: 53, BackendPort: 30053, IdleTimeoutInMinutes: ptr.To[int32](4)},
+	}))
+	g.Expect(probes).To(Equal([]loadbalancers.Probe{
+		{
+			Name:              "https",
+			Protocol:          network.ProbeProtocolHTTPS,
+			Port:              30443,
+			RequestPath:       "/healthz",
+			IntervalInSeconds: azurestackhci.DefaultProbeIntervalInSeconds,
+			NumberOfProbes:    3,
+		},
+	}))
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// updateMaintenanceStatus reports the replicas drained for host maintenance. The replicas are replaced by the
+// rolling update: a replacement is created first, once it is connected the replica is taken out of the loadbalancer
+// service and it is removed after its connections drained.
+func (r *AzureStackHCILoadBalancerReconciler) updateMaintenanceStatus(lbs *scope.LoadBalancerScope, vmList []*infrav1.AzureStackHCIVirtualMachine) {
+	draining := []string{}
+	for _, vm := range vmList {
+		if isInMaintenance(vm) {
+			draining = append(draining, vm.Name)
+		}
+	}
+
+	if len(draining) == 0 {
+		conditions.Delete(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerMaintenanceCondition)
+		return
+	}
+
+	message := fmt.Sprintf("draining replicas %s", strings.Join(draining, ", "))
+	if conditions.GetMessage(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerMaintenanceCondition) != message {
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "DrainingReplicas",
+			"Draining replicas %s of AzureStackHCILoadBalancer %s for maintenance", strings.Join(draining, ", "), lbs.Name())
+	}
+	conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+		Type:    infrav1.LoadBalancerMaintenanceCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrav1.LoadBalancerDrainingReplicasReason,
+		Message: message,
+	})
+}
+
+// drainVirtualMachine takes a replica out of the loadbalancer service. MOC does not track the replicas serving a
+// loadbalancer, a replica leaves the service when its guest shuts down, which lets it finish the open connections.
+func (r *AzureStackHCILoadBalancerReconciler) drainVirtualMachine(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, vm *infrav1.AzureStackHCIVirtualMachine) error {
+	if isDraining(vm) {
+		return nil
+	}
+
+	annotations := vm.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[infrav1.AnnotationLoadBalancerDrainStarted] = time.Now().UTC().Format(time.RFC3339)
+	annotations[infrav1.AnnotationVMPowerOperation] = string(infrav1.VMPowerOperationShutdown)
+	vm.SetAnnotations(annotations)
+	if err := r.Client.Update(clusterScope.Context, vm); err != nil {
+		return errors.Wrapf(err, "failed to take AzureStackHCIVirtualMachine %s out of service", vm.Name)
+	}
+
+	lbs.Info("Taking replica out of the loadbalancer service", "vmName", vm.Name, "drainTimeout", scope.DefaultDrainTimeout)
+	r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "DrainingReplica",
+		"Took replica %s of AzureStackHCILoadBalancer %s out of service, it is removed once its connections drained", vm.Name, lbs.Name())
+	return nil
+}
+
+// reconcileDrainingReplicas removes the replicas taken out of the loadbalancer service once their guest shut down or
+// the drain timeout expired. Returns true while replicas are draining, other changes to the replicas wait for them.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileDrainingReplicas(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, vmList []*infrav1.AzureStackHCIVirtualMachine) (reconcile.Result, bool, error) {
+	draining := false
+	requeueAfter := scope.DefaultDrainTimeout
+	for _, vm := range vmList {
+		if !isDraining(vm) {
+			continue
+		}
+
+		remaining := drainTimeRemaining(vm)
+		if remaining > 0 && vm.Status.PowerState != infrav1.VMPowerStateStopped {
+			draining = true
+			requeueAfter = min(requeueAfter, remaining)
+			continue
+		}
+
+		lbs.Info("Removing drained replica", "vmName", vm.Name)
+		if err := r.deleteVirtualMachine(lbs, clusterScope, vm); err != nil {
+			return reconcile.Result{}, false, errors.Wrapf(err, "failed to remove drained loadbalancer VM %s", vm.Name)
+		}
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "RemovedDrainedReplica",
+			"Removed drained replica %s of AzureStackHCILoadBalancer %s", vm.Name, lbs.Name())
+		lbs.RemoveReplica()
+		draining = true
+		requeueAfter = min(requeueAfter, 20*time.Second)
+	}
+	if !draining {
+		return reconcile.Result{}, false, nil
+	}
+	// the power state of the replica is refreshed by the virtual machine controller
+	return reconcile.Result{RequeueAfter: min(requeueAfter, time.Minute)}, true, nil
+}
+
+// drainTimeRemaining returns how long a replica taken out of service is kept before it is removed. A drain start
+// that can't be parsed counts as expired.
+func drainTimeRemaining(vm *infrav1.AzureStackHCIVirtualMachine) time.Duration {
+	started, err := time.Parse(time.RFC3339, vm.GetAnnotations()[infrav1.AnnotationLoadBalancerDrainStarted])
+	if err != nil {
+		return 0
+	}
+	return scope.DefaultDrainTimeout - time.Since(started)
+}
+
+// isDraining returns true if the replica was taken out of the loadbalancer service.
+func isDraining(vm *infrav1.AzureStackHCIVirtualMachine) bool {
+	_, ok := vm.GetAnnotations()[infrav1.AnnotationLoadBalancerDrainStarted]
+	return ok
+}
+
+// isDrainRequired determines if there are any replicas which need to be drained for maintenance
+func (r *AzureStackHCILoadBalancerReconciler) isDrainRequired(vmList []*infrav1.AzureStackHCIVirtualMachine) bool {
+	for _, vm := range vmList {
+		if isInMaintenance(vm) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInMaintenance returns true if the replica is marked to be drained before its host is taken down.
+func isInMaintenance(vm *infrav1.AzureStackHCIVirtualMachine) bool {
+	_, ok := vm.GetAnnotations()[infrav1.AnnotationLoadBalancerMaintenance]
+	return ok
+}
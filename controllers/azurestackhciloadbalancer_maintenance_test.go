@@ -0,0 +1,169 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+)
+
+func inMaintenance(vm *infrav1.AzureStackHCIVirtualMachine) *infrav1.AzureStackHCIVirtualMachine {
+	vm.Annotations = map[string]string{infrav1.AnnotationLoadBalancerMaintenance: ""}
+	return vm
+}
+
+func TestUpdateMaintenanceStatus(t *testing.T) {
+	g := NewWithT(t)
+	lbs := newTestLoadBalancerScope(1, "v2")
+	recorder := record.NewFakeRecorder(10)
+	r := &AzureStackHCILoadBalancerReconciler{Recorder: recorder}
+
+	vmList := []*infrav1.AzureStackHCIVirtualMachine{
+		inMaintenance(newTestReplica("a", time.Hour, "v2")),
+		newTestReplica("b", time.Hour, "v2"),
+	}
+	g.Expect(r.isDrainRequired(vmList)).To(BeTrue())
+	r.updateMaintenanceStatus(lbs, vmList)
+	r.updateMaintenanceStatus(lbs, vmList)
+	g.Expect(conditions.GetReason(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerMaintenanceCondition)).To(Equal(infrav1.LoadBalancerDrainingReplicasReason))
+	g.Expect(conditions.GetMessage(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerMaintenanceCondition)).To(Equal("draining replicas a"))
+	g.Expect(recorder.Events).To(HaveLen(1))
+
+	vmList = vmList[1:]
+	g.Expect(r.isDrainRequired(vmList)).To(BeFalse())
+	r.updateMaintenanceStatus(lbs, vmList)
+	g.Expect(conditions.Has(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerMaintenanceCondition)).To(BeFalse())
+}
+
+// TestRollingUpdateDrainsMaintenanceReplica verifies that a replica in maintenance is taken out of the loadbalancer
+// service instead of being deleted once its replacement is connected.
+func TestRollingUpdateDrainsMaintenanceReplica(t *testing.T) {
+	g := NewWithT(t)
+	vmList := []*infrav1.AzureStackHCIVirtualMachine{
+		inMaintenance(newTestReplica("maintenance", time.Hour, "v2")),
+		newTestReplica("replacement", time.Minute, "v2"),
+	}
+	r, clusterScope := newTestLoadBalancerReconciler(g, vmList)
+	lbs := newTestLoadBalancerScope(1, "v2")
+	lbs.SetReplicas(2)
+	lbs.SetReadyReplicas(2)
+
+	_, err := r.rollingUpdateVirtualMachines(lbs, clusterScope, vmList)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lbs.GetReplicas()).To(Equal(int32(2)))
+
+	stored := &infrav1.AzureStackHCIVirtualMachine{}
+	g.Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(vmList[0]), stored)).To(Succeed())
+	g.Expect(stored.Annotations).To(HaveKey(infrav1.AnnotationLoadBalancerDrainStarted))
+	g.Expect(stored.Annotations).To(HaveKeyWithValue(infrav1.AnnotationVMPowerOperation, string(infrav1.VMPowerOperationShutdown)))
+	g.Expect(isDraining(stored)).To(BeTrue())
+}
+
+func TestReconcileDrainingReplicas(t *testing.T) {
+	draining := func(name string, since time.Duration, powerState infrav1.VMPowerState) *infrav1.AzureStackHCIVirtualMachine {
+		vm := inMaintenance(newTestReplica(name, time.Hour, "v2"))
+		vm.Annotations[infrav1.AnnotationLoadBalancerDrainStarted] = time.Now().Add(-since).UTC().Format(time.RFC3339)
+		vm.Status.PowerState = powerState
+		return vm
+	}
+
+	tests := []struct {
+		name          string
+		vmList        []*infrav1.AzureStackHCIVirtualMachine
+		wantDraining  bool
+		wantRemaining int
+	}{
+		{
+			name:          "no replica is draining",
+			vmList:        []*infrav1.AzureStackHCIVirtualMachine{inMaintenance(newTestReplica("a", time.Hour, "v2"))},
+			wantRemaining: 1,
+		},
+		{
+			name:          "connections are draining",
+			vmList:        []*infrav1.AzureStackHCIVirtualMachine{draining("a", time.Minute, infrav1.VMPowerStateRunning)},
+			wantDraining:  true,
+			wantRemaining: 1,
+		},
+		{
+			name:         "guest shut down",
+			vmList:       []*infrav1.AzureStackHCIVirtualMachine{draining("a", time.Minute, infrav1.VMPowerStateStopped)},
+			wantDraining: true,
+		},
+		{
+			name:         "drain timeout expired",
+			vmList:       []*infrav1.AzureStackHCIVirtualMachine{draining("a", scope.DefaultDrainTimeout+time.Minute, infrav1.VMPowerStateRunning)},
+			wantDraining: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r, clusterScope := newTestLoadBalancerReconciler(g, tc.vmList)
+			lbs := newTestLoadBalancerScope(1, "v2")
+			lbs.SetReplicas(int32(len(tc.vmList))) //nolint:gosec // G115
+
+			result, isDraining, err := r.reconcileDrainingReplicas(lbs, clusterScope, tc.vmList)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(isDraining).To(Equal(tc.wantDraining))
+			if tc.wantDraining {
+				g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				g.Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+			}
+
+			remaining := &infrav1.AzureStackHCIVirtualMachineList{}
+			g.Expect(r.Client.List(context.Background(), remaining)).To(Succeed())
+			g.Expect(remaining.Items).To(HaveLen(tc.wantRemaining))
+			g.Expect(lbs.GetReplicas()).To(Equal(int32(tc.wantRemaining))) //nolint:gosec // G115
+		})
+	}
+}
+
+// TestClusterToPausedObjects verifies that pause transitions of a Cluster enqueue its loadbalancer and the
+// virtual machines of the Cluster only.
+func TestClusterToPausedObjects(t *testing.T) {
+	g := NewWithT(t)
+	other := newTestReplica("other", time.Hour, "v2")
+	other.Spec.ClusterName = "other"
+	replica := newTestReplica("replica", time.Hour, "v2")
+	replica.Spec.ClusterName = "cluster"
+	lbr, clusterScope := newTestLoadBalancerReconciler(g, []*infrav1.AzureStackHCIVirtualMachine{replica, other})
+	vmr := &AzureStackHCIVirtualMachineReconciler{Client: lbr.Client, Log: logr.Discard()}
+
+	requests := lbr.ClusterToAzureStackHCILoadBalancer(context.Background(), clusterScope.Cluster)
+	g.Expect(requests).To(HaveLen(1))
+	g.Expect(requests[0].Name).To(Equal(azurestackhci.GenerateAzureStackHCILoadBalancerName("cluster")))
+	g.Expect(requests[0].Namespace).To(Equal("default"))
+
+	requests = vmr.ClusterToAzureStackHCIVirtualMachines(context.Background(), clusterScope.Cluster)
+	g.Expect(requests).To(HaveLen(1))
+	g.Expect(requests[0].Name).To(Equal("replica"))
+
+	g.Expect(vmr.ClusterToAzureStackHCIVirtualMachines(context.Background(), replica)).To(BeNil())
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/availabilitysets"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcilePlacement creates the availability set of the replicas when the placement is managed by the provider.
+func (r *AzureStackHCILoadBalancerReconciler) reconcilePlacement(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope) error {
+	placement := lbs.AzureStackHCILoadBalancer.Spec.Placement
+	if !placement.IsProviderManaged() {
+		return nil
+	}
+
+	avsetSpec := &availabilitysets.Spec{
+		Name:                     azurestackhci.GenerateAzureStackHCILoadBalancerAvailabilitySetName(lbs.Name()),
+		PlatformFaultDomainCount: azurestackhci.DefaultPlatformFaultDomainCount,
+	}
+	if placement.PlatformFaultDomainCount != nil {
+		avsetSpec.PlatformFaultDomainCount = *placement.PlatformFaultDomainCount
+	}
+	if err := availabilitysets.NewService(clusterScope).Reconcile(clusterScope.Context, avsetSpec); err != nil {
+		return errors.Wrapf(err, "failed to reconcile availability set %s", avsetSpec.Name)
+	}
+	return nil
+}
+
+// reconcileDeletePlacement deletes the availability set created by the provider once all replicas are gone.
+// Returns false while replicas are still being deleted.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileDeletePlacement(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope) (bool, error) {
+	vmList := &infrav1.AzureStackHCIVirtualMachineList{}
+	if err := r.Client.List(
+		clusterScope.Context,
+		vmList,
+		client.InNamespace(clusterScope.Namespace()),
+		client.MatchingLabels{infrav1.LoadBalancerLabel: lbs.Name()}); err != nil {
+		return false, errors.Wrapf(err, "failed to list loadbalancer virtual machines")
+	}
+	if len(vmList.Items) > 0 {
+		lbs.Info("Waiting for replicas to be deleted before deleting the availability set", "replicas", len(vmList.Items))
+		return false, nil
+	}
+
+	// the availability set is deleted even if the placement was changed after it was created
+	avsetSpec := &availabilitysets.Spec{
+		Name: azurestackhci.GenerateAzureStackHCILoadBalancerAvailabilitySetName(lbs.Name()),
+	}
+	if err := availabilitysets.NewService(clusterScope).Delete(clusterScope.Context, avsetSpec); err != nil {
+		return false, errors.Wrapf(err, "failed to delete availability set %s", avsetSpec.Name)
+	}
+	return true, nil
+}
+
+// placementNames returns the availability set and placement group the replicas join.
+func placementNames(lbs *scope.LoadBalancerScope) (availabilitySetName, placementGroupName string) {
+	placement := lbs.AzureStackHCILoadBalancer.Spec.Placement
+	switch {
+	case placement == nil:
+		return "", ""
+	case placement.IsProviderManaged():
+		return azurestackhci.GenerateAzureStackHCILoadBalancerAvailabilitySetName(lbs.Name()), ""
+	default:
+		return placement.AvailabilitySetName, placement.PlacementGroupName
+	}
+}
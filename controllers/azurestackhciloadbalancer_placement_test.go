@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+)
+
+func TestPlacementNames(t *testing.T) {
+	tests := []struct {
+		name                    string
+		placement               *infrav1.LoadBalancerPlacement
+		wantProviderManaged     bool
+		wantAvailabilitySetName string
+		wantPlacementGroupName  string
+	}{
+		{
+			name: "no placement",
+		},
+		{
+			name:                    "provider managed availability set",
+			placement:               &infrav1.LoadBalancerPlacement{PlatformFaultDomainCount: ptr.To[int32](3)},
+			wantProviderManaged:     true,
+			wantAvailabilitySetName: azurestackhci.GenerateAzureStackHCILoadBalancerAvailabilitySetName("lb"),
+		},
+		{
+			name:                    "existing availability set",
+			placement:               &infrav1.LoadBalancerPlacement{AvailabilitySetName: "avset"},
+			wantAvailabilitySetName: "avset",
+		},
+		{
+			name:                   "existing placement group",
+			placement:              &infrav1.LoadBalancerPlacement{PlacementGroupName: "group"},
+			wantPlacementGroupName: "group",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			lbs := newTestLoadBalancerScope(1, "v2")
+			lbs.AzureStackHCILoadBalancer.Spec.Placement = tc.placement
+
+			availabilitySetName, placementGroupName := placementNames(lbs)
+			g.Expect(tc.placement.IsProviderManaged()).To(Equal(tc.wantProviderManaged))
+			g.Expect(availabilitySetName).To(Equal(tc.wantAvailabilitySetName))
+			g.Expect(placementGroupName).To(Equal(tc.wantPlacementGroupName))
+		})
+	}
+}
+
+// TestReconcileDeletePlacementWaitsForReplicas verifies that the availability set is kept while replicas exist.
+func TestReconcileDeletePlacementWaitsForReplicas(t *testing.T) {
+	g := NewWithT(t)
+	r, clusterScope := newTestLoadBalancerReconciler(g, []*infrav1.AzureStackHCIVirtualMachine{newTestReplica("a", time.Hour, "v2")})
+
+	deleted, err := r.reconcileDeletePlacement(newTestLoadBalancerScope(1, "v2"), clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeFalse())
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/galleryimages"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileImage checks that the image of new replicas exists in the gallery before they are created.
+// Returns false while the image is not found.
+func (r *AzureStackHCILoadBalancerReconciler) reconcileImage(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope) (bool, error) {
+	image, err := r.getVMImage(lbs)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get AzureStackHCILoadBalancer image")
+	}
+
+	imageName := to.String(image.Name)
+	imageSpec := &galleryimages.Spec{
+		Name:     imageName,
+		Location: clusterScope.Location(),
+	}
+	if _, err := galleryimages.NewService(clusterScope).Get(clusterScope.Context, imageSpec); err != nil {
+		if !azurestackhci.ResourceNotFound(err) {
+			return false, errors.Wrapf(err, "failed to get gallery image %s", imageName)
+		}
+		if !conditions.IsFalse(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerImageAvailableCondition) {
+			r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "LoadBalancerImageNotFound",
+				"Gallery image %s of AzureStackHCILoadBalancer %s was not found, no replicas are created", imageName, lbs.Name())
+		}
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerImageAvailableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.LoadBalancerImageNotFoundReason,
+			Message: fmt.Sprintf("gallery image %s was not found", imageName),
+		})
+		return false, nil
+	}
+
+	conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+		Type:   infrav1.LoadBalancerImageAvailableCondition,
+		Status: metav1.ConditionTrue,
+		Reason: "ImageFound",
+	})
+	return true, nil
+}
+
+// checkUpgradeProgress rolls the update back when updated replicas are not ready once the update ran for longer
+// than the progress deadline. Replicas are then created from the previous image until a different version is requested.
+func (r *AzureStackHCILoadBalancerReconciler) checkUpgradeProgress(lbs *scope.LoadBalancerScope, vmList []*infrav1.AzureStackHCIVirtualMachine) {
+	upgrade := lbs.AzureStackHCILoadBalancer.Status.Upgrade
+	if upgrade.StartTime == nil || upgrade.PreviousImage == nil || time.Since(upgrade.StartTime.Time) < lbs.GetProgressDeadline() {
+		return
+	}
+
+	updatedReady := true
+	for _, vm := range vmList {
+		if vm.Labels[infrav1.OSVersionLabelName] == upgrade.OSVersion && !isReplicaReady(vm) {
+			updatedReady = false
+		}
+	}
+	// MOC only reports how many replicas are connected, disconnected replicas are attributed to the update
+	if updatedReady && lbs.GetReadyReplicas() >= lbs.GetReplicas() {
+		return
+	}
+
+	upgrade.FailedOSVersion = upgrade.OSVersion
+	r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeWarning, "LoadBalancerUpgradeRolledBack",
+		"Replicas of AzureStackHCILoadBalancer %s running os version %s were not ready within %s, rolling back to os version %s",
+		lbs.Name(), upgrade.FailedOSVersion, lbs.GetProgressDeadline(), upgrade.PreviousOSVersion)
+}
+
+// removeFailedUpgradeReplicas deletes the replicas of a rolled back update which are not ready, or not connected
+// to the loadbalancer service. Ready replicas are replaced by the rolling update. Returns true if replicas were removed.
+func (r *AzureStackHCILoadBalancerReconciler) removeFailedUpgradeReplicas(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, vmList []*infrav1.AzureStackHCIVirtualMachine) (bool, error) {
+	failedOSVersion := lbs.AzureStackHCILoadBalancer.Status.Upgrade.FailedOSVersion
+	failed := []*infrav1.AzureStackHCIVirtualMachine{}
+	running := []*infrav1.AzureStackHCIVirtualMachine{}
+	for _, vm := range vmList {
+		switch {
+		case vm.Labels[infrav1.OSVersionLabelName] != failedOSVersion:
+		case !isReplicaReady(vm):
+			failed = append(failed, vm)
+		default:
+			running = append(running, vm)
+		}
+	}
+
+	// the most recently created replicas are the ones considered disconnected
+	// Assume that overflow will not happen for G115
+	if disconnected := min(lbs.GetReplicas()-lbs.GetReadyReplicas()-int32(len(failed)), int32(len(running))); disconnected > 0 { //nolint
+		sort.Sort(sort.Reverse(infrav1.VirtualMachinesByCreationTimestamp(running)))
+		failed = append(failed, running[:disconnected]...)
+	}
+
+	for _, vm := range failed {
+		lbs.Info("Removing replica of rolled back update", "vmName", vm.Name, "osVersion", failedOSVersion)
+		if err := r.deleteVirtualMachine(lbs, clusterScope, vm); err != nil {
+			return false, errors.Wrapf(err, "failed to remove loadbalancer VM %s", vm.Name)
+		}
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "RemovedReplicaForRollback",
+			"Removed replica %s of AzureStackHCILoadBalancer %s running os version %s", vm.Name, lbs.Name(), failedOSVersion)
+		lbs.RemoveReplica()
+	}
+	return len(failed) > 0, nil
+}
+
+// migrateOSVersionLabels labels the replicas created before the os version fell back to the Kubernetes version,
+// which carry an empty os version. Replicas running the image new replicas are created from are labeled with the
+// current os version, the others remain outdated and are replaced by the rolling update.
+func (r *AzureStackHCILoadBalancerReconciler) migrateOSVersionLabels(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, vmList []*infrav1.AzureStackHCIVirtualMachine) error {
+	osVersion := lbs.OSVersion()
+	if osVersion == "" {
+		return nil
+	}
+
+	var image *infrav1.Image
+	for _, vm := range vmList {
+		if vm.Labels[infrav1.OSVersionLabelName] != "" || vm.Spec.Image == nil {
+			continue
+		}
+		if image == nil {
+			var err error
+			if image, err = r.getVMImage(lbs); err != nil {
+				return errors.Wrap(err, "failed to get AzureStackHCILoadBalancer image")
+			}
+		}
+		if to.String(vm.Spec.Image.Name) != to.String(image.Name) {
+			continue
+		}
+
+		lbs.Info("Labeling replica with its os version", "vmName", vm.Name, "osVersion", osVersion)
+		base := vm.DeepCopy()
+		if vm.Labels == nil {
+			vm.Labels = map[string]string{}
+		}
+		vm.Labels[infrav1.OSVersionLabelName] = osVersion
+		if err := r.Client.Patch(clusterScope.Context, vm, client.MergeFrom(base)); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to label AzureStackHCIVirtualMachine %s", vm.Name)
+		}
+	}
+	return nil
+}
+
+// isReplicaReady returns true if the virtual machine of the replica is running.
+func isReplicaReady(vm *infrav1.AzureStackHCIVirtualMachine) bool {
+	if vm.Status.VMState != nil && *vm.Status.VMState == infrav1.VMStateFailed {
+		return false
+	}
+	return vm.Status.Ready && !conditions.IsFalse(vm, infrav1.VMRunningCondition)
+}
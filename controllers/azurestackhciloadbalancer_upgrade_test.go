@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+)
+
+// TestMigrateOSVersionLabels verifies that replicas labeled with an empty os version are labeled with the
+// Kubernetes version the loadbalancer falls back to when they run its default image, and stay outdated otherwise.
+func TestMigrateOSVersionLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	withImage := func(vm *infrav1.AzureStackHCIVirtualMachine, k8sVersion string) *infrav1.AzureStackHCIVirtualMachine {
+		image, err := azurestackhci.GetDefaultImage(infrav1.OSTypeLinux, k8sVersion)
+		g.Expect(err).NotTo(HaveOccurred())
+		vm.Spec.Image = image
+		return vm
+	}
+	vmList := []*infrav1.AzureStackHCIVirtualMachine{
+		withImage(newTestReplica("current", time.Hour, ""), "v1.30.0"),
+		withImage(newTestReplica("previous", time.Hour*2, ""), "v1.29.0"),
+		withImage(newTestReplica("labeled", time.Minute, "v1.30.0"), "v1.30.0"),
+	}
+	r, clusterScope := newTestLoadBalancerReconciler(g, vmList)
+	lbs := newTestLoadBalancerScope(3, "")
+	lbs.AzureStackHCILoadBalancer.Spec.Image = nil
+	lbs.AzureStackHCILoadBalancer.Spec.Version = ptr.To("v1.30.0")
+
+	g.Expect(r.migrateOSVersionLabels(lbs, clusterScope, vmList)).To(Succeed())
+
+	stored := &infrav1.AzureStackHCIVirtualMachineList{}
+	g.Expect(r.Client.List(context.Background(), stored)).To(Succeed())
+	labels := map[string]string{}
+	for _, vm := range stored.Items {
+		labels[vm.Name] = vm.Labels[infrav1.OSVersionLabelName]
+	}
+	g.Expect(labels).To(Equal(map[string]string{"current": "v1.30.0", "previous": "", "labeled": "v1.30.0"}))
+
+	r.updateUpgradeStatus(lbs, vmList)
+	g.Expect(lbs.AzureStackHCILoadBalancer.Status.Upgrade.UpdatedReplicas).To(Equal(int32(2)))
+	g.Expect(lbs.AzureStackHCILoadBalancer.Status.Upgrade.OutdatedReplicas).To(Equal(int32(1)))
+}
+
+// TestCheckUpgradeProgress verifies that an update is rolled back when updated replicas are not ready after the
+// progress deadline.
+func TestCheckUpgradeProgress(t *testing.T) {
+	notReady := func(vm *infrav1.AzureStackHCIVirtualMachine) *infrav1.AzureStackHCIVirtualMachine {
+		vm.Status.Ready = false
+		return vm
+	}
+
+	tests := []struct {
+		name          string
+		startedAgo    time.Duration
+		readyReplicas int32
+		vmList        []*infrav1.AzureStackHCIVirtualMachine
+		wantRollback  bool
+	}{
+		{
+			name:       "within the deadline",
+			startedAgo: time.Minute,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("old", time.Hour, "v1"),
+				notReady(newTestReplica("new", time.Minute, "v2")),
+			},
+			readyReplicas: 1,
+		},
+		{
+			name:       "updated replicas are ready",
+			startedAgo: time.Hour,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("old", time.Hour, "v1"),
+				newTestReplica("new", time.Minute, "v2"),
+			},
+			readyReplicas: 2,
+		},
+		{
+			name:       "updated replica is not ready",
+			startedAgo: time.Hour,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("old", time.Hour, "v1"),
+				notReady(newTestReplica("new", time.Minute, "v2")),
+			},
+			readyReplicas: 1,
+			wantRollback:  true,
+		},
+		{
+			name:       "updated replica is not connected",
+			startedAgo: time.Hour,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("old", time.Hour, "v1"),
+				newTestReplica("new", time.Minute, "v2"),
+			},
+			readyReplicas: 1,
+			wantRollback:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			lbs := newTestLoadBalancerScope(1, "v2")
+			lbs.SetReplicas(int32(len(tc.vmList))) //nolint:gosec // G115
+			lbs.SetReadyReplicas(tc.readyReplicas)
+			startTime := metav1.NewTime(time.Now().Add(-tc.startedAgo))
+			lbs.AzureStackHCILoadBalancer.Status.Upgrade = &infrav1.LoadBalancerUpgradeStatus{
+				OSVersion:         "v2",
+				StartTime:         &startTime,
+				PreviousOSVersion: "v1",
+				PreviousImage:     &infrav1.Image{Version: ptr.To("v1")},
+			}
+			r := &AzureStackHCILoadBalancerReconciler{Recorder: record.NewFakeRecorder(10)}
+
+			r.checkUpgradeProgress(lbs, tc.vmList)
+
+			g.Expect(lbs.IsUpgradeRolledBack()).To(Equal(tc.wantRollback))
+			if tc.wantRollback {
+				g.Expect(lbs.OSVersion()).To(Equal("v1"))
+				g.Expect(lbs.TargetOSVersion()).To(Equal("v2"))
+			}
+		})
+	}
+}
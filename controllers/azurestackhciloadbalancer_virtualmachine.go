@@ -22,7 +22,6 @@ import (
 	"sort"
 	"time"
 
-	"github.com/Azure/go-autorest/autorest/to"
 	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
 	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
@@ -45,6 +44,39 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileVirtualMachines(lbs *scop
 		return reconcile.Result{}, errors.Wrapf(err, "failed to get loadbalancer virtual machine list")
 	}
 
+	if err := r.migrateOSVersionLabels(lbs, clusterScope, loadBalancerVMs); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to migrate loadbalancer replica os version labels")
+	}
+
+	r.updateReplicaStatus(lbs, clusterScope, loadBalancerVMs)
+	r.updateUpgradeStatus(lbs, loadBalancerVMs)
+	r.updateMaintenanceStatus(lbs, loadBalancerVMs)
+
+	// replicas taken out of service are removed once their connections drained
+	if result, draining, err := r.reconcileDrainingReplicas(lbs, clusterScope, loadBalancerVMs); err != nil || draining {
+		return result, err
+	}
+
+	// replicas of a rolled back update never became ready, so they are removed without waiting for them
+	if lbs.IsUpgradeRolledBack() {
+		removed, err := r.removeFailedUpgradeReplicas(lbs, clusterScope, loadBalancerVMs)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to remove replicas of the rolled back update")
+		}
+		if removed {
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+		}
+	}
+
+	// replace unhealthy replicas, they are recreated by the scale up of a later reconciliation
+	remediated, err := r.reconcileReplicaHealth(lbs, clusterScope, loadBalancerVMs)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to remediate unhealthy loadbalancer replicas")
+	}
+	if remediated {
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+	}
+
 	for _, vm := range loadBalancerVMs {
 		if conditions.IsFalse(vm, infrav1.VMRunningCondition) {
 			cond := conditions.Get(vm, infrav1.VMRunningCondition)
@@ -60,11 +92,9 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileVirtualMachines(lbs *scop
 		}
 	}
 
-	r.updateReplicaStatus(lbs, clusterScope, loadBalancerVMs)
-
 	// before we handle any scaling or upgrade operations, we make sure that all existing replicas we have created are ready
 	if lbs.GetReadyReplicas() < lbs.GetReplicas() {
-		// continue waiting for upgrade/scaleup to complete, this is a best effort until health checks and remediation are available
+		// continue waiting for the new replica to be service connected before the upgrade/scaleup continues
 		if r.replicasAreUpgrading(lbs) || r.replicasAreScalingUp(lbs) {
 			return reconcile.Result{Requeue: true, RequeueAfter: time.Minute}, nil
 		}
@@ -81,6 +111,22 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileVirtualMachines(lbs *scop
 		}
 	}
 
+	// new replicas can only be created from an image that exists in the gallery
+	if r.isScaleUpRequired(lbs) || (lbs.GetDesiredReplicas() > 0 && (r.isUpgradeRequired(lbs, loadBalancerVMs) || r.isDrainRequired(loadBalancerVMs))) {
+		available, err := r.reconcileImage(lbs, clusterScope)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to check the loadbalancer image")
+		}
+		if !available {
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Minute}, nil
+		}
+	}
+
+	// check if we need to upgrade, replicas in maintenance are replaced the same way
+	if lbs.GetDesiredReplicas() > 0 && (r.isUpgradeRequired(lbs, loadBalancerVMs) || r.isDrainRequired(loadBalancerVMs)) {
+		return r.rollingUpdateVirtualMachines(lbs, clusterScope, loadBalancerVMs)
+	}
+
 	// check if we need to scale up
 	if r.isScaleUpRequired(lbs) {
 		if !r.replicasAreUpgrading(lbs) {
@@ -117,24 +163,6 @@ func (r *AzureStackHCILoadBalancerReconciler) reconcileVirtualMachines(lbs *scop
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
 	}
 
-	// check if we need to upgrade
-	if r.isUpgradeRequired(lbs, loadBalancerVMs) {
-		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
-			Type:   infrav1.LoadBalancerReplicasReadyCondition,
-			Status: metav1.ConditionFalse,
-			Reason: infrav1.LoadBalancerReplicasUpgradingReason,
-		})
-		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "UpgradingLoadBalancer", "Upgrading AzureStackHCILoadBalancer %s", lbs.Name())
-
-		for lbs.GetReplicas() < lbs.GetMaxReplicas() {
-			err = r.scaleUpVirtualMachines(lbs, clusterScope)
-			if err != nil {
-				return reconcile.Result{}, errors.Wrapf(err, "failed to scale up loadbalancer VM for upgrade")
-			}
-		}
-		return reconcile.Result{Requeue: true, RequeueAfter: time.Minute}, nil
-	}
-
 	// desired state was achieved
 	if conditions.IsFalse(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerReplicasReadyCondition) {
 		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
@@ -224,13 +252,21 @@ func (r *AzureStackHCILoadBalancerReconciler) createOrUpdateVirtualMachine(loadB
 		vm.Spec.VMSize = loadBalancerScope.AzureStackHCILoadBalancer.Spec.VMSize
 		vm.Spec.Location = clusterScope.Location()
 		vm.Spec.SSHPublicKey = loadBalancerScope.AzureStackHCILoadBalancer.Spec.SSHPublicKey
-		vm.Spec.StorageContainer = loadBalancerScope.AzureStackHCILoadBalancer.Spec.StorageContainer
+		storageContainer, err := storageContainerFor(clusterScope.Context, r.Client, clusterScope, vm, loadBalancerScope.AzureStackHCILoadBalancer.Spec.StorageContainer)
+		if err != nil {
+			return errors.Wrap(err, "failed to select storage container")
+		}
+		vm.Spec.StorageContainer = storageContainer
+		vm.Spec.AvailabilitySetName, vm.Spec.PlacementGroupName = placementNames(loadBalancerScope)
 
 		image, err := r.getVMImage(loadBalancerScope)
 		if err != nil {
 			return errors.Wrap(err, "failed to get AzureStackHCILoadBalancer image")
 		}
 		vm.Spec.Image = image.DeepCopy()
+		if len(vm.Spec.NetworkInterfaces) == 0 {
+			vm.Spec.NetworkInterfaces = clusterScope.DualStackNetworkInterfaces()
+		}
 		infrav1util.CopyCorrelationID(loadBalancerScope.AzureStackHCILoadBalancer, vm)
 
 		return nil
@@ -254,6 +290,54 @@ func (r *AzureStackHCILoadBalancerReconciler) createOrUpdateVirtualMachine(loadB
 	return vm, nil
 }
 
+// rollingUpdateVirtualMachines replaces outdated replicas one at a time. An outdated replica is only removed
+// when the remaining service connected replicas satisfy maxUnavailable, otherwise a new replica is created
+// within maxSurge. Each step waits for all replicas to be service connected before the next one starts.
+func (r *AzureStackHCILoadBalancerReconciler) rollingUpdateVirtualMachines(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, vmList []*infrav1.AzureStackHCIVirtualMachine) (reconcile.Result, error) {
+	if !r.replicasAreUpgrading(lbs) {
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "UpgradingLoadBalancer", "Upgrading AzureStackHCILoadBalancer %s", lbs.Name())
+	}
+	conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+		Type:   infrav1.LoadBalancerReplicasReadyCondition,
+		Status: metav1.ConditionFalse,
+		Reason: infrav1.LoadBalancerReplicasUpgradingReason,
+	})
+
+	if lbs.GetReadyReplicas()-1 >= lbs.GetDesiredReplicas()-lbs.GetMaxUnavailable() {
+		vm, reason, err := r.selectVirtualMachineForScaleDown(lbs, vmList)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// replicas in maintenance leave the loadbalancer service before they are removed
+		if isInMaintenance(vm) {
+			if err := r.drainVirtualMachine(lbs, clusterScope, vm); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+		}
+
+		lbs.Info("Removing replica for upgrade", "vmName", vm.Name, "reason", reason)
+		if err := r.deleteVirtualMachine(lbs, clusterScope, vm); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to remove loadbalancer VM %s", vm.Name)
+		}
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "RemovedReplicaForUpgrade", "Removed replica %s of AzureStackHCILoadBalancer %s: %s", vm.Name, lbs.Name(), reason)
+
+		lbs.RemoveReplica()
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+	}
+
+	if lbs.GetReplicas() < lbs.GetMaxReplicas() {
+		if err := r.scaleUpVirtualMachines(lbs, clusterScope); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to scale up loadbalancer VM for upgrade")
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Minute}, nil
+	}
+
+	lbs.Info("Waiting for replicas to be available before removing an outdated replica", "readyReplicas", lbs.GetReadyReplicas(), "maxUnavailable", lbs.GetMaxUnavailable())
+	return reconcile.Result{Requeue: true, RequeueAfter: time.Minute}, nil
+}
+
 // deleteVirtualMachine deletes a virtual machine
 func (r *AzureStackHCILoadBalancerReconciler) deleteVirtualMachine(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, vm *infrav1.AzureStackHCIVirtualMachine) error {
 	if vm.GetDeletionTimestamp().IsZero() {
@@ -297,12 +381,15 @@ func (r *AzureStackHCILoadBalancerReconciler) scaleUpVirtualMachines(lbs *scope.
 
 // scaleDownVirtualMachines scales down by deleting a virtual machine replica
 func (r *AzureStackHCILoadBalancerReconciler) scaleDownVirtualMachines(lbs *scope.LoadBalancerScope, clusterScope *scope.ClusterScope, vmList []*infrav1.AzureStackHCIVirtualMachine) error {
-	r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "ScalingDownLoadBalancer", "Scaling down AzureStackHCILoadBalancer %s", lbs.Name())
-
-	vm, err := r.selectVirtualMachineForScaleDown(lbs, vmList)
+	vm, reason, err := r.selectVirtualMachineForScaleDown(lbs, vmList)
 	if err != nil {
 		return err
 	}
+	// replicas in maintenance leave the loadbalancer service before they are removed
+	if isInMaintenance(vm) {
+		return r.drainVirtualMachine(lbs, clusterScope, vm)
+	}
+	r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "ScalingDownLoadBalancer", "Scaling down AzureStackHCILoadBalancer %s by removing replica %s: %s", lbs.Name(), vm.Name, reason)
 
 	err = r.deleteVirtualMachine(lbs, clusterScope, vm)
 	if err != nil {
@@ -313,26 +400,60 @@ func (r *AzureStackHCILoadBalancerReconciler) scaleDownVirtualMachines(lbs *scop
 	return nil
 }
 
-// selectVirtualMachineForScaleDown determines the next machine to be deleted when scaling down
-func (r *AzureStackHCILoadBalancerReconciler) selectVirtualMachineForScaleDown(lbs *scope.LoadBalancerScope, vmList []*infrav1.AzureStackHCIVirtualMachine) (*infrav1.AzureStackHCIVirtualMachine, error) {
+// selectVirtualMachineForScaleDown determines the next machine to be deleted when scaling down. Failed replicas
+// are preferred, then replicas in maintenance, then replicas running an outdated os version, then replicas sharing
+// the host MOC reports for them with another replica, and finally the oldest replica. All replicas are in the same
+// availability set, so only the host tells that they are co-located. The reason for the choice is returned for the
+// event.
+func (r *AzureStackHCILoadBalancerReconciler) selectVirtualMachineForScaleDown(lbs *scope.LoadBalancerScope, vmList []*infrav1.AzureStackHCIVirtualMachine) (*infrav1.AzureStackHCIVirtualMachine, string, error) {
 	if len(vmList) < 1 {
-		return nil, fmt.Errorf("no machines were provided for scale down selection")
+		return nil, "", fmt.Errorf("no machines were provided for scale down selection")
 	}
 
-	// find the oldest machine which is not running the latest os version
+	// every tier prefers the oldest machine
 	sort.Sort(infrav1.VirtualMachinesByCreationTimestamp(vmList))
+
+	for _, vm := range vmList {
+		if (vm.Status.VMState != nil && *vm.Status.VMState == infrav1.VMStateFailed) || conditions.IsFalse(vm, infrav1.VMRunningCondition) {
+			return vm, "replica is not running", nil
+		}
+	}
+
+	for _, vm := range vmList {
+		if isInMaintenance(vm) {
+			return vm, "replica is drained for maintenance", nil
+		}
+	}
+
 	for _, vm := range vmList {
 		if vm.Labels[infrav1.OSVersionLabelName] != lbs.OSVersion() {
-			return vm, nil
+			return vm, fmt.Sprintf("replica runs outdated os version %q", vm.Labels[infrav1.OSVersionLabelName]), nil
+		}
+	}
+
+	hosts := map[string]int{}
+	for _, vm := range vmList {
+		if vm.Status.Host != "" {
+			hosts[vm.Status.Host]++
+		}
+	}
+	for _, vm := range vmList {
+		if hosts[vm.Status.Host] > 1 {
+			return vm, fmt.Sprintf("replica shares host %s with another replica", vm.Status.Host), nil
 		}
 	}
 
-	// all machines are running the latest os version, so we just select the oldest machine
-	return vmList[0], nil
+	// all machines are running the latest os version on distinct hosts, so we just select the oldest machine
+	return vmList[0], "replica is the oldest", nil
 }
 
 // getVMImage returns the image to use for a virtual machine
 func (r *AzureStackHCILoadBalancerReconciler) getVMImage(loadBalancerScope *scope.LoadBalancerScope) (*infrav1.Image, error) {
+	// Use the image the replicas ran before a failed update
+	if loadBalancerScope.IsUpgradeRolledBack() {
+		return loadBalancerScope.AzureStackHCILoadBalancer.Status.Upgrade.PreviousImage, nil
+	}
+
 	// Use custom image if provided
 	if loadBalancerScope.AzureStackHCILoadBalancer.Spec.Image != nil &&
 		loadBalancerScope.AzureStackHCILoadBalancer.Spec.Image.Name != nil &&
@@ -345,7 +466,7 @@ func (r *AzureStackHCILoadBalancerReconciler) getVMImage(loadBalancerScope *scop
 	if loadBalancerScope.AzureStackHCILoadBalancer.Spec.Image != nil {
 		osType = loadBalancerScope.AzureStackHCILoadBalancer.Spec.Image.OSType
 	}
-	return azurestackhci.GetDefaultImage(osType, to.String(loadBalancerScope.AzureStackHCICluster.Spec.Version))
+	return azurestackhci.GetDefaultImage(osType, loadBalancerScope.KubernetesVersion())
 }
 
 // getVirtualMachinesForLoadBalancer returns a list of non-deleted AzureStackHCIVirtualMachines associated with the load balancer
@@ -385,6 +506,76 @@ func (r *AzureStackHCILoadBalancerReconciler) updateReplicaStatus(lbs *scope.Loa
 	lbs.Info("Updated replication status", "replicas", lbs.GetReplicas(), "readyReplicas", lbs.GetReadyReplicas(), "failedReplicas", lbs.GetFailedReplicas())
 }
 
+// updateUpgradeStatus updates the progress of the rolling update of the replicas
+func (r *AzureStackHCILoadBalancerReconciler) updateUpgradeStatus(lbs *scope.LoadBalancerScope, vmList []*infrav1.AzureStackHCIVirtualMachine) {
+	var updatedReplicas, outdatedReplicas int32
+	var previous *infrav1.AzureStackHCIVirtualMachine
+	sort.Sort(infrav1.VirtualMachinesByCreationTimestamp(vmList))
+	for _, vm := range vmList {
+		if vm.Labels[infrav1.OSVersionLabelName] == lbs.OSVersion() {
+			updatedReplicas++
+		} else {
+			outdatedReplicas++
+			if previous == nil {
+				previous = vm
+			}
+		}
+	}
+
+	if outdatedReplicas == 0 && lbs.AzureStackHCILoadBalancer.Status.Upgrade == nil {
+		// the replicas were never upgraded
+		return
+	}
+
+	upgrade := lbs.GetUpgradeStatus()
+	rolledBack := lbs.IsUpgradeRolledBack()
+	if outdatedReplicas > 0 && !rolledBack && (upgrade.OSVersion != lbs.OSVersion() || upgrade.CompletionTime != nil) {
+		now := metav1.Now()
+		upgrade.OSVersion = lbs.OSVersion()
+		upgrade.StartTime = &now
+		upgrade.CompletionTime = nil
+		upgrade.PreviousOSVersion = previous.Labels[infrav1.OSVersionLabelName]
+		upgrade.PreviousImage = previous.Spec.Image.DeepCopy()
+		upgrade.FailedOSVersion = ""
+	}
+	upgrade.UpdatedReplicas = updatedReplicas
+	upgrade.OutdatedReplicas = outdatedReplicas
+
+	if rolledBack {
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerReplicasUpToDateCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.LoadBalancerUpgradeRolledBackReason,
+			Message: fmt.Sprintf("replicas were rolled back from os version %s to %s", upgrade.FailedOSVersion, upgrade.PreviousOSVersion),
+		})
+		return
+	}
+
+	if outdatedReplicas > 0 {
+		conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+			Type:    infrav1.LoadBalancerReplicasUpToDateCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.LoadBalancerReplicasUpgradingReason,
+			Message: fmt.Sprintf("%d replicas do not run os version %s", outdatedReplicas, upgrade.OSVersion),
+		})
+		r.checkUpgradeProgress(lbs, vmList)
+		return
+	}
+
+	conditions.Set(lbs.AzureStackHCILoadBalancer, metav1.Condition{
+		Type:   infrav1.LoadBalancerReplicasUpToDateCondition,
+		Status: metav1.ConditionTrue,
+		Reason: "AllReplicasUpToDate",
+	})
+	if upgrade.CompletionTime == nil {
+		now := metav1.Now()
+		upgrade.OSVersion = lbs.OSVersion()
+		upgrade.CompletionTime = &now
+		upgrade.FailedOSVersion = ""
+		r.Recorder.Eventf(lbs.AzureStackHCILoadBalancer, corev1.EventTypeNormal, "LoadBalancerUpgraded", "All replicas of AzureStackHCILoadBalancer %s run os version %s", lbs.Name(), upgrade.OSVersion)
+	}
+}
+
 // getMachineReplicaCounts calculates the replica counts for the AzureStackHCIVirtualMachines associated with the load balancer
 func (r *AzureStackHCILoadBalancerReconciler) getMachineReplicaCounts(vmList []*infrav1.AzureStackHCIVirtualMachine) (replicas, failedReplicas int32) {
 	// Assume replicas will be under uniteger overflow for G115
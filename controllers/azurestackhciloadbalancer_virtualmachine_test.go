@@ -0,0 +1,321 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+)
+
+func newTestLoadBalancerScope(replicas int32, version string) *scope.LoadBalancerScope {
+	return &scope.LoadBalancerScope{
+		Logger: logr.Discard(),
+		AzureStackHCILoadBalancer: &infrav1.AzureStackHCILoadBalancer{
+			ObjectMeta: metav1.ObjectMeta{Name: "lb"},
+			Spec: infrav1.AzureStackHCILoadBalancerSpec{
+				Replicas: ptr.To(replicas),
+				Image:    &infrav1.Image{Version: ptr.To(version)},
+			},
+		},
+		AzureStackHCICluster: &infrav1.AzureStackHCICluster{},
+	}
+}
+
+func newTestReplica(name string, age time.Duration, version string) *infrav1.AzureStackHCIVirtualMachine {
+	return &infrav1.AzureStackHCIVirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			Labels:            map[string]string{infrav1.OSVersionLabelName: version, infrav1.LoadBalancerLabel: "lb"},
+		},
+		Spec:   infrav1.AzureStackHCIVirtualMachineSpec{Image: &infrav1.Image{Version: ptr.To(version)}},
+		Status: infrav1.AzureStackHCIVirtualMachineStatus{Ready: true},
+	}
+}
+
+// newTestLoadBalancerReconciler returns a reconciler whose client holds the replicas, and the scope of their cluster.
+func newTestLoadBalancerReconciler(g *WithT, vmList []*infrav1.AzureStackHCIVirtualMachine) (*AzureStackHCILoadBalancerReconciler, *scope.ClusterScope) {
+	scheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	objs := make([]client.Object, 0, len(vmList))
+	for _, vm := range vmList {
+		objs = append(objs, vm)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	clusterScope := &scope.ClusterScope{
+		Logger:               logr.Discard(),
+		Context:              context.Background(),
+		Cluster:              &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}},
+		AzureStackHCICluster: &infrav1.AzureStackHCICluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}},
+	}
+	return &AzureStackHCILoadBalancerReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}, clusterScope
+}
+
+func TestUpdateUpgradeStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		vmList        []*infrav1.AzureStackHCIVirtualMachine
+		upgrade       *infrav1.LoadBalancerUpgradeStatus
+		wantUpgrade   bool
+		wantUpdated   int32
+		wantOutdated  int32
+		wantPrevious  string
+		wantUpToDate  metav1.ConditionStatus
+		wantCompleted bool
+	}{
+		{
+			name:   "never upgraded",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{newTestReplica("a", time.Hour, "v2")},
+		},
+		{
+			name: "upgrade starts from the oldest outdated replica",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("new", time.Minute, "v2"),
+				newTestReplica("older", time.Hour, "v0"),
+				newTestReplica("old", time.Minute*30, "v1"),
+			},
+			wantUpgrade:  true,
+			wantUpdated:  1,
+			wantOutdated: 2,
+			wantPrevious: "v0",
+			wantUpToDate: metav1.ConditionFalse,
+		},
+		{
+			name:          "upgrade completes",
+			vmList:        []*infrav1.AzureStackHCIVirtualMachine{newTestReplica("a", time.Hour, "v2")},
+			upgrade:       &infrav1.LoadBalancerUpgradeStatus{OSVersion: "v2", PreviousOSVersion: "v1"},
+			wantUpgrade:   true,
+			wantUpdated:   1,
+			wantPrevious:  "v1",
+			wantUpToDate:  metav1.ConditionTrue,
+			wantCompleted: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			lbs := newTestLoadBalancerScope(1, "v2")
+			lbs.AzureStackHCILoadBalancer.Status.Upgrade = tc.upgrade
+			r := &AzureStackHCILoadBalancerReconciler{Recorder: record.NewFakeRecorder(10)}
+
+			r.updateUpgradeStatus(lbs, tc.vmList)
+
+			upgrade := lbs.AzureStackHCILoadBalancer.Status.Upgrade
+			if !tc.wantUpgrade {
+				g.Expect(upgrade).To(BeNil())
+				return
+			}
+			g.Expect(upgrade.OSVersion).To(Equal("v2"))
+			g.Expect(upgrade.UpdatedReplicas).To(Equal(tc.wantUpdated))
+			g.Expect(upgrade.OutdatedReplicas).To(Equal(tc.wantOutdated))
+			g.Expect(upgrade.PreviousOSVersion).To(Equal(tc.wantPrevious))
+			g.Expect(upgrade.CompletionTime != nil).To(Equal(tc.wantCompleted))
+			cond := conditions.Get(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerReplicasUpToDateCondition)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(tc.wantUpToDate))
+		})
+	}
+}
+
+// TestRollingUpdateVirtualMachines verifies that an outdated replica is only removed when the remaining ready
+// replicas satisfy maxUnavailable, and that the update waits while the replicas are at maxSurge.
+func TestRollingUpdateVirtualMachines(t *testing.T) {
+	tests := []struct {
+		name          string
+		desired       int32
+		replicas      int32
+		readyReplicas int32
+		vmList        []*infrav1.AzureStackHCIVirtualMachine
+		wantDeleted   string
+		wantReplicas  int32
+	}{
+		{
+			name:          "surged replica is ready, remove the outdated replica",
+			desired:       1,
+			replicas:      2,
+			readyReplicas: 2,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("outdated", time.Minute, "v1"),
+				newTestReplica("updated", time.Second, "v2"),
+			},
+			wantDeleted:  "outdated",
+			wantReplicas: 1,
+		},
+		{
+			name:          "surged replica is not ready yet, wait",
+			desired:       1,
+			replicas:      2,
+			readyReplicas: 1,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("outdated", time.Minute, "v1"),
+				newTestReplica("updated", time.Second, "v2"),
+			},
+			wantReplicas: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r, clusterScope := newTestLoadBalancerReconciler(g, tc.vmList)
+
+			lbs := newTestLoadBalancerScope(tc.desired, "v2")
+			lbs.SetReplicas(tc.replicas)
+			lbs.SetReadyReplicas(tc.readyReplicas)
+
+			result, err := r.rollingUpdateVirtualMachines(lbs, clusterScope, tc.vmList)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			g.Expect(lbs.GetReplicas()).To(Equal(tc.wantReplicas))
+			g.Expect(conditions.GetReason(lbs.AzureStackHCILoadBalancer, infrav1.LoadBalancerReplicasReadyCondition)).To(Equal(infrav1.LoadBalancerReplicasUpgradingReason))
+
+			remaining := &infrav1.AzureStackHCIVirtualMachineList{}
+			g.Expect(r.Client.List(context.Background(), remaining)).To(Succeed())
+			names := []string{}
+			for _, vm := range remaining.Items {
+				names = append(names, vm.Name)
+			}
+			if tc.wantDeleted == "" {
+				g.Expect(names).To(HaveLen(len(tc.vmList)))
+				return
+			}
+			g.Expect(names).To(HaveLen(len(tc.vmList) - 1))
+			g.Expect(names).NotTo(ContainElement(tc.wantDeleted))
+		})
+	}
+}
+
+func TestSelectVirtualMachineForScaleDown(t *testing.T) {
+	withState := func(vm *infrav1.AzureStackHCIVirtualMachine, state infrav1.VMState) *infrav1.AzureStackHCIVirtualMachine {
+		vm.Status.VMState = ptr.To(state)
+		return vm
+	}
+	onHost := func(vm *infrav1.AzureStackHCIVirtualMachine, host string) *infrav1.AzureStackHCIVirtualMachine {
+		vm.Status.Host = host
+		return vm
+	}
+	inAvailabilitySet := func(vm *infrav1.AzureStackHCIVirtualMachine, name string) *infrav1.AzureStackHCIVirtualMachine {
+		vm.Spec.AvailabilitySetName = name
+		return vm
+	}
+
+	tests := []struct {
+		name    string
+		vmList  []*infrav1.AzureStackHCIVirtualMachine
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no replicas",
+			wantErr: true,
+		},
+		{
+			name: "oldest replica",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				onHost(newTestReplica("young", time.Minute, "v2"), "host-1"),
+				onHost(newTestReplica("old", time.Hour, "v2"), "host-2"),
+			},
+			want: "old",
+		},
+		{
+			name: "failed before maintenance",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				inMaintenance(newTestReplica("maintenance", time.Hour, "v2")),
+				withState(newTestReplica("failed", time.Minute, "v2"), infrav1.VMStateFailed),
+			},
+			want: "failed",
+		},
+		{
+			name: "maintenance before outdated",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("outdated", time.Hour, "v1"),
+				inMaintenance(newTestReplica("maintenance", time.Minute, "v2")),
+			},
+			want: "maintenance",
+		},
+		{
+			name: "outdated before co-located",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				onHost(newTestReplica("shared-1", time.Hour, "v2"), "host-1"),
+				onHost(newTestReplica("shared-2", time.Hour, "v2"), "host-1"),
+				onHost(newTestReplica("outdated", time.Minute, "v1"), "host-2"),
+			},
+			want: "outdated",
+		},
+		{
+			name: "co-located on a host",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				onHost(newTestReplica("alone", time.Hour*2, "v2"), "host-1"),
+				onHost(newTestReplica("shared-old", time.Hour, "v2"), "host-2"),
+				onHost(newTestReplica("shared-young", time.Minute, "v2"), "host-2"),
+			},
+			want: "shared-old",
+		},
+		{
+			// every replica is in the availability set of the loadbalancer, which doesn't make them co-located
+			name: "shared availability set on distinct hosts",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				inAvailabilitySet(onHost(newTestReplica("young", time.Minute, "v2"), "host-1"), "set-1"),
+				inAvailabilitySet(onHost(newTestReplica("old", time.Hour, "v2"), "host-2"), "set-1"),
+			},
+			want: "old",
+		},
+		{
+			name: "hosts not reported",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestReplica("young", time.Minute, "v2"),
+				newTestReplica("old", time.Hour, "v2"),
+			},
+			want: "old",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := &AzureStackHCILoadBalancerReconciler{}
+
+			vm, reason, err := r.selectVirtualMachineForScaleDown(newTestLoadBalancerScope(1, "v2"), tc.vmList)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(vm.Name).To(Equal(tc.want))
+			g.Expect(reason).NotTo(BeEmpty())
+		})
+	}
+}
@@ -37,10 +37,14 @@ import (
 	apitypes "k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/paused"
+	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -56,6 +60,11 @@ type AzureStackHCIMachineReconciler struct {
 }
 
 func (r *AzureStackHCIMachineReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	clusterToAzureStackHCIMachines, err := util.ClusterToTypedObjectsMapper(mgr.GetClient(), &infrav1.AzureStackHCIMachineList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
 		WithLogConstructor(r.ConstructLogger).
@@ -72,6 +81,11 @@ func (r *AzureStackHCIMachineReconciler) SetupWithManager(mgr ctrl.Manager, opti
 			&infrav1.AzureStackHCIVirtualMachine{},
 			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrav1.AzureStackHCIMachine{}),
 		).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(clusterToAzureStackHCIMachines),
+			builder.WithPredicates(predicates.ClusterPausedTransitions(mgr.GetScheme(), r.Log)),
+		).
 		Complete(r)
 }
 
@@ -135,6 +149,11 @@ func (r *AzureStackHCIMachineReconciler) Reconcile(ctx context.Context, req ctrl
 
 	logger = logger.WithValues("cluster", cluster.Name)
 
+	// Return early if the object or Cluster is paused.
+	if isPaused, requeue, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, azureStackHCIMachine); err != nil || isPaused || requeue {
+		return reconcile.Result{}, err
+	}
+
 	azureStackHCICluster := &infrav1.AzureStackHCICluster{}
 
 	azureStackHCIClusterName := client.ObjectKey{
@@ -222,7 +241,26 @@ func (r *AzureStackHCIMachineReconciler) reconcileNormal(machineScope *scope.Mac
 		return reconcile.Result{}, nil
 	}
 
-	vm, err := r.reconcileVirtualMachineNormal(machineScope, clusterScope)
+	// Honor the failure domain Cluster API placed the Machine in, KCP relies on it to spread the control plane.
+	var failureDomain *infrav1.FailureDomainSpec
+	if name := machineScope.FailureDomain(); name != "" {
+		failureDomain = clusterScope.FailureDomain(name)
+		if failureDomain == nil {
+			message := fmt.Sprintf("failure domain %q is not declared on AzureStackHCICluster %s", name, clusterScope.Name())
+			machineScope.Info("Machine failure domain not found", "failureDomain", name)
+			r.Recorder.Eventf(machineScope.AzureStackHCIMachine, corev1.EventTypeWarning, "FailureDomainNotFound", message)
+			conditions.Set(machineScope.AzureStackHCIMachine, metav1.Condition{
+				Type:    infrav1.VMRunningCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  infrav1.FailureDomainNotFoundReason,
+				Message: message,
+			})
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+		}
+	}
+	machineScope.SetFailureDomain(machineScope.FailureDomain())
+
+	vm, err := r.reconcileVirtualMachineNormal(machineScope, clusterScope, failureDomain)
 
 	if err != nil {
 		return reconcile.Result{}, err
@@ -248,6 +286,18 @@ func (r *AzureStackHCIMachineReconciler) reconcileNormal(machineScope *scope.Mac
 		conditions.Set(machineScope.AzureStackHCIMachine, vmCond)
 	}
 
+	// A terminal failure of the VM won't be retried, surface it so that a MachineHealthCheck replaces the Machine.
+	machineScope.SetFailureReason(vm.Status.FailureReason)
+	machineScope.SetFailureMessage(vm.Status.FailureMessage)
+	if vm.Status.FailureReason != nil {
+		machineScope.Info("Machine VM failed terminally", "name", vm.Name, "reason", *vm.Status.FailureReason)
+		if err := r.markMachineForRemediation(machineScope); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, reconcile.TerminalError(errors.Errorf("AzureStackHCIVirtualMachine %s failed: %s",
+			vm.Name, ptr.Deref(vm.Status.FailureMessage, string(*vm.Status.FailureReason))))
+	}
+
 	if vm.Status.VMState == nil {
 		machineScope.Info("Waiting for VM controller to set vm state")
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Minute}, nil
@@ -255,6 +305,7 @@ func (r *AzureStackHCIMachineReconciler) reconcileNormal(machineScope *scope.Mac
 
 	// changed to avoid using dereference in function param for deep copying
 	machineScope.SetVMState(vm.Status.VMState)
+	machineScope.SetAddresses(vm.Status.Addresses)
 
 	switch *machineScope.GetVMState() {
 	case infrav1.VMStateSucceeded:
@@ -284,7 +335,28 @@ func (r *AzureStackHCIMachineReconciler) reconcileNormal(machineScope *scope.Mac
 	return reconcile.Result{}, nil
 }
 
-func (r *AzureStackHCIMachineReconciler) reconcileVirtualMachineNormal(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (*infrav1.AzureStackHCIVirtualMachine, error) {
+// markMachineForRemediation annotates the owner Machine for remediation. MachineHealthChecks remediate annotated
+// Machines right away instead of waiting for the node startup timeout.
+func (r *AzureStackHCIMachineReconciler) markMachineForRemediation(machineScope *scope.MachineScope) error {
+	machine := machineScope.Machine
+	if _, ok := machine.Annotations[clusterv1.RemediateMachineAnnotation]; ok {
+		return nil
+	}
+
+	patch := client.MergeFrom(machine.DeepCopy())
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+	machine.Annotations[clusterv1.RemediateMachineAnnotation] = ""
+	if err := r.Client.Patch(machineScope.Context, machine, patch); err != nil {
+		return errors.Wrapf(err, "failed to mark Machine %s/%s for remediation", machine.Namespace, machine.Name)
+	}
+	r.Recorder.Eventf(machineScope.AzureStackHCIMachine, corev1.EventTypeWarning, "MachineMarkedForRemediation",
+		"Machine %s/%s marked for remediation: %s", machine.Namespace, machine.Name, ptr.Deref(machineScope.AzureStackHCIMachine.Status.FailureMessage, ""))
+	return nil
+}
+
+func (r *AzureStackHCIMachineReconciler) reconcileVirtualMachineNormal(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, failureDomain *infrav1.FailureDomainSpec) (*infrav1.AzureStackHCIVirtualMachine, error) {
 	vm := &infrav1.AzureStackHCIVirtualMachine{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: clusterScope.Namespace(),
@@ -338,6 +410,7 @@ func (r *AzureStackHCIMachineReconciler) reconcileVirtualMachineNormal(machineSc
 		vm.Spec.Image = image.DeepCopy()
 
 		vm.Spec.VMSize = machineScope.AzureStackHCIMachine.Spec.VMSize
+		vm.Spec.CustomSize = machineScope.AzureStackHCIMachine.Spec.CustomSize.DeepCopy()
 		vm.Spec.GpuCount = machineScope.AzureStackHCIMachine.Spec.GpuCount
 		if machineScope.AzureStackHCIMachine.Spec.AvailabilityZone != nil {
 			vm.Spec.AvailabilityZone = machineScope.AzureStackHCIMachine.Spec.AvailabilityZone.DeepCopy()
@@ -349,11 +422,19 @@ func (r *AzureStackHCIMachineReconciler) reconcileVirtualMachineNormal(machineSc
 		vm.Spec.SSHPublicKey = machineScope.AzureStackHCIMachine.Spec.SSHPublicKey
 		vm.Spec.BootstrapData = &bootstrapData
 		vm.Spec.AdditionalSSHKeys = machineScope.AzureStackHCIMachine.Spec.AdditionalSSHKeys
-		vm.Spec.StorageContainer = machineScope.AzureStackHCIMachine.Spec.StorageContainer
+		vm.Spec.StorageContainer, err = storageContainerFor(clusterScope.Context, r.Client, clusterScope, vm, machineScope.AzureStackHCIMachine.Spec.StorageContainer)
+		if err != nil {
+			return errors.Wrap(err, "failed to select storage container")
+		}
 		vm.Spec.AvailabilitySetName = machineScope.AzureStackHCIMachine.Spec.AvailabilitySetName
 		vm.Spec.PlacementGroupName = machineScope.AzureStackHCIMachine.Spec.PlacementGroupName
+		applyFailureDomain(&vm.Spec, failureDomain)
 
-		machineScope.AzureStackHCIMachine.Spec.NetworkInterfaces.DeepCopyInto(&vm.Spec.NetworkInterfaces)
+		if len(machineScope.AzureStackHCIMachine.Spec.NetworkInterfaces) > 0 {
+			machineScope.AzureStackHCIMachine.Spec.NetworkInterfaces.DeepCopyInto(&vm.Spec.NetworkInterfaces)
+		} else if len(vm.Spec.NetworkInterfaces) == 0 {
+			vm.Spec.NetworkInterfaces = clusterScope.DualStackNetworkInterfaces()
+		}
 
 		infrav1util.CopyCorrelationID(machineScope.AzureStackHCIMachine, vm)
 
@@ -407,6 +488,20 @@ func (r *AzureStackHCIMachineReconciler) reconcileVirtualMachineNormal(machineSc
 	return azureStackHCIVirtualMachine, nil
 }
 
+// applyFailureDomain places the virtual machine in the availability set and placement group of the failure domain,
+// they take precedence over the ones set on the AzureStackHCIMachine.
+func applyFailureDomain(spec *infrav1.AzureStackHCIVirtualMachineSpec, failureDomain *infrav1.FailureDomainSpec) {
+	if failureDomain == nil {
+		return
+	}
+	if failureDomain.AvailabilitySetName != "" {
+		spec.AvailabilitySetName = failureDomain.AvailabilitySetName
+	}
+	if failureDomain.PlacementGroupName != "" {
+		spec.PlacementGroupName = failureDomain.PlacementGroupName
+	}
+}
+
 func (r *AzureStackHCIMachineReconciler) reconcileDelete(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	machineScope.Info("Handling deleted AzureStackHCIMachine", "MachineName", machineScope.AzureStackHCIMachine.Name)
 
@@ -2,19 +2,31 @@ package controllers
 
 import (
 	"context"
+	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
+
 	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
 	mock8sclient "github.com/microsoft/cluster-api-provider-azurestackhci/test/mocks/k8s/client"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var _ = Describe("AzureStackHCIMachine Controller", func() {
@@ -243,3 +255,134 @@ var _ = Describe("AzureStackHCIMachine Controller", func() {
 
 	})
 })
+
+// TestReconcileMachineNormalVirtualMachineStatus verifies that the conditions of the AzureStackHCIVirtualMachine,
+// whose reasons are the classes of the MOC errors, are surfaced on the AzureStackHCIMachine, and that a virtual
+// machine that failed terminally fails the reconciliation for good and marks the Machine for remediation.
+func TestReconcileMachineNormalVirtualMachineStatus(t *testing.T) {
+	vmRunning := func(status metav1.ConditionStatus, reason string) metav1.Condition {
+		return metav1.Condition{Type: infrav1.VMRunningCondition, Status: status, Reason: reason, LastTransitionTime: metav1.Now()}
+	}
+
+	tests := []struct {
+		name              string
+		vmStatus          infrav1.AzureStackHCIVirtualMachineStatus
+		wantResult        reconcile.Result
+		wantTerminal      bool
+		wantReason        string
+		wantReady         bool
+		wantRemediation   bool
+		wantFailureReason *capierrors.MachineStatusError
+	}{
+		{
+			name: "running",
+			vmStatus: infrav1.AzureStackHCIVirtualMachineStatus{
+				VMState:    ptr.To(infrav1.VMStateSucceeded),
+				Conditions: []metav1.Condition{vmRunning(metav1.ConditionTrue, string(infrav1.VMStateSucceeded))},
+			},
+			wantReason: "VMRunning",
+			wantReady:  true,
+		},
+		{
+			name: "MOC unreachable",
+			vmStatus: infrav1.AzureStackHCIVirtualMachineStatus{
+				Conditions: []metav1.Condition{vmRunning(metav1.ConditionFalse, infrav1.MOCUnreachableReason)},
+			},
+			wantResult: reconcile.Result{Requeue: true, RequeueAfter: time.Minute},
+			wantReason: infrav1.MOCUnreachableReason,
+		},
+		{
+			name: "failed terminally",
+			vmStatus: infrav1.AzureStackHCIVirtualMachineStatus{
+				FailureReason:  ptr.To(capierrors.InsufficientResourcesMachineError),
+				FailureMessage: ptr.To("out of capacity"),
+				Conditions:     []metav1.Condition{vmRunning(metav1.ConditionFalse, infrav1.OutOfCapacityReason)},
+			},
+			wantTerminal:      true,
+			wantReason:        infrav1.OutOfCapacityReason,
+			wantRemediation:   true,
+			wantFailureReason: ptr.To(capierrors.InsufficientResourcesMachineError),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+			azureStackHCICluster := &infrav1.AzureStackHCICluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+				Status: infrav1.AzureStackHCIClusterStatus{
+					Initialization: &infrav1.AzureStackHCIClusterInitializationStatus{Provisioned: ptr.To(true)},
+				},
+			}
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "cluster"}},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "cluster",
+					Version:     "v1.31.0",
+					Bootstrap:   clusterv1.Bootstrap{DataSecretName: ptr.To("machine-bootstrap")},
+				},
+			}
+			azureStackHCIMachine := &infrav1.AzureStackHCIMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+				Spec: infrav1.AzureStackHCIMachineSpec{
+					VMSize: "Standard_A4_v2",
+					Image:  &infrav1.Image{Name: ptr.To("linux-image"), OSType: infrav1.OSTypeLinux},
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-bootstrap", Namespace: "default"},
+				Data:       map[string][]byte{"value": []byte("bootstrap")},
+			}
+			vm := &infrav1.AzureStackHCIVirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+				Status:     tc.vmStatus,
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(machine, azureStackHCIMachine, secret, vm).
+				WithStatusSubresource(&infrav1.AzureStackHCIMachine{}, &infrav1.AzureStackHCIVirtualMachine{}).
+				Build()
+
+			logger := logr.Discard()
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:               c,
+				Logger:               &logger,
+				Cluster:              cluster,
+				Machine:              machine,
+				AzureStackHCICluster: azureStackHCICluster,
+				AzureStackHCIMachine: azureStackHCIMachine,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			clusterScope := &scope.ClusterScope{
+				Logger:               logger,
+				Client:               c,
+				Context:              context.Background(),
+				Cluster:              cluster,
+				AzureStackHCICluster: azureStackHCICluster,
+			}
+			r := &AzureStackHCIMachineReconciler{Client: c, Log: logger, Recorder: record.NewFakeRecorder(10)}
+
+			result, err := r.reconcileNormal(machineScope, clusterScope)
+			if tc.wantTerminal {
+				g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(result).To(Equal(tc.wantResult))
+			g.Expect(conditions.GetReason(azureStackHCIMachine, infrav1.VMRunningCondition)).To(Equal(tc.wantReason))
+			g.Expect(azureStackHCIMachine.Status.Ready).To(Equal(tc.wantReady))
+			g.Expect(azureStackHCIMachine.Status.FailureReason).To(Equal(tc.wantFailureReason))
+
+			stored := &clusterv1.Machine{}
+			g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(machine), stored)).To(Succeed())
+			_, marked := stored.Annotations[clusterv1.RemediateMachineAnnotation]
+			g.Expect(marked).To(Equal(tc.wantRemediation))
+		})
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/paused"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AzureStackHCIMachinePoolReconciler reconciles a AzureStackHCIMachinePool object
+type AzureStackHCIMachinePoolReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+func (r *AzureStackHCIMachinePoolReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	clusterToAzureStackHCIMachinePools, err := util.ClusterToTypedObjectsMapper(mgr.GetClient(), &infrav1.AzureStackHCIMachinePoolList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureStackHCIMachinePool{}).
+		Watches(
+			&clusterv1.MachinePool{},
+			handler.EnqueueRequestsFromMapFunc(util.MachinePoolToInfrastructureMapFunc(ctrl.LoggerInto(context.Background(), r.Log), infrav1.GroupVersion.WithKind("AzureStackHCIMachinePool"))),
+		).
+		Watches(
+			&infrav1.AzureStackHCIVirtualMachine{},
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrav1.AzureStackHCIMachinePool{}),
+		).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(clusterToAzureStackHCIMachinePools),
+			builder.WithPredicates(predicates.ClusterPausedTransitions(mgr.GetScheme(), r.Log)),
+		).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhcimachinepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhcimachinepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+
+// Reconcile keeps the virtual machines of an AzureStackHCIMachinePool in line with the replicas and the template
+// of the MachinePool that owns it, and reports their provider IDs back to the MachinePool.
+func (r *AzureStackHCIMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	logger := r.Log.WithValues("azureStackHCIMachinePool", req.NamespacedName, "reconcileID", infrav1util.GetReconcileID(ctx))
+	logger.Info("Attempt to reconcile resource")
+
+	// Fetch the AzureStackHCIMachinePool resource.
+	azureStackHCIMachinePool := &infrav1.AzureStackHCIMachinePool{}
+	err := r.Get(ctx, req.NamespacedName, azureStackHCIMachinePool)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	logger = logger.WithValues("operationId", azureStackHCIMachinePool.GetAnnotations()[infrav1.AzureOperationIDAnnotationKey], "correlationId", azureStackHCIMachinePool.GetAnnotations()[infrav1.AzureCorrelationIDAnnotationKey])
+
+	// Fetch the MachinePool.
+	machinePool, err := util.GetOwnerMachinePool(ctx, r.Client, azureStackHCIMachinePool.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if machinePool == nil {
+		logger.Info("MachinePool Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	logger = logger.WithValues("machinePool", machinePool.Name)
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		logger.Info("MachinePool is missing cluster label or cluster does not exist")
+		return reconcile.Result{}, nil
+	}
+
+	logger = logger.WithValues("cluster", cluster.Name)
+
+	// Return early if the object or Cluster is paused.
+	if isPaused, requeue, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, azureStackHCIMachinePool); err != nil || isPaused || requeue {
+		return reconcile.Result{}, err
+	}
+
+	azureStackHCICluster := &infrav1.AzureStackHCICluster{}
+
+	azureStackHCIClusterName := client.ObjectKey{
+		Namespace: azureStackHCIMachinePool.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, azureStackHCIClusterName, azureStackHCICluster); err != nil {
+		logger.Info("AzureStackHCICluster is not available yet")
+		return reconcile.Result{}, nil
+	}
+
+	logger = logger.WithValues("azureStackHCICluster", azureStackHCICluster.Name)
+
+	// Create the cluster scope
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client:               r.Client,
+		Logger:               &logger,
+		Cluster:              cluster,
+		AzureStackHCICluster: azureStackHCICluster,
+	})
+	if err != nil {
+		r.Recorder.Eventf(azureStackHCIMachinePool, corev1.EventTypeWarning, "CreateClusterScopeFailed", errors.Wrapf(err, "failed to create cluster scope").Error())
+		return reconcile.Result{}, err
+	}
+
+	// Create the machine pool scope
+	machinePoolScope, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+		Logger:                   &logger,
+		Client:                   r.Client,
+		Cluster:                  cluster,
+		MachinePool:              machinePool,
+		AzureStackHCICluster:     azureStackHCICluster,
+		AzureStackHCIMachinePool: azureStackHCIMachinePool,
+	})
+	if err != nil {
+		r.Recorder.Eventf(azureStackHCIMachinePool, corev1.EventTypeWarning, "FailureCreateMachinePoolScope", errors.Wrapf(err, "failed to create machine pool scope").Error())
+		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	// Always close the scope when exiting this function so we can persist any AzureStackHCIMachinePool changes.
+	defer func() {
+		if err := machinePoolScope.Close(); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	// Handle deleted machine pools
+	if !azureStackHCIMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(machinePoolScope, clusterScope)
+	}
+
+	// Handle non-deleted machine pools
+	return r.reconcileNormal(machinePoolScope, clusterScope)
+}
+
+func (r *AzureStackHCIMachinePoolReconciler) reconcileNormal(machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	machinePoolScope.Info("Reconciling AzureStackHCIMachinePool")
+
+	// If the AzureStackHCIMachinePool doesn't have our finalizer, add it.
+	controllerutil.AddFinalizer(machinePoolScope.AzureStackHCIMachinePool, infrav1.MachinePoolFinalizer)
+	// Register the finalizer immediately to avoid orphaning virtual machines on delete
+	if err := machinePoolScope.PatchObject(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Check if the infrastructure cluster is ready by checking our own AzureStackHCICluster status
+	if clusterScope.AzureStackHCICluster.Status.Initialization == nil ||
+		clusterScope.AzureStackHCICluster.Status.Initialization.Provisioned == nil ||
+		!*clusterScope.AzureStackHCICluster.Status.Initialization.Provisioned {
+		machinePoolScope.Info("Cluster infrastructure is not ready yet")
+		return reconcile.Result{}, nil
+	}
+
+	// Make sure bootstrap data is available and populated.
+	if machinePoolScope.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+		machinePoolScope.Info("Bootstrap data secret reference is not yet available")
+		conditions.Set(machinePoolScope.AzureStackHCIMachinePool, metav1.Condition{
+			Type:   infrav1.MachinePoolReplicasReadyCondition,
+			Status: metav1.ConditionFalse,
+			Reason: infrav1.MachinePoolWaitingForBootstrapDataReason,
+		})
+		return reconcile.Result{}, nil
+	}
+
+	return r.reconcileVirtualMachines(machinePoolScope, clusterScope)
+}
+
+func (r *AzureStackHCIMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	machinePoolScope.Info("Handling deleted AzureStackHCIMachinePool")
+
+	vmList, err := r.getVirtualMachinesForMachinePool(machinePoolScope, clusterScope, true)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get machine pool virtual machine list")
+	}
+
+	if len(vmList) > 0 {
+		for _, vm := range vmList {
+			if err := r.deleteVirtualMachine(machinePoolScope, clusterScope, vm); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		machinePoolScope.Info("Waiting for AzureStackHCIVirtualMachines to be deleted", "count", len(vmList))
+		return reconcile.Result{RequeueAfter: 15 * time.Second}, nil
+	}
+
+	// All virtual machines are gone, remove the finalizer.
+	controllerutil.RemoveFinalizer(machinePoolScope.AzureStackHCIMachinePool, infrav1.MachinePoolFinalizer)
+
+	return reconcile.Result{}, nil
+}
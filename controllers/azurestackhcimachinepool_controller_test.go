@@ -0,0 +1,475 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+)
+
+func TestSelectVirtualMachinesForScaleDown(t *testing.T) {
+	const templateHash = "current"
+	now := time.Now()
+	vm := func(name string, age time.Duration, state infrav1.VMState, hash string, annotations map[string]string) *infrav1.AzureStackHCIVirtualMachine {
+		return &infrav1.AzureStackHCIVirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				Labels:            map[string]string{infrav1.MachinePoolTemplateHashLabel: hash},
+				Annotations:       annotations,
+			},
+			Status: infrav1.AzureStackHCIVirtualMachineStatus{VMState: &state},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		vmList []*infrav1.AzureStackHCIVirtualMachine
+		count  int
+		want   []string
+	}{
+		{
+			name: "oldest first",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				vm("young", time.Minute, infrav1.VMStateSucceeded, templateHash, nil),
+				vm("old", time.Hour, infrav1.VMStateSucceeded, templateHash, nil),
+			},
+			count: 1,
+			want:  []string{"old"},
+		},
+		{
+			name: "delete-machine annotation before not running before outdated",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				vm("outdated", time.Hour, infrav1.VMStateSucceeded, "previous", nil),
+				vm("creating", time.Hour, infrav1.VMStateCreating, templateHash, nil),
+				vm("annotated", time.Minute, infrav1.VMStateSucceeded, templateHash, map[string]string{clusterv1.DeleteMachineAnnotation: ""}),
+				vm("current", 2*time.Hour, infrav1.VMStateSucceeded, templateHash, nil),
+			},
+			count: 3,
+			want:  []string{"annotated", "creating", "outdated"},
+		},
+		{
+			name: "count larger than pool",
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				vm("only", time.Minute, infrav1.VMStateSucceeded, templateHash, nil),
+			},
+			count: 2,
+			want:  []string{"only"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			selected := selectVirtualMachinesForScaleDown(tt.vmList, templateHash, tt.count)
+			names := []string{}
+			for _, vm := range selected {
+				names = append(names, vm.Name)
+			}
+			g.Expect(names).To(Equal(tt.want))
+		})
+	}
+}
+
+// newTestMachinePoolReconciler returns a reconciler whose client holds the machine pool, its bootstrap secret and
+// the virtual machines, and the scopes of the machine pool and its cluster.
+func newTestMachinePoolReconciler(g *WithT, replicas int32, strategy *infrav1.MachinePoolStrategy, vmList []*infrav1.AzureStackHCIVirtualMachine) (*AzureStackHCIMachinePoolReconciler, *scope.MachinePoolScope, *scope.ClusterScope) {
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+	azureStackHCICluster := &infrav1.AzureStackHCICluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+	machinePool := &clusterv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+		Spec: clusterv1.MachinePoolSpec{
+			Replicas: ptr.To(replicas),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Version:   "v1.31.0",
+					Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("pool-bootstrap")},
+				},
+			},
+		},
+	}
+	azureStackHCIMachinePool := &infrav1.AzureStackHCIMachinePool{
+		TypeMeta:   metav1.TypeMeta{APIVersion: infrav1.GroupVersion.String(), Kind: "AzureStackHCIMachinePool"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", UID: "pool-uid"},
+		Spec: infrav1.AzureStackHCIMachinePoolSpec{
+			Template: infrav1.AzureStackHCIMachinePoolMachineTemplate{
+				VMSize: "Standard_A4_v2",
+				Image:  &infrav1.Image{Name: ptr.To("linux-image"), OSType: infrav1.OSTypeLinux},
+			},
+			Strategy: strategy,
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool-bootstrap", Namespace: "default"},
+		Data:       map[string][]byte{"value": []byte("bootstrap")},
+	}
+
+	objs := []client.Object{azureStackHCIMachinePool, secret}
+	for _, vm := range vmList {
+		objs = append(objs, vm)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&infrav1.AzureStackHCIMachinePool{}).
+		Build()
+
+	logger := logr.Discard()
+	mps, err := scope.NewMachinePoolScope(scope.MachinePoolScopeParams{
+		Client:                   c,
+		Logger:                   &logger,
+		Cluster:                  cluster,
+		MachinePool:              machinePool,
+		AzureStackHCICluster:     azureStackHCICluster,
+		AzureStackHCIMachinePool: azureStackHCIMachinePool,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	clusterScope := &scope.ClusterScope{
+		Logger:               logger,
+		Client:               c,
+		Context:              context.Background(),
+		Cluster:              cluster,
+		AzureStackHCICluster: azureStackHCICluster,
+	}
+	return &AzureStackHCIMachinePoolReconciler{Client: c, Log: logger, Recorder: record.NewFakeRecorder(10)}, mps, clusterScope
+}
+
+func newTestPoolVM(name string, age time.Duration, state infrav1.VMState, hash string) *infrav1.AzureStackHCIVirtualMachine {
+	return &infrav1.AzureStackHCIVirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			Labels:            map[string]string{infrav1.MachinePoolLabel: "pool", infrav1.MachinePoolTemplateHashLabel: hash},
+		},
+		Status: infrav1.AzureStackHCIVirtualMachineStatus{VMState: &state},
+	}
+}
+
+// currentHash is replaced by the template hash of the test machine pool.
+const currentHash = "current"
+
+func TestReconcileMachinePoolVirtualMachines(t *testing.T) {
+	failed := func(vm *infrav1.AzureStackHCIVirtualMachine, reason capierrors.MachineStatusError) *infrav1.AzureStackHCIVirtualMachine {
+		vm.Status.FailureReason = &reason
+		return vm
+	}
+	notRunning := func(vm *infrav1.AzureStackHCIVirtualMachine, reason string) *infrav1.AzureStackHCIVirtualMachine {
+		conditions.Set(vm, metav1.Condition{Type: infrav1.VMRunningCondition, Status: metav1.ConditionFalse, Reason: reason})
+		return vm
+	}
+
+	tests := []struct {
+		name         string
+		replicas     int32
+		strategy     *infrav1.MachinePoolStrategy
+		vmList       []*infrav1.AzureStackHCIVirtualMachine
+		wantRemoved  []string
+		wantCreated  int
+		wantReason   string
+		wantRequeue  bool
+		wantTerminal bool
+		wantReplicas int32
+	}{
+		{
+			name:         "scale up from zero",
+			replicas:     2,
+			wantCreated:  2,
+			wantReason:   infrav1.MachinePoolScalingUpReason,
+			wantRequeue:  true,
+			wantReplicas: 2,
+		},
+		{
+			name:     "scale down removes the oldest replica",
+			replicas: 1,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("young", time.Minute, infrav1.VMStateSucceeded, currentHash),
+				newTestPoolVM("old", time.Hour, infrav1.VMStateSucceeded, currentHash),
+			},
+			wantRemoved:  []string{"old"},
+			wantReason:   infrav1.MachinePoolScalingDownReason,
+			wantRequeue:  true,
+			wantReplicas: 1,
+		},
+		{
+			name:     "failed replica is replaced",
+			replicas: 2,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("running", time.Hour, infrav1.VMStateSucceeded, currentHash),
+				failed(newTestPoolVM("failed", time.Hour, infrav1.VMStateFailed, currentHash), capierrors.CreateMachineError),
+			},
+			wantRemoved:  []string{"failed"},
+			wantReason:   infrav1.MachinePoolReplacingFailedReplicasReason,
+			wantRequeue:  true,
+			wantReplicas: 1,
+		},
+		{
+			name:     "invalid configuration is kept until the template changes",
+			replicas: 2,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("running", time.Hour, infrav1.VMStateSucceeded, currentHash),
+				failed(newTestPoolVM("invalid", time.Hour, infrav1.VMStateFailed, currentHash), capierrors.InvalidConfigurationMachineError),
+			},
+			wantReason:   infrav1.MachinePoolWaitingForReplicasReadyReason,
+			wantRequeue:  true,
+			wantReplicas: 2,
+		},
+		{
+			name:     "invalid configuration of the current template stops the scale up",
+			replicas: 3,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("running", time.Hour, infrav1.VMStateSucceeded, currentHash),
+				notRunning(failed(newTestPoolVM("invalid", time.Hour, infrav1.VMStateFailed, currentHash), capierrors.InvalidConfigurationMachineError), infrav1.PathNotFoundReason),
+			},
+			wantReason:   infrav1.PathNotFoundReason,
+			wantTerminal: true,
+			wantReplicas: 2,
+		},
+		{
+			name:     "invalid configuration of the previous template is removed by the rolling update",
+			replicas: 1,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				failed(newTestPoolVM("invalid", time.Hour, infrav1.VMStateFailed, "previous"), capierrors.InvalidConfigurationMachineError),
+			},
+			wantRemoved:  []string{"invalid"},
+			wantReason:   infrav1.MachinePoolRollingUpdateReason,
+			wantRequeue:  true,
+			wantReplicas: 0,
+		},
+		{
+			name:     "rolling update surges before removing a running replica",
+			replicas: 2,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("old", time.Hour, infrav1.VMStateSucceeded, "previous"),
+				newTestPoolVM("older", 2*time.Hour, infrav1.VMStateSucceeded, "previous"),
+			},
+			wantCreated:  1,
+			wantReason:   infrav1.MachinePoolRollingUpdateReason,
+			wantRequeue:  true,
+			wantReplicas: 3,
+		},
+		{
+			name:     "rolling update removes the oldest replica within maxUnavailable",
+			replicas: 2,
+			strategy: &infrav1.MachinePoolStrategy{RollingUpdate: &infrav1.MachinePoolRollingUpdate{
+				MaxSurge:       ptr.To(intstr.FromInt32(0)),
+				MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+			}},
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("old", time.Hour, infrav1.VMStateSucceeded, "previous"),
+				newTestPoolVM("older", 2*time.Hour, infrav1.VMStateSucceeded, "previous"),
+			},
+			wantRemoved:  []string{"older"},
+			wantReason:   infrav1.MachinePoolRollingUpdateReason,
+			wantRequeue:  true,
+			wantReplicas: 1,
+		},
+		{
+			name:     "rolling update waits for the surged replica",
+			replicas: 2,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("old", time.Hour, infrav1.VMStateSucceeded, "previous"),
+				newTestPoolVM("older", 2*time.Hour, infrav1.VMStateSucceeded, "previous"),
+				newTestPoolVM("new", time.Minute, infrav1.VMStateCreating, currentHash),
+			},
+			wantReason:   infrav1.MachinePoolRollingUpdateReason,
+			wantRequeue:  true,
+			wantReplicas: 3,
+		},
+		{
+			name:     "rolling update removes an outdated replica that isn't running",
+			replicas: 2,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("current", time.Minute, infrav1.VMStateSucceeded, currentHash),
+				newTestPoolVM("running", time.Hour, infrav1.VMStateSucceeded, "previous"),
+				newTestPoolVM("stopped", time.Minute, infrav1.VMStateFailed, "previous"),
+			},
+			wantRemoved:  []string{"stopped"},
+			wantReason:   infrav1.MachinePoolRollingUpdateReason,
+			wantRequeue:  true,
+			wantReplicas: 2,
+		},
+		{
+			name:     "all replicas ready",
+			replicas: 2,
+			vmList: []*infrav1.AzureStackHCIVirtualMachine{
+				newTestPoolVM("b", time.Hour, infrav1.VMStateSucceeded, currentHash),
+				newTestPoolVM("a", time.Hour, infrav1.VMStateSucceeded, currentHash),
+			},
+			wantReason:   "AllReplicasReady",
+			wantReplicas: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// the template hash depends on the machine pool, resolve it before the virtual machines are stored
+			_, mps, _ := newTestMachinePoolReconciler(g, tt.replicas, tt.strategy, nil)
+			templateHash, err := mps.TemplateHash()
+			g.Expect(err).NotTo(HaveOccurred())
+			for _, vm := range tt.vmList {
+				if vm.Labels[infrav1.MachinePoolTemplateHashLabel] == currentHash {
+					vm.Labels[infrav1.MachinePoolTemplateHashLabel] = templateHash
+				}
+			}
+
+			r, mps, clusterScope := newTestMachinePoolReconciler(g, tt.replicas, tt.strategy, tt.vmList)
+			result, err := r.reconcileVirtualMachines(mps, clusterScope)
+			if tt.wantTerminal {
+				g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(result.RequeueAfter > 0).To(Equal(tt.wantRequeue))
+			g.Expect(conditions.GetReason(mps.AzureStackHCIMachinePool, infrav1.MachinePoolReplicasReadyCondition)).To(Equal(tt.wantReason))
+			g.Expect(mps.GetReplicas()).To(Equal(tt.wantReplicas))
+
+			remaining := &infrav1.AzureStackHCIVirtualMachineList{}
+			g.Expect(r.Client.List(context.Background(), remaining)).To(Succeed())
+			names := map[string]bool{}
+			created := 0
+			for _, vm := range remaining.Items {
+				names[vm.Name] = true
+				if !containsVM(tt.vmList, vm.Name) {
+					created++
+					g.Expect(vm.Labels).To(HaveKeyWithValue(infrav1.MachinePoolLabel, "pool"))
+					g.Expect(vm.Labels).To(HaveKeyWithValue(infrav1.MachinePoolTemplateHashLabel, templateHash))
+					g.Expect(vm.Spec.VMSize).To(Equal("Standard_A4_v2"))
+					g.Expect(vm.Spec.BootstrapData).NotTo(BeNil())
+				}
+			}
+			g.Expect(created).To(Equal(tt.wantCreated))
+			for _, name := range tt.wantRemoved {
+				g.Expect(names).NotTo(HaveKey(name))
+			}
+			g.Expect(remaining.Items).To(HaveLen(len(tt.vmList) + tt.wantCreated - len(tt.wantRemoved)))
+		})
+	}
+}
+
+func containsVM(vmList []*infrav1.AzureStackHCIVirtualMachine, name string) bool {
+	for _, vm := range vmList {
+		if vm.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TestUpdateReplicaStatus verifies that only running virtual machines are reported to the MachinePool, and that the
+// pool is reported as provisioned once all replicas are ready.
+func TestUpdateReplicaStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	vmList := []*infrav1.AzureStackHCIVirtualMachine{
+		newTestPoolVM("pool-b", time.Hour, infrav1.VMStateSucceeded, currentHash),
+		newTestPoolVM("pool-a", time.Hour, infrav1.VMStateSucceeded, currentHash),
+		newTestPoolVM("pool-c", time.Hour, infrav1.VMStateCreating, "previous"),
+	}
+	r, mps, _ := newTestMachinePoolReconciler(g, 2, nil, nil)
+
+	r.updateReplicaStatus(mps, vmList, currentHash)
+
+	status := mps.AzureStackHCIMachinePool.Status
+	g.Expect(mps.AzureStackHCIMachinePool.Spec.ProviderIDList).To(Equal([]string{"moc://pool-a", "moc://pool-b"}))
+	g.Expect(status.Replicas).To(Equal(int32(3)))
+	g.Expect(status.ReadyReplicas).To(Equal(int32(2)))
+	g.Expect(status.UpToDateReplicas).To(Equal(int32(2)))
+	g.Expect(status.Instances).To(HaveLen(3))
+	g.Expect(status.Instances[0].Name).To(Equal("pool-a"))
+	g.Expect(status.Instances[2].ProviderID).To(BeEmpty())
+	g.Expect(status.Instances[2].UpToDate).To(BeFalse())
+	g.Expect(conditions.IsFalse(mps.AzureStackHCIMachinePool, infrav1.MachinePoolReplicasUpToDateCondition)).To(BeTrue())
+	g.Expect(status.Initialization).To(BeNil())
+
+	// once the outdated replica is gone, the pool is up to date and provisioned
+	r, mps, clusterScope := newTestMachinePoolReconciler(g, 2, nil, nil)
+	templateHash, err := mps.TemplateHash()
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, vm := range vmList[:2] {
+		vm.Labels[infrav1.MachinePoolTemplateHashLabel] = templateHash
+		g.Expect(r.Client.Create(context.Background(), vm.DeepCopy())).To(Succeed())
+	}
+
+	_, err = r.reconcileVirtualMachines(mps, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	status = mps.AzureStackHCIMachinePool.Status
+	g.Expect(mps.AzureStackHCIMachinePool.Spec.ProviderIDList).To(Equal([]string{"moc://pool-a", "moc://pool-b"}))
+	g.Expect(conditions.IsTrue(mps.AzureStackHCIMachinePool, infrav1.MachinePoolReplicasUpToDateCondition)).To(BeTrue())
+	g.Expect(status.Ready).To(BeTrue())
+	g.Expect(status.Initialization).NotTo(BeNil())
+	g.Expect(status.Initialization.Provisioned).To(Equal(ptr.To(true)))
+}
+
+// TestReconcileMachinePoolDelete verifies that the finalizer is only removed once all virtual machines of the pool
+// are gone.
+func TestReconcileMachinePoolDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	vmList := []*infrav1.AzureStackHCIVirtualMachine{
+		newTestPoolVM("pool-a", time.Hour, infrav1.VMStateSucceeded, currentHash),
+		newTestPoolVM("pool-b", time.Hour, infrav1.VMStateSucceeded, currentHash),
+	}
+	r, mps, clusterScope := newTestMachinePoolReconciler(g, 2, nil, vmList)
+	controllerutil.AddFinalizer(mps.AzureStackHCIMachinePool, infrav1.MachinePoolFinalizer)
+
+	result, err := r.reconcileDelete(mps, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+	g.Expect(controllerutil.ContainsFinalizer(mps.AzureStackHCIMachinePool, infrav1.MachinePoolFinalizer)).To(BeTrue())
+
+	remaining := &infrav1.AzureStackHCIVirtualMachineList{}
+	g.Expect(r.Client.List(context.Background(), remaining)).To(Succeed())
+	g.Expect(remaining.Items).To(BeEmpty())
+
+	result, err = r.reconcileDelete(mps, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeZero())
+	g.Expect(controllerutil.ContainsFinalizer(mps.AzureStackHCIMachinePool, infrav1.MachinePoolFinalizer)).To(BeFalse())
+}
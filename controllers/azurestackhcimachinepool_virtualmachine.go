@@ -0,0 +1,504 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/telemetry"
+	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func (r *AzureStackHCIMachinePoolReconciler) reconcileVirtualMachines(mps *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	vmList, err := r.getVirtualMachinesForMachinePool(mps, clusterScope, false)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to get machine pool virtual machine list")
+	}
+
+	templateHash, err := mps.TemplateHash()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	r.updateReplicaStatus(mps, vmList, templateHash)
+
+	// replace virtual machines that failed terminally, they are recreated by the scale up of a later reconciliation.
+	// Virtual machines with an invalid configuration would fail again, they are replaced once the template changes.
+	var invalid *infrav1.AzureStackHCIVirtualMachine
+	if failed := getFailedVirtualMachines(vmList); len(failed) > 0 {
+		replaced := 0
+		for _, vm := range failed {
+			if *vm.Status.FailureReason == capierrors.InvalidConfigurationMachineError {
+				if isVirtualMachineUpToDate(vm, templateHash) {
+					invalid = vm
+				}
+				continue
+			}
+			if err := r.deleteVirtualMachine(mps, clusterScope, vm); err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to replace failed machine pool VM %s", vm.Name)
+			}
+			r.Recorder.Eventf(mps.AzureStackHCIMachinePool, corev1.EventTypeNormal, "ReplacingFailedReplica", "Replacing replica %s of AzureStackHCIMachinePool %s: %s", vm.Name, mps.Name(), *vm.Status.FailureReason)
+			mps.RemoveReplica()
+			replaced++
+		}
+		if replaced > 0 {
+			conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+				Type:   infrav1.MachinePoolReplicasReadyCondition,
+				Status: metav1.ConditionFalse,
+				Reason: infrav1.MachinePoolReplacingFailedReplicasReason,
+			})
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+		}
+	}
+
+	// new virtual machines are created from the current template, they would fail like the invalid one
+	scaleUp := mps.GetReplicas() < mps.GetDesiredReplicas()
+	rollingUpdate := mps.GetDesiredReplicas() > 0 && mps.AzureStackHCIMachinePool.Status.UpToDateReplicas < mps.GetReplicas()
+	if invalid != nil && (scaleUp || rollingUpdate) {
+		return r.reconcileInvalidConfiguration(mps, invalid)
+	}
+
+	// check if we need to scale up, new virtual machines are always created from the current template
+	if scaleUp {
+		count := mps.GetDesiredReplicas() - mps.GetReplicas()
+		conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+			Type:    infrav1.MachinePoolReplicasReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.MachinePoolScalingUpReason,
+			Message: fmt.Sprintf("creating %d virtual machines", count),
+		})
+		r.Recorder.Eventf(mps.AzureStackHCIMachinePool, corev1.EventTypeNormal, "ScalingUpMachinePool", "Scaling up AzureStackHCIMachinePool %s from %d to %d replicas", mps.Name(), mps.GetReplicas(), mps.GetDesiredReplicas())
+
+		for i := int32(0); i < count; i++ {
+			if err := r.createVirtualMachine(mps, clusterScope, templateHash); err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to scale up machine pool")
+			}
+			mps.AddReplica()
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+	}
+
+	// check if we need to replace outdated virtual machines
+	if rollingUpdate {
+		return r.rollingUpdateVirtualMachines(mps, clusterScope, vmList, templateHash)
+	}
+
+	// check if we need to scale down
+	if mps.GetReplicas() > mps.GetDesiredReplicas() {
+		count := int(mps.GetReplicas() - mps.GetDesiredReplicas())
+		conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+			Type:    infrav1.MachinePoolReplicasReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.MachinePoolScalingDownReason,
+			Message: fmt.Sprintf("removing %d virtual machines", count),
+		})
+
+		for _, vm := range selectVirtualMachinesForScaleDown(vmList, templateHash, count) {
+			r.Recorder.Eventf(mps.AzureStackHCIMachinePool, corev1.EventTypeNormal, "ScalingDownMachinePool", "Scaling down AzureStackHCIMachinePool %s by removing replica %s", mps.Name(), vm.Name)
+			if err := r.deleteVirtualMachine(mps, clusterScope, vm); err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to scale down machine pool VM %s", vm.Name)
+			}
+			mps.RemoveReplica()
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+	}
+
+	// all virtual machines exist, wait for them to be running
+	if mps.GetReadyReplicas() < mps.GetDesiredReplicas() {
+		conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+			Type:    infrav1.MachinePoolReplicasReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.MachinePoolWaitingForReplicasReadyReason,
+			Message: notRunningMessage(vmList),
+		})
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+	}
+
+	// desired state was achieved
+	if !conditions.IsTrue(mps.AzureStackHCIMachinePool, infrav1.MachinePoolReplicasReadyCondition) {
+		r.Recorder.Eventf(mps.AzureStackHCIMachinePool, corev1.EventTypeNormal, "MachinePoolReplicasReady", "All %d replicas of AzureStackHCIMachinePool %s are running", mps.GetDesiredReplicas(), mps.Name())
+	}
+	conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+		Type:   infrav1.MachinePoolReplicasReadyCondition,
+		Status: metav1.ConditionTrue,
+		Reason: "AllReplicasReady",
+	})
+	mps.SetReady()
+
+	return reconcile.Result{}, nil
+}
+
+// rollingUpdateVirtualMachines replaces outdated virtual machines one at a time. An outdated virtual machine is only
+// removed when the remaining running virtual machines satisfy maxUnavailable, otherwise a new virtual machine is
+// created within maxSurge. Outdated virtual machines that aren't running are removed right away.
+func (r *AzureStackHCIMachinePoolReconciler) rollingUpdateVirtualMachines(mps *scope.MachinePoolScope, clusterScope *scope.ClusterScope, vmList []*infrav1.AzureStackHCIVirtualMachine, templateHash string) (reconcile.Result, error) {
+	if conditions.GetReason(mps.AzureStackHCIMachinePool, infrav1.MachinePoolReplicasReadyCondition) != infrav1.MachinePoolRollingUpdateReason {
+		r.Recorder.Eventf(mps.AzureStackHCIMachinePool, corev1.EventTypeNormal, "RollingUpdateMachinePool", "Replacing outdated replicas of AzureStackHCIMachinePool %s", mps.Name())
+	}
+	conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+		Type:   infrav1.MachinePoolReplicasReadyCondition,
+		Status: metav1.ConditionFalse,
+		Reason: infrav1.MachinePoolRollingUpdateReason,
+	})
+
+	outdated := []*infrav1.AzureStackHCIVirtualMachine{}
+	for _, vm := range vmList {
+		if !isVirtualMachineUpToDate(vm, templateHash) {
+			outdated = append(outdated, vm)
+		}
+	}
+	vm := selectVirtualMachinesForScaleDown(outdated, templateHash, 1)[0]
+
+	if !isVirtualMachineRunning(vm) || mps.GetReadyReplicas()-1 >= mps.GetDesiredReplicas()-mps.GetMaxUnavailable() {
+		mps.Info("Removing outdated replica", "vmName", vm.Name)
+		if err := r.deleteVirtualMachine(mps, clusterScope, vm); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to remove outdated machine pool VM %s", vm.Name)
+		}
+		r.Recorder.Eventf(mps.AzureStackHCIMachinePool, corev1.EventTypeNormal, "RemovedOutdatedReplica", "Removed outdated replica %s of AzureStackHCIMachinePool %s", vm.Name, mps.Name())
+
+		mps.RemoveReplica()
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+	}
+
+	if mps.GetReplicas() < mps.GetMaxReplicas() {
+		if err := r.createVirtualMachine(mps, clusterScope, templateHash); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to create machine pool VM for rolling update")
+		}
+		mps.AddReplica()
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+	}
+
+	mps.Info("Waiting for replicas to be running before removing an outdated replica", "readyReplicas", mps.GetReadyReplicas(), "maxUnavailable", mps.GetMaxUnavailable())
+	return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 20}, nil
+}
+
+// createVirtualMachine creates a virtual machine of the pool from the current template
+func (r *AzureStackHCIMachinePoolReconciler) createVirtualMachine(mps *scope.MachinePoolScope, clusterScope *scope.ClusterScope, templateHash string) error {
+	vmName, err := azurestackhci.GenerateAzureStackHCIMachinePoolMachineName(mps.Name())
+	if err != nil {
+		return err
+	}
+
+	vm := &infrav1.AzureStackHCIVirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: clusterScope.Namespace(),
+			Name:      vmName,
+		},
+	}
+
+	mutateFn := func() (err error) {
+		// Mark the AzureStackHCIMachinePool as the owner of the AzureStackHCIVirtualMachine
+		vm.SetOwnerReferences(util.EnsureOwnerRef(
+			vm.OwnerReferences,
+			metav1.OwnerReference{
+				APIVersion: mps.AzureStackHCIMachinePool.APIVersion,
+				Kind:       mps.AzureStackHCIMachinePool.Kind,
+				Name:       mps.AzureStackHCIMachinePool.Name,
+				UID:        mps.AzureStackHCIMachinePool.UID,
+			}))
+
+		labels := vm.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[infrav1.MachinePoolLabel] = mps.Name()
+		labels[infrav1.MachinePoolTemplateHashLabel] = templateHash
+		vm.SetLabels(labels)
+
+		template := mps.AzureStackHCIMachinePool.Spec.Template
+		vm.Spec.ResourceGroup = clusterScope.AzureStackHCICluster.Spec.ResourceGroup
+		vm.Spec.VnetName = clusterScope.AzureStackHCICluster.Spec.NetworkSpec.Vnet.Name
+		vm.Spec.ClusterName = clusterScope.AzureStackHCICluster.Name
+		vm.Spec.SubnetName = azurestackhci.GenerateNodeSubnetName(clusterScope.Name())
+		if clusterScope.AzureStackHCILoadBalancer() != nil {
+			vm.Spec.BackendPoolNames = []string{azurestackhci.GenerateBackendPoolName(clusterScope.Name())}
+		}
+
+		bootstrapData, err := mps.GetBootstrapData()
+		if err != nil {
+			return errors.Wrap(err, "failed to retrieve bootstrap data")
+		}
+		vm.Spec.BootstrapData = &bootstrapData
+
+		image, err := r.getVMImage(mps)
+		if err != nil {
+			return errors.Wrap(err, "failed to get VM image")
+		}
+		vm.Spec.Image = image.DeepCopy()
+
+		vm.Spec.VMSize = template.VMSize
+		vm.Spec.CustomSize = template.CustomSize.DeepCopy()
+		vm.Spec.GpuCount = template.GpuCount
+		if template.OSDisk != nil {
+			vm.Spec.OSDisk = template.OSDisk.DeepCopy()
+		}
+		vm.Spec.Location = clusterScope.Location()
+		vm.Spec.SSHPublicKey = template.SSHPublicKey
+		vm.Spec.AdditionalSSHKeys = template.AdditionalSSHKeys
+		vm.Spec.StorageContainer, err = storageContainerFor(clusterScope.Context, r.Client, clusterScope, vm, template.StorageContainer)
+		if err != nil {
+			return errors.Wrap(err, "failed to select storage container")
+		}
+		vm.Spec.AvailabilitySetName = template.AvailabilitySetName
+		vm.Spec.PlacementGroupName = template.PlacementGroupName
+
+		if len(template.NetworkInterfaces) > 0 {
+			template.NetworkInterfaces.DeepCopyInto(&vm.Spec.NetworkInterfaces)
+		} else if len(vm.Spec.NetworkInterfaces) == 0 {
+			vm.Spec.NetworkInterfaces = clusterScope.DualStackNetworkInterfaces()
+		}
+
+		infrav1util.CopyCorrelationID(mps.AzureStackHCIMachinePool, vm)
+
+		return nil
+	}
+
+	operationResult, err := controllerutil.CreateOrUpdate(clusterScope.Context, r.Client, vm, mutateFn)
+	if telemetry.IsCRDUpdate(operationResult) {
+		operation, resourceType := telemetry.ConvertOperationResult(operationResult)
+		telemetry.RecordHybridAKSCRDChange(
+			mps.GetLogger(),
+			clusterScope.GetCustomResourceTypeWithName(),
+			fmt.Sprintf("%s/%s/%s", vm.TypeMeta.Kind, vm.ObjectMeta.Namespace, vm.ObjectMeta.Name),
+			operation,
+			resourceType,
+			nil,
+			err)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to create AzureStackHCIVirtualMachine %s", vm.Name)
+	}
+
+	return nil
+}
+
+// deleteVirtualMachine deletes a virtual machine of the pool
+func (r *AzureStackHCIMachinePoolReconciler) deleteVirtualMachine(mps *scope.MachinePoolScope, clusterScope *scope.ClusterScope, vm *infrav1.AzureStackHCIVirtualMachine) error {
+	if !vm.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	// update correlationId before deletion
+	infrav1util.CopyCorrelationID(mps.AzureStackHCIMachinePool, vm)
+	if err := r.Client.Update(clusterScope.Context, vm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to update AzureStackHCIVirtualMachine %s", vm.Name)
+		}
+	}
+	err := r.Client.Delete(clusterScope.Context, vm)
+	telemetry.RecordHybridAKSCRDChange(
+		clusterScope.GetLogger(),
+		clusterScope.GetCustomResourceTypeWithName(),
+		fmt.Sprintf("%s/%s/%s", vm.TypeMeta.Kind, vm.ObjectMeta.Namespace, vm.ObjectMeta.Name),
+		telemetry.Delete,
+		telemetry.CRD,
+		nil,
+		err)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete AzureStackHCIVirtualMachine %s", vm.Name)
+	}
+	return nil
+}
+
+// getVMImage returns the image of the pool template, or the default image of the Kubernetes version of the MachinePool
+func (r *AzureStackHCIMachinePoolReconciler) getVMImage(mps *scope.MachinePoolScope) (*infrav1.Image, error) {
+	image := mps.AzureStackHCIMachinePool.Spec.Template.Image
+
+	// Use custom image if provided
+	if image != nil && image.Name != nil && *image.Name != "" {
+		mps.Info("Using custom image name for machine pool", "imageName", image.Name)
+		return image, nil
+	}
+
+	osType := infrav1.OSTypeLinux
+	if image != nil {
+		osType = image.OSType
+	}
+	return azurestackhci.GetDefaultImage(osType, mps.KubernetesVersion())
+}
+
+// getVirtualMachinesForMachinePool returns the AzureStackHCIVirtualMachines of the machine pool. Virtual machines that
+// are being deleted are only included when requested.
+func (r *AzureStackHCIMachinePoolReconciler) getVirtualMachinesForMachinePool(mps *scope.MachinePoolScope, clusterScope *scope.ClusterScope, includeDeleted bool) ([]*infrav1.AzureStackHCIVirtualMachine, error) {
+	vmList := &infrav1.AzureStackHCIVirtualMachineList{}
+	if err := r.Client.List(
+		clusterScope.Context,
+		vmList,
+		client.InNamespace(clusterScope.Namespace()),
+		client.MatchingLabels{infrav1.MachinePoolLabel: mps.Name()}); err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*infrav1.AzureStackHCIVirtualMachine, 0, len(vmList.Items))
+	for idx := range vmList.Items {
+		vm := &vmList.Items[idx]
+		if includeDeleted || vm.GetDeletionTimestamp().IsZero() {
+			filtered = append(filtered, vm)
+		}
+	}
+
+	return filtered, nil
+}
+
+// updateReplicaStatus reports the virtual machines of the pool and the provider IDs of the running ones
+func (r *AzureStackHCIMachinePoolReconciler) updateReplicaStatus(mps *scope.MachinePoolScope, vmList []*infrav1.AzureStackHCIVirtualMachine, templateHash string) {
+	var readyReplicas, upToDateReplicas int32
+	providerIDs := []string{}
+	instances := make([]infrav1.AzureStackHCIMachinePoolInstance, 0, len(vmList))
+	for _, vm := range vmList {
+		instance := infrav1.AzureStackHCIMachinePoolInstance{
+			Name:     vm.Name,
+			VMState:  vm.Status.VMState,
+			UpToDate: isVirtualMachineUpToDate(vm, templateHash),
+		}
+		if isVirtualMachineRunning(vm) {
+			instance.ProviderID = fmt.Sprintf("moc://%s", vm.Name)
+			providerIDs = append(providerIDs, instance.ProviderID)
+			readyReplicas++
+		}
+		if instance.UpToDate {
+			upToDateReplicas++
+		}
+		instances = append(instances, instance)
+	}
+	sort.Strings(providerIDs)
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+
+	// Assume replicas will be under uniteger overflow for G115
+	mps.SetReplicas(int32(len(vmList)), readyReplicas, upToDateReplicas) //nolint
+	mps.SetInstances(instances)
+	mps.SetProviderIDList(providerIDs)
+
+	if outdated := int32(len(vmList)) - upToDateReplicas; outdated > 0 { //nolint
+		conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+			Type:    infrav1.MachinePoolReplicasUpToDateCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.MachinePoolRollingUpdateReason,
+			Message: fmt.Sprintf("%d replicas were not created from the current template", outdated),
+		})
+	} else {
+		conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+			Type:   infrav1.MachinePoolReplicasUpToDateCondition,
+			Status: metav1.ConditionTrue,
+			Reason: "AllReplicasUpToDate",
+		})
+	}
+
+	mps.Info("Updated replication status", "replicas", mps.GetReplicas(), "readyReplicas", readyReplicas, "upToDateReplicas", upToDateReplicas)
+}
+
+// selectVirtualMachinesForScaleDown determines the virtual machines to be deleted when scaling down. Virtual machines
+// annotated with the Cluster API delete-machine annotation are preferred, then virtual machines that aren't running,
+// then outdated virtual machines, and finally the oldest virtual machines.
+func selectVirtualMachinesForScaleDown(vmList []*infrav1.AzureStackHCIVirtualMachine, templateHash string, count int) []*infrav1.AzureStackHCIVirtualMachine {
+	candidates := make([]*infrav1.AzureStackHCIVirtualMachine, len(vmList))
+	copy(candidates, vmList)
+	sort.Sort(infrav1.VirtualMachinesByCreationTimestamp(candidates))
+
+	priority := func(vm *infrav1.AzureStackHCIVirtualMachine) int {
+		switch {
+		case metav1.HasAnnotation(vm.ObjectMeta, clusterv1.DeleteMachineAnnotation):
+			return 0
+		case !isVirtualMachineRunning(vm):
+			return 1
+		case !isVirtualMachineUpToDate(vm, templateHash):
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return priority(candidates[i]) < priority(candidates[j]) })
+
+	return candidates[:min(count, len(candidates))]
+}
+
+// reconcileInvalidConfiguration stops creating virtual machines from a template whose configuration MOC rejected. The
+// error is terminal, the machine pool is reconciled again once its template changes.
+func (r *AzureStackHCIMachinePoolReconciler) reconcileInvalidConfiguration(mps *scope.MachinePoolScope, vm *infrav1.AzureStackHCIVirtualMachine) (reconcile.Result, error) {
+	// the VM controller reports the class of the MOC error on the VMRunning condition
+	reason := conditions.GetReason(vm, infrav1.VMRunningCondition)
+	if reason == "" {
+		reason = infrav1.InvalidConfigurationReason
+	}
+	err := errors.Errorf("replica %s of the current template has an invalid configuration: %s", vm.Name, ptr.Deref(vm.Status.FailureMessage, reason))
+	if conditions.GetReason(mps.AzureStackHCIMachinePool, infrav1.MachinePoolReplicasReadyCondition) != reason {
+		r.Recorder.Eventf(mps.AzureStackHCIMachinePool, corev1.EventTypeWarning, "InvalidConfiguration",
+			"AzureStackHCIMachinePool %s creates no virtual machines until its template changes: %s", mps.Name(), err.Error())
+	}
+	conditions.Set(mps.AzureStackHCIMachinePool, metav1.Condition{
+		Type:    infrav1.MachinePoolReplicasReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: err.Error(),
+	})
+	return reconcileResultForError(azurestackhci.ErrorClass{Reason: reason, Terminal: true}, err)
+}
+
+// getFailedVirtualMachines returns the virtual machines that failed terminally
+func getFailedVirtualMachines(vmList []*infrav1.AzureStackHCIVirtualMachine) []*infrav1.AzureStackHCIVirtualMachine {
+	failed := []*infrav1.AzureStackHCIVirtualMachine{}
+	for _, vm := range vmList {
+		if vm.Status.FailureReason != nil {
+			failed = append(failed, vm)
+		}
+	}
+	return failed
+}
+
+// notRunningMessage summarizes why the virtual machines of the pool aren't running
+func notRunningMessage(vmList []*infrav1.AzureStackHCIVirtualMachine) string {
+	messages := []string{}
+	for _, vm := range vmList {
+		if isVirtualMachineRunning(vm) {
+			continue
+		}
+		if cond := conditions.Get(vm, infrav1.VMRunningCondition); cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", vm.Name, cond.Reason))
+		}
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
+
+// isVirtualMachineRunning returns true if the virtual machine was provisioned successfully
+func isVirtualMachineRunning(vm *infrav1.AzureStackHCIVirtualMachine) bool {
+	return vm.Status.VMState != nil && *vm.Status.VMState == infrav1.VMStateSucceeded
+}
+
+// isVirtualMachineUpToDate returns true if the virtual machine was created from the current template
+func isVirtualMachineUpToDate(vm *infrav1.AzureStackHCIVirtualMachine, templateHash string) bool {
+	return vm.Labels[infrav1.MachinePoolTemplateHashLabel] == templateHash
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/virtualmachines"
+	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// gpuResourceName is the extended resource the NVIDIA device plugin advertises the GPUs of a node with.
+	gpuResourceName = corev1.ResourceName("nvidia.com/gpu")
+)
+
+// AzureStackHCIMachineTemplateReconciler reconciles a AzureStackHCIMachineTemplate object
+type AzureStackHCIMachineTemplateReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+func (r *AzureStackHCIMachineTemplateReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		WithLogConstructor(r.ConstructLogger).
+		For(&infrav1.AzureStackHCIMachineTemplate{}).
+		Complete(r)
+}
+
+func (r *AzureStackHCIMachineTemplateReconciler) ConstructLogger(req *reconcile.Request) logr.Logger {
+	log := r.Log.WithName("")
+	if req == nil {
+		return log
+	}
+	log = log.WithValues("azureStackHCIMachineTemplate", req.NamespacedName)
+	cxt := context.Background()
+	azureStackHCIMachineTemplate := &infrav1.AzureStackHCIMachineTemplate{}
+	err := r.Get(cxt, req.NamespacedName, azureStackHCIMachineTemplate)
+	if err != nil {
+		log.Error(err, "failed to get azureStackHCIMachineTemplate")
+		return log
+	}
+	return log.WithValues("operationId", azureStackHCIMachineTemplate.GetAnnotations()[infrav1.AzureOperationIDAnnotationKey],
+		"correlationId", azureStackHCIMachineTemplate.GetAnnotations()[infrav1.AzureCorrelationIDAnnotationKey])
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhcimachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhcimachinetemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+
+// Reconcile publishes the capacity and node info of the machines created from an AzureStackHCIMachineTemplate,
+// which the cluster autoscaler needs to scale a MachineDeployment from zero.
+func (r *AzureStackHCIMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	logger := r.Log.WithValues("azureStackHCIMachineTemplate", req.NamespacedName, "reconcileID", infrav1util.GetReconcileID(ctx))
+	logger.Info("Attempt to reconcile resource")
+
+	// Fetch the AzureStackHCIMachineTemplate resource.
+	azureStackHCIMachineTemplate := &infrav1.AzureStackHCIMachineTemplate{}
+	err := r.Get(ctx, req.NamespacedName, azureStackHCIMachineTemplate)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !azureStackHCIMachineTemplate.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(azureStackHCIMachineTemplate, r.Client)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+
+	// Always patch the AzureStackHCIMachineTemplate when exiting this function so we can persist the status.
+	defer func() {
+		if err := patchHelper.Patch(ctx, azureStackHCIMachineTemplate,
+			patch.WithOwnedConditions{Conditions: []string{infrav1.MachineTemplateCapacityResolvedCondition}}); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	machineSpec := azureStackHCIMachineTemplate.Spec.Template.Spec
+	azureStackHCIMachineTemplate.Status.NodeInfo = machineTemplateNodeInfo(machineSpec)
+
+	capacity, err := machineTemplateCapacity(machineSpec)
+	if err != nil {
+		logger.Info("Unable to resolve the capacity of the template", "vmSize", machineSpec.VMSize, "error", err.Error())
+		r.Recorder.Eventf(azureStackHCIMachineTemplate, corev1.EventTypeWarning, infrav1.UnknownVMSizeReason, err.Error())
+		azureStackHCIMachineTemplate.Status.Capacity = nil
+		conditions.Set(azureStackHCIMachineTemplate, metav1.Condition{
+			Type:    infrav1.MachineTemplateCapacityResolvedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.UnknownVMSizeReason,
+			Message: err.Error(),
+		})
+		// the spec is immutable, there is nothing to retry until the template is replaced
+		return reconcile.Result{}, nil
+	}
+
+	azureStackHCIMachineTemplate.Status.Capacity = capacity
+	conditions.Set(azureStackHCIMachineTemplate, metav1.Condition{
+		Type:   infrav1.MachineTemplateCapacityResolvedCondition,
+		Status: metav1.ConditionTrue,
+		Reason: infrav1.InfrastructureReadyReason,
+	})
+	return reconcile.Result{}, nil
+}
+
+// machineTemplateCapacity returns the cpu, memory and GPUs of the VM size of the machine spec.
+// GpuCount overrides the GPUs that come with the VM size. Custom VM sizes take their cpu and memory from CustomSize.
+func machineTemplateCapacity(machineSpec infrav1.AzureStackHCIMachineSpec) (corev1.ResourceList, error) {
+	size, err := virtualmachines.GetSize(machineSpec.VMSize, machineSpec.CustomSize)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewQuantity(int64(size.CpuCount), resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(int64(size.MemoryMB)*1024*1024, resource.BinarySI),
+	}
+
+	gpuCount := int64(size.GpuCount)
+	if machineSpec.GpuCount > 0 {
+		gpuCount = int64(machineSpec.GpuCount)
+	}
+	if gpuCount > 0 {
+		capacity[gpuResourceName] = *resource.NewQuantity(gpuCount, resource.DecimalSI)
+	}
+	return capacity, nil
+}
+
+// machineTemplateNodeInfo returns the architecture and operating system of the nodes created from the machine spec.
+func machineTemplateNodeInfo(machineSpec infrav1.AzureStackHCIMachineSpec) *infrav1.NodeInfo {
+	operatingSystem := "linux"
+	if machineSpec.Image != nil && (machineSpec.Image.OSType == infrav1.OSTypeWindows || machineSpec.Image.OSType == infrav1.OSTypeWindows2022) {
+		operatingSystem = "windows"
+	}
+	return &infrav1.NodeInfo{
+		// MOC only runs on amd64 hosts
+		Architecture:    infrav1.ArchitectureAmd64,
+		OperatingSystem: operatingSystem,
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+)
+
+// TestMachineTemplateCapacity verifies that the capacity is resolved from the MOC VM size catalog, or from the custom
+// size of Custom VM sizes, and that GpuCount overrides the GPUs of the VM size.
+func TestMachineTemplateCapacity(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         infrav1.AzureStackHCIMachineSpec
+		wantCapacity corev1.ResourceList
+		wantErr      bool
+	}{
+		{
+			name: "vm size without gpus",
+			spec: infrav1.AzureStackHCIMachineSpec{VMSize: "Standard_A4_v2"},
+			wantCapacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+		{
+			name: "gpu count",
+			spec: infrav1.AzureStackHCIMachineSpec{VMSize: "Standard_D4s_v3", GpuCount: 2},
+			wantCapacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+				gpuResourceName:       resource.MustParse("2"),
+			},
+		},
+		{
+			name: "vm size with gpus",
+			spec: infrav1.AzureStackHCIMachineSpec{VMSize: "Standard_NK6"},
+			wantCapacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("6"),
+				corev1.ResourceMemory: resource.MustParse("12Gi"),
+				gpuResourceName:       resource.MustParse("1"),
+			},
+		},
+		{
+			name: "custom vm size",
+			spec: infrav1.AzureStackHCIMachineSpec{
+				VMSize:     "Custom",
+				CustomSize: &infrav1.VMCustomSize{CpuCount: 6, MemoryMB: 12288},
+				GpuCount:   1,
+			},
+			wantCapacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("6"),
+				corev1.ResourceMemory: resource.MustParse("12Gi"),
+				gpuResourceName:       resource.MustParse("1"),
+			},
+		},
+		{
+			name:    "custom vm size without custom size",
+			spec:    infrav1.AzureStackHCIMachineSpec{VMSize: "Custom"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown vm size",
+			spec:    infrav1.AzureStackHCIMachineSpec{VMSize: "Standard_Unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			capacity, err := machineTemplateCapacity(tc.spec)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(capacity).To(HaveLen(len(tc.wantCapacity)))
+			for name, want := range tc.wantCapacity {
+				got := capacity[name]
+				g.Expect(got.Cmp(want)).To(BeZero(), "%s: got %s, want %s", name, got.String(), want.String())
+			}
+		})
+	}
+}
+
+// TestMachineTemplateNodeInfo verifies that the operating system follows the OS type of the image.
+func TestMachineTemplateNodeInfo(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(machineTemplateNodeInfo(infrav1.AzureStackHCIMachineSpec{})).To(Equal(&infrav1.NodeInfo{
+		Architecture:    infrav1.ArchitectureAmd64,
+		OperatingSystem: "linux",
+	}))
+	g.Expect(machineTemplateNodeInfo(infrav1.AzureStackHCIMachineSpec{Image: &infrav1.Image{OSType: infrav1.OSTypeWindows2022}})).To(Equal(&infrav1.NodeInfo{
+		Architecture:    infrav1.ArchitectureAmd64,
+		OperatingSystem: "windows",
+	}))
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/virtualmachines"
+	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	v1beta1conditions "sigs.k8s.io/cluster-api/util/conditions/deprecated/v1beta1"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/paused"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AzureStackHCIRemediationReconciler reconciles a AzureStackHCIRemediation object
+type AzureStackHCIRemediationReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+func (r *AzureStackHCIRemediationReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	clusterToAzureStackHCIRemediations, err := util.ClusterToTypedObjectsMapper(mgr.GetClient(), &infrav1.AzureStackHCIRemediationList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureStackHCIRemediation{}).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(clusterToAzureStackHCIRemediations),
+			builder.WithPredicates(predicates.ClusterPausedTransitions(mgr.GetScheme(), r.Log)),
+		).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhciremediations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhciremediations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+
+// Reconcile remediates the unhealthy Machine an AzureStackHCIRemediation was created for by a MachineHealthCheck.
+// The MachineHealthCheck deletes the AzureStackHCIRemediation once the Machine is healthy again.
+func (r *AzureStackHCIRemediationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	logger := r.Log.WithValues("azureStackHCIRemediation", req.NamespacedName, "reconcileID", infrav1util.GetReconcileID(ctx))
+	logger.Info("Attempt to reconcile resource")
+
+	// Fetch the AzureStackHCIRemediation resource.
+	azureStackHCIRemediation := &infrav1.AzureStackHCIRemediation{}
+	err := r.Get(ctx, req.NamespacedName, azureStackHCIRemediation)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Fetch the Machine.
+	machine, err := util.GetOwnerMachine(ctx, r.Client, azureStackHCIRemediation.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if machine == nil {
+		logger.Info("MachineHealthCheck Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	logger = logger.WithValues("machine", machine.Name)
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
+	if err != nil {
+		logger.Info("Machine is missing cluster label or cluster does not exist")
+		return reconcile.Result{}, nil
+	}
+
+	logger = logger.WithValues("cluster", cluster.Name)
+
+	// Return early if the object or Cluster is paused.
+	if isPaused, requeue, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, azureStackHCIRemediation); err != nil || isPaused || requeue {
+		return reconcile.Result{}, err
+	}
+
+	// Create the remediation scope.
+	remediationScope, err := scope.NewRemediationScope(scope.RemediationScopeParams{
+		Client:                   r.Client,
+		Logger:                   &logger,
+		AzureStackHCIRemediation: azureStackHCIRemediation,
+		Machine:                  machine,
+	})
+	if err != nil {
+		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	// Always close the scope when exiting this function so we can persist any AzureStackHCIRemediation changes.
+	defer func() {
+		if err := remediationScope.Close(); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	// Handle deleted remediations.
+	if !azureStackHCIRemediation.ObjectMeta.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	azureStackHCICluster := &infrav1.AzureStackHCICluster{}
+	azureStackHCIClusterName := client.ObjectKey{
+		Namespace: azureStackHCIRemediation.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, azureStackHCIClusterName, azureStackHCICluster); err != nil {
+		logger.Info("AzureStackHCICluster is not available yet")
+		r.setWaitingForMachine(remediationScope, "AzureStackHCICluster is not available yet")
+		return reconcile.Result{}, nil
+	}
+
+	azureStackHCIMachine := &infrav1.AzureStackHCIMachine{}
+	azureStackHCIMachineName := client.ObjectKey{
+		Namespace: machine.Namespace,
+		Name:      machine.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, azureStackHCIMachineName, azureStackHCIMachine); err != nil {
+		logger.Info("AzureStackHCIMachine is not available yet")
+		r.setWaitingForMachine(remediationScope, "AzureStackHCIMachine is not available yet")
+		return reconcile.Result{}, nil
+	}
+	remediationScope.AzureStackHCIMachine = azureStackHCIMachine
+
+	// Create the cluster scope.
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client:               r.Client,
+		Logger:               &logger,
+		Cluster:              cluster,
+		AzureStackHCICluster: azureStackHCICluster,
+	})
+	if err != nil {
+		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
+	}
+
+	return r.reconcileNormal(remediationScope, clusterScope)
+}
+
+// reconcileNormal tries the remediation steps in order, each up to the retry limit, and waits for the Machine to become
+// healthy after every try. The owner of the Machine is asked to replace it once all steps failed to recover it.
+func (r *AzureStackHCIRemediationReconciler) reconcileNormal(remediationScope *scope.RemediationScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
+	remediationScope.Info("Reconciling AzureStackHCIRemediation")
+
+	if !remediationScope.Machine.DeletionTimestamp.IsZero() {
+		remediationScope.Info("Machine is being deleted, skipping remediation")
+		return reconcile.Result{}, nil
+	}
+
+	step := remediationScope.Step()
+	if step == infrav1.RemediationStepReplace {
+		return reconcile.Result{}, r.requestReplacement(remediationScope)
+	}
+
+	// give the machine time to recover from the last try
+	if lastRemediated := remediationScope.LastRemediated(); lastRemediated != nil {
+		if remaining := remediationScope.Timeout() - time.Since(lastRemediated.Time); remaining > 0 {
+			return reconcile.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
+	if remediationScope.RetryCount() >= remediationScope.RetryLimit() {
+		next := remediationScope.NextStep()
+		r.Recorder.Eventf(remediationScope.AzureStackHCIRemediation, corev1.EventTypeWarning, "RemediationStepExhausted",
+			"Machine %s/%s did not recover after %s, continuing with %s", remediationScope.Namespace(), remediationScope.Machine.Name, step, next)
+		return r.reconcileNormal(remediationScope, clusterScope)
+	}
+
+	vmSpec := &virtualmachines.Spec{
+		Name: remediationScope.AzureStackHCIMachine.Name,
+	}
+	if image := remediationScope.AzureStackHCIMachine.Spec.Image; image != nil {
+		vmSpec.Image = *image
+	}
+
+	vmSvc := virtualmachines.NewService(clusterScope)
+	switch step {
+	case infrav1.RemediationStepSoftReboot:
+		err := vmSvc.Restart(clusterScope.Context, vmSpec)
+		return r.recordRemediation(remediationScope, step, err)
+	case infrav1.RemediationStepHardPowerCycle:
+		err := vmSvc.PowerCycle(clusterScope.Context, vmSpec)
+		return r.recordRemediation(remediationScope, step, err)
+	default:
+		return reconcile.Result{}, errors.Errorf("unknown remediation step %q", step)
+	}
+}
+
+// recordRemediation records a try of the remediation step. A failed try counts against the retry limit, so that a
+// guest agent that doesn't respond to a soft reboot leads to a hard power cycle without waiting for the timeout. A step
+// that MOC rejects as invalid would fail the same way on every try, so the next step is taken right away.
+func (r *AzureStackHCIRemediationReconciler) recordRemediation(remediationScope *scope.RemediationScope, step infrav1.RemediationStep, err error) (reconcile.Result, error) {
+	remediationScope.SetRemediated(err == nil)
+	if err != nil {
+		class := azurestackhci.ClassifyError(err, infrav1.RemediationStepFailedReason)
+		r.Recorder.Eventf(remediationScope.AzureStackHCIRemediation, corev1.EventTypeWarning, "FailureRemediateMachine",
+			"Failed %s of Machine %s/%s: %s", step, remediationScope.Namespace(), remediationScope.Machine.Name, err.Error())
+		conditions.Set(remediationScope.AzureStackHCIRemediation, metav1.Condition{
+			Type:    infrav1.RemediationInProgressCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  class.Reason,
+			Message: err.Error(),
+		})
+		if class.Terminal {
+			next := remediationScope.NextStep()
+			r.Recorder.Eventf(remediationScope.AzureStackHCIRemediation, corev1.EventTypeWarning, "RemediationStepExhausted",
+				"Machine %s/%s can't be remediated with %s, continuing with %s", remediationScope.Namespace(), remediationScope.Machine.Name, step, next)
+			return reconcile.Result{RequeueAfter: 20 * time.Second}, nil
+		}
+		if class.RequeueAfter == 0 {
+			class.RequeueAfter = 20 * time.Second
+		}
+		return reconcileResultForError(class, err)
+	}
+
+	r.Recorder.Eventf(remediationScope.AzureStackHCIRemediation, corev1.EventTypeNormal, "RemediatingMachine",
+		"Applied %s to Machine %s/%s, attempt %d of %d", step, remediationScope.Namespace(), remediationScope.Machine.Name,
+		remediationScope.RetryCount(), remediationScope.RetryLimit())
+	conditions.Set(remediationScope.AzureStackHCIRemediation, metav1.Condition{
+		Type:    infrav1.RemediationInProgressCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  string(step),
+		Message: fmt.Sprintf("Waiting up to %s for the Machine to become healthy", remediationScope.Timeout()),
+	})
+	return reconcile.Result{RequeueAfter: remediationScope.Timeout()}, nil
+}
+
+// requestReplacement asks the owner of the Machine, a MachineSet or a control plane, to replace it. The owner remediates
+// Machines with a false OwnerRemediated condition, which the MachineHealthCheck only sets without external remediation.
+func (r *AzureStackHCIRemediationReconciler) requestReplacement(remediationScope *scope.RemediationScope) error {
+	conditions.Set(remediationScope.AzureStackHCIRemediation, metav1.Condition{
+		Type:    infrav1.RemediationInProgressCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  infrav1.WaitingForReplacementReason,
+		Message: "Remediation steps failed to recover the Machine",
+	})
+
+	machine := remediationScope.Machine
+	if conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+	conditions.Set(machine, metav1.Condition{
+		Type:    clusterv1.MachineOwnerRemediatedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  clusterv1.MachineOwnerRemediatedWaitingForRemediationReason,
+		Message: "Remediation steps failed to recover the Machine",
+	})
+	v1beta1conditions.MarkFalse(machine, clusterv1.MachineOwnerRemediatedV1Beta1Condition, clusterv1.WaitingForRemediationV1Beta1Reason, clusterv1.ConditionSeverityWarning, "")
+	if err := patchHelper.Patch(remediationScope.Context, machine,
+		patch.WithOwnedConditions{Conditions: []string{clusterv1.MachineOwnerRemediatedCondition}},
+		patch.WithOwnedV1Beta1Conditions{Conditions: []clusterv1.ConditionType{clusterv1.MachineOwnerRemediatedV1Beta1Condition}},
+	); err != nil {
+		return errors.Wrapf(err, "failed to request replacement of Machine %s/%s", machine.Namespace, machine.Name)
+	}
+
+	r.Recorder.Eventf(remediationScope.AzureStackHCIRemediation, corev1.EventTypeWarning, "RequestedMachineReplacement",
+		"Machine %s/%s did not recover, requested its replacement", machine.Namespace, machine.Name)
+	return nil
+}
+
+// setWaitingForMachine reports that the remediation can't start yet.
+func (r *AzureStackHCIRemediationReconciler) setWaitingForMachine(remediationScope *scope.RemediationScope, message string) {
+	conditions.Set(remediationScope.AzureStackHCIRemediation, metav1.Condition{
+		Type:    infrav1.RemediationInProgressCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrav1.WaitingForMachineReason,
+		Message: message,
+	})
+}
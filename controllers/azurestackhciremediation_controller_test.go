@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	mocerrors "github.com/microsoft/moc/pkg/errors"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+)
+
+func newTestRemediationReconciler(g *WithT, machine *clusterv1.Machine) (*AzureStackHCIRemediationReconciler, *scope.RemediationScope) {
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine).WithStatusSubresource(machine).Build()
+
+	r := &AzureStackHCIRemediationReconciler{
+		Client:   c,
+		Log:      logr.Discard(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+	remediationScope := &scope.RemediationScope{
+		Logger:  logr.Discard(),
+		Context: context.Background(),
+		Machine: machine,
+		AzureStackHCIRemediation: &infrav1.AzureStackHCIRemediation{
+			ObjectMeta: metav1.ObjectMeta{Name: machine.Name, Namespace: machine.Namespace},
+		},
+	}
+	return r, remediationScope
+}
+
+func newTestRemediationMachine() *clusterv1.Machine {
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+	}
+}
+
+func TestRecordRemediation(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		wantReason       string
+		wantRequeueAfter time.Duration
+		wantRemediated   bool
+		wantStep         infrav1.RemediationStep
+		wantRetries      int32
+	}{
+		{
+			name:             "applied",
+			wantReason:       string(infrav1.RemediationStepSoftReboot),
+			wantRequeueAfter: scope.DefaultRemediationTimeout,
+			wantRemediated:   true,
+			wantStep:         infrav1.RemediationStepSoftReboot,
+			wantRetries:      1,
+		},
+		{
+			name:             "failed",
+			err:              errors.New("guest agent not responding"),
+			wantReason:       infrav1.RemediationStepFailedReason,
+			wantRequeueAfter: 20 * time.Second,
+			wantStep:         infrav1.RemediationStepSoftReboot,
+			wantRetries:      1,
+		},
+		{
+			name:             "MOC unreachable",
+			err:              status.Error(codes.Unavailable, "connection refused"),
+			wantReason:       infrav1.MOCUnreachableReason,
+			wantRequeueAfter: azurestackhci.DefaultMOCUnreachableRetryInterval,
+			wantStep:         infrav1.RemediationStepSoftReboot,
+			wantRetries:      1,
+		},
+		{
+			name:             "rejected by MOC",
+			err:              mocerrors.InvalidInput,
+			wantReason:       infrav1.InvalidConfigurationReason,
+			wantRequeueAfter: 20 * time.Second,
+			wantStep:         infrav1.RemediationStepHardPowerCycle,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r, remediationScope := newTestRemediationReconciler(g, newTestRemediationMachine())
+
+			result, err := r.recordRemediation(remediationScope, infrav1.RemediationStepSoftReboot, tc.err)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(Equal(tc.wantRequeueAfter))
+			g.Expect(remediationScope.Step()).To(Equal(tc.wantStep))
+			g.Expect(remediationScope.RetryCount()).To(Equal(tc.wantRetries))
+			g.Expect(remediationScope.LastRemediated() != nil).To(Equal(tc.wantRemediated))
+
+			condition := conditions.Get(remediationScope.AzureStackHCIRemediation, infrav1.RemediationInProgressCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			g.Expect(condition.Reason).To(Equal(tc.wantReason))
+		})
+	}
+}
+
+// TestReconcileRemediation covers the paths of reconcileNormal that don't reach MOC: waiting for the Machine to
+// recover, exhausting the retries of the last step and skipping Machines that are being deleted.
+func TestReconcileRemediation(t *testing.T) {
+	tests := []struct {
+		name             string
+		strategy         *infrav1.RemediationStrategy
+		status           infrav1.AzureStackHCIRemediationStatus
+		deleting         bool
+		wantStep         infrav1.RemediationStep
+		wantRequeue      bool
+		wantReplacement  bool
+		wantRetryCount   int32
+		wantConditionSet bool
+	}{
+		{
+			name: "waits for the machine to recover",
+			status: infrav1.AzureStackHCIRemediationStatus{
+				Step:           infrav1.RemediationStepSoftReboot,
+				RetryCount:     1,
+				LastRemediated: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+			},
+			wantStep:       infrav1.RemediationStepSoftReboot,
+			wantRequeue:    true,
+			wantRetryCount: 1,
+		},
+		{
+			name: "last step exhausted",
+			status: infrav1.AzureStackHCIRemediationStatus{
+				Step:           infrav1.RemediationStepHardPowerCycle,
+				RetryCount:     1,
+				LastRemediated: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			},
+			wantStep:         infrav1.RemediationStepReplace,
+			wantReplacement:  true,
+			wantConditionSet: true,
+		},
+		{
+			name: "only step exhausted after failed tries",
+			strategy: &infrav1.RemediationStrategy{
+				Steps: []infrav1.RemediationStep{infrav1.RemediationStepSoftReboot},
+			},
+			status: infrav1.AzureStackHCIRemediationStatus{
+				Step:       infrav1.RemediationStepSoftReboot,
+				RetryCount: 1,
+			},
+			wantStep:         infrav1.RemediationStepReplace,
+			wantReplacement:  true,
+			wantConditionSet: true,
+		},
+		{
+			name:     "machine being deleted",
+			status:   infrav1.AzureStackHCIRemediationStatus{Step: infrav1.RemediationStepHardPowerCycle, RetryCount: 1},
+			deleting: true,
+			wantStep: infrav1.RemediationStepHardPowerCycle,
+			// the retry count is left as is, there is nothing to remediate anymore
+			wantRetryCount: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machine := newTestRemediationMachine()
+			if tc.deleting {
+				machine.Finalizers = []string{clusterv1.MachineFinalizer}
+				machine.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+			r, remediationScope := newTestRemediationReconciler(g, machine)
+			remediationScope.AzureStackHCIRemediation.Spec.Strategy = tc.strategy
+			remediationScope.AzureStackHCIRemediation.Status = tc.status
+
+			// the cluster scope is only needed to reach MOC, which none of these cases do
+			result, err := r.reconcileNormal(remediationScope, nil)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter > 0).To(Equal(tc.wantRequeue))
+			g.Expect(result.RequeueAfter).To(BeNumerically("<=", remediationScope.Timeout()))
+			g.Expect(remediationScope.Step()).To(Equal(tc.wantStep))
+			g.Expect(remediationScope.RetryCount()).To(Equal(tc.wantRetryCount))
+
+			stored := &clusterv1.Machine{}
+			g.Expect(r.Get(context.Background(), client.ObjectKeyFromObject(machine), stored)).To(Succeed())
+			g.Expect(conditions.IsFalse(stored, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(tc.wantConditionSet))
+
+			condition := conditions.Get(remediationScope.AzureStackHCIRemediation, infrav1.RemediationInProgressCondition)
+			if tc.wantReplacement {
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Reason).To(Equal(infrav1.WaitingForReplacementReason))
+			} else {
+				g.Expect(condition).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestRequestReplacement(t *testing.T) {
+	g := NewWithT(t)
+	machine := newTestRemediationMachine()
+	r, remediationScope := newTestRemediationReconciler(g, machine)
+	recorder := r.Recorder.(*record.FakeRecorder)
+
+	g.Expect(r.requestReplacement(remediationScope)).To(Succeed())
+
+	stored := &clusterv1.Machine{}
+	g.Expect(r.Get(context.Background(), client.ObjectKeyFromObject(machine), stored)).To(Succeed())
+	condition := conditions.Get(stored, clusterv1.MachineOwnerRemediatedCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(clusterv1.MachineOwnerRemediatedWaitingForRemediationReason))
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(ContainSubstring("RequestedMachineReplacement"))
+
+	// the owner was already asked, the Machine isn't patched again
+	g.Expect(r.requestReplacement(remediationScope)).To(Succeed())
+	g.Expect(recorder.Events).To(BeEmpty())
+}
@@ -20,12 +20,12 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
 	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
-	mocerrors "github.com/microsoft/moc/pkg/errors"
-	moccodes "github.com/microsoft/moc/pkg/errors/codes"
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,11 +34,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/paused"
+	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
@@ -58,6 +63,11 @@ func (r *AzureStackHCIVirtualMachineReconciler) SetupWithManager(mgr ctrl.Manage
 		WithOptions(options).
 		WithLogConstructor(r.ConstructLogger).
 		For(&infrav1.AzureStackHCIVirtualMachine{}).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(r.ClusterToAzureStackHCIVirtualMachines),
+			builder.WithPredicates(predicates.ClusterPausedTransitions(mgr.GetScheme(), r.Log)),
+		).
 		Complete(r)
 }
 
@@ -80,6 +90,7 @@ func (r *AzureStackHCIVirtualMachineReconciler) ConstructLogger(req *reconcile.R
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhcivirtualmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhcivirtualmachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azurestackhciimages,verbs=get;list;watch
 
 // Reconcile reacts to some event on the kubernetes object that the controller has registered to handle
 func (r *AzureStackHCIVirtualMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -98,6 +109,24 @@ func (r *AzureStackHCIVirtualMachineReconciler) Reconcile(ctx context.Context, r
 
 	logger = logger.WithValues("operationId", azureStackHCIVirtualMachine.GetAnnotations()[infrav1.AzureOperationIDAnnotationKey], "correlationId", azureStackHCIVirtualMachine.GetAnnotations()[infrav1.AzureCorrelationIDAnnotationKey])
 
+	// Fetch the Cluster, the virtual machine is only paused by its own annotation while the Cluster does not exist.
+	cluster := &clusterv1.Cluster{}
+	clusterName := client.ObjectKey{
+		Namespace: azureStackHCIVirtualMachine.Namespace,
+		Name:      azureStackHCIVirtualMachine.Spec.ClusterName,
+	}
+	if err := r.Client.Get(ctx, clusterName, cluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		cluster = nil
+	}
+
+	// Return early if the object or Cluster is paused.
+	if isPaused, requeue, err := paused.EnsurePausedCondition(ctx, r.Client, cluster, azureStackHCIVirtualMachine); err != nil || isPaused || requeue {
+		return reconcile.Result{}, err
+	}
+
 	// Create the machine scope
 	virtualMachineScope, err := scope.NewVirtualMachineScope(scope.VirtualMachineScopeParams{
 		Logger:                      &logger,
@@ -154,11 +183,26 @@ func (r *AzureStackHCIVirtualMachineReconciler) reconcileNormal(virtualMachineSc
 
 	ams := newAzureStackHCIVirtualMachineService(virtualMachineScope)
 
+	// Wait for the gallery image to be imported instead of failing to create the virtual machine.
+	if !virtualMachineScope.AzureStackHCIVirtualMachine.Status.Ready {
+		ready, err := r.isImageReady(virtualMachineScope)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !ready {
+			return reconcile.Result{RequeueAfter: imageImportPollInterval}, nil
+		}
+	}
+
 	// Get or create the virtual machine.
-	vm, err := r.getOrCreate(virtualMachineScope, ams)
+	vm, requeueAfter, err := r.getOrCreate(virtualMachineScope, ams)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	if vm == nil {
+		// The gallery image is missing, no host has the capacity for the VM yet or MOC is unreachable.
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
+	}
 
 	/*
 		// right now validateUpdate seems to be a no-op so skipping this logic for now
@@ -171,11 +215,29 @@ func (r *AzureStackHCIVirtualMachineReconciler) reconcileNormal(virtualMachineSc
 
 	// Proceed to reconcile the AzureStackHCIVirtualMachine state.
 	virtualMachineScope.SetVMState(vm.State)
+	if vm.Host != "" {
+		virtualMachineScope.SetHost(vm.Host)
+	}
+
+	// Report the addresses of both families on dual-stack networks.
+	addresses, err := ams.GetAddresses()
+	if err != nil {
+		virtualMachineScope.Error(err, "failed to get addresses of vm", "vmName", virtualMachineScope.Name())
+	} else {
+		virtualMachineScope.SetAddresses(addresses)
+	}
+
+	// Apply requested power operations and the desired power state.
+	result, err := r.reconcilePowerState(virtualMachineScope, ams, vm)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
 	switch vm.State {
 	case infrav1.VMStateSucceeded:
 		virtualMachineScope.Info("Machine VM is running", "name", virtualMachineScope.Name())
 		virtualMachineScope.SetReady()
+		virtualMachineScope.ResetCapacityRetries()
 		conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
 			Type:   infrav1.VMRunningCondition,
 			Status: metav1.ConditionTrue,
@@ -206,7 +268,105 @@ func (r *AzureStackHCIVirtualMachineReconciler) reconcileNormal(virtualMachineSc
 		})
 	}
 
-	return reconcile.Result{}, nil
+	return result, nil
+}
+
+// isImageReady returns false while the AzureStackHCIImage that imports the image of the virtual machine isn't ready.
+// Gallery images that aren't managed with an AzureStackHCIImage are expected to exist.
+func (r *AzureStackHCIVirtualMachineReconciler) isImageReady(virtualMachineScope *scope.VirtualMachineScope) (bool, error) {
+	image := virtualMachineScope.AzureStackHCIVirtualMachine.Spec.Image
+	if image == nil || image.Name == nil {
+		return true, nil
+	}
+
+	azureStackHCIImage, err := infrav1util.GetAzureStackHCIImageByGalleryImageName(virtualMachineScope.Context, r.Client, virtualMachineScope.Namespace(), *image.Name)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get AzureStackHCIImage of gallery image %s", *image.Name)
+	}
+	if azureStackHCIImage == nil || azureStackHCIImage.Status.Ready {
+		return true, nil
+	}
+
+	virtualMachineScope.Info("Waiting for AzureStackHCIImage to be imported", "image", azureStackHCIImage.Name, "galleryImage", *image.Name)
+	conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+		Type:    infrav1.VMRunningCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrav1.WaitingForImageReason,
+		Message: fmt.Sprintf("waiting for AzureStackHCIImage %s to import gallery image %s", azureStackHCIImage.Name, *image.Name),
+	})
+	return false, nil
+}
+
+// reconcileImage returns false when the gallery image of the virtual machine doesn't exist in MOC.
+// The name reported is the one the AzureStackHCIMachine resolved, which is the default image for
+// the Kubernetes version of the Machine when no custom image is set. The ImageAvailable condition is
+// left as is while MOC can't be queried. The image is given DefaultImageNotFoundTimeout to be imported,
+// after that the missing image is a terminal failure, like the PathNotFound errors of MOC.
+func (r *AzureStackHCIVirtualMachineReconciler) reconcileImage(virtualMachineScope *scope.VirtualMachineScope, ams *azureStackHCIVirtualMachineService) (bool, error) {
+	found, err := ams.ImageExists()
+	if err != nil {
+		return false, err
+	}
+	if found {
+		conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+			Type:   infrav1.ImageAvailableCondition,
+			Status: metav1.ConditionTrue,
+			Reason: infrav1.ImageFoundReason,
+		})
+		return true, nil
+	}
+
+	image := virtualMachineScope.AzureStackHCIVirtualMachine.Spec.Image
+	message := fmt.Sprintf("gallery image %s (%s) was not found in location %s, import it or create an AzureStackHCIImage for it",
+		*image.Name, image.OSType, virtualMachineScope.Location())
+	if !conditions.IsFalse(virtualMachineScope.AzureStackHCIVirtualMachine, infrav1.ImageAvailableCondition) {
+		r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeWarning, "ImageNotFound", message)
+	}
+	conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+		Type:    infrav1.ImageAvailableCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrav1.ImageNotFoundReason,
+		Message: message,
+	})
+	setVMProvisionFailure(virtualMachineScope, infrav1.ImageNotFoundReason, message)
+
+	// the transition time is kept while the image stays missing, it tells since when
+	missingSince := conditions.Get(virtualMachineScope.AzureStackHCIVirtualMachine, infrav1.ImageAvailableCondition).LastTransitionTime
+	if time.Since(missingSince.Time) >= azurestackhci.DefaultImageNotFoundTimeout {
+		err := errors.Errorf("gallery image %s (%s) is still not found in location %s after %s",
+			*image.Name, image.OSType, virtualMachineScope.Location(), azurestackhci.DefaultImageNotFoundTimeout)
+		r.setVMTerminalFailure(virtualMachineScope, capierrors.InvalidConfigurationMachineError, infrav1.ImageNotFoundReason, err)
+		return false, reconcile.TerminalError(err)
+	}
+	virtualMachineScope.Info("Gallery image not found, waiting before creating VM", "image", *image.Name, "missingSince", missingSince)
+	return false, nil
+}
+
+// reconcileCapacity returns how long to wait before creating the virtual machine while no host has the capacity
+// for it. The capacity is checked with an exponential backoff. Unlike creations that fail for lack of capacity, the
+// checks don't count towards DefaultMaxCapacityRetries, a virtual machine waits for capacity as long as it takes.
+func (r *AzureStackHCIVirtualMachineReconciler) reconcileCapacity(virtualMachineScope *scope.VirtualMachineScope, ams *azureStackHCIVirtualMachineService) (time.Duration, error) {
+	if delay := virtualMachineScope.CapacityCheckDelay(); delay > 0 {
+		virtualMachineScope.Info("Waiting before checking the capacity for VM again", "delay", delay)
+		return delay, nil
+	}
+
+	fits, reason, err := ams.Precheck()
+	if err != nil {
+		// The pre-check is best effort, a creation that fails for lack of capacity is backed off too.
+		virtualMachineScope.Error(err, "Unable to check the capacity for VM", "name", virtualMachineScope.Name())
+		return 0, nil
+	}
+	if fits {
+		return 0, nil
+	}
+
+	delay := virtualMachineScope.BackOffCapacityCheck(virtualMachineScope.AddCapacityCheck())
+	message := fmt.Sprintf("no host has the capacity for vm size %s, checking again in %s: %s",
+		virtualMachineScope.AzureStackHCIVirtualMachine.Spec.VMSize, delay, reason)
+	r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeWarning, "WaitingForCapacity", message)
+	setVMProvisionFailure(virtualMachineScope, infrav1.WaitingForCapacityReason, message)
+	return delay, nil
 }
 
 // setVMProvisionFailure records a terminal VM-provisioning failure on both the legacy
@@ -232,65 +392,92 @@ func setVMProvisionFailure(virtualMachineScope *scope.VirtualMachineScope, reaso
 	})
 }
 
-func (r *AzureStackHCIVirtualMachineReconciler) getOrCreate(virtualMachineScope *scope.VirtualMachineScope, ams *azureStackHCIVirtualMachineService) (*infrav1.VM, error) {
+// setVMTerminalFailure records a failure that retrying can't fix. The failure reason stops any further
+// reconciliation of the VM and is surfaced through the AzureStackHCIMachine, so that a MachineHealthCheck
+// can replace the Machine.
+func (r *AzureStackHCIVirtualMachineReconciler) setVMTerminalFailure(virtualMachineScope *scope.VirtualMachineScope, failure capierrors.MachineStatusError, reason string, err error) {
+	virtualMachineScope.SetFailureReason(failure)
+	virtualMachineScope.SetFailureMessage(err)
+	conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+		Type:    infrav1.TerminalFailureCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: err.Error(),
+	})
+	r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeWarning, "TerminalFailure",
+		"AzureStackHCIVirtualMachine %s/%s can't be provisioned: %s", virtualMachineScope.Namespace(), virtualMachineScope.Name(), err.Error())
+}
+
+// getOrCreate returns the VM, creating it if it doesn't exist. No VM is returned while the VM can't be created yet,
+// along with the delay before trying again.
+func (r *AzureStackHCIVirtualMachineReconciler) getOrCreate(virtualMachineScope *scope.VirtualMachineScope, ams *azureStackHCIVirtualMachineService) (*infrav1.VM, time.Duration, error) {
 	virtualMachineScope.Info("Attempting to find VM", "Name", virtualMachineScope.Name())
 	vm, err := r.findVM(virtualMachineScope, ams)
 	if err != nil {
+		class := azurestackhci.ClassifyError(err, infrav1.VMNotFoundReason)
 		wrappedErr := errors.Wrapf(err, "Failed to query for AzureStackHCIVirtualMachine %s/%s", virtualMachineScope.Namespace(), virtualMachineScope.Name())
 		r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeWarning, "FailureQueryForVM", wrappedErr.Error())
 		conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
 			Type:    infrav1.VMRunningCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  infrav1.VMNotFoundReason,
+			Reason:  class.Reason,
 			Message: err.Error(),
 		})
-		return nil, err
+		result, err := reconcileResultForError(class, err)
+		return nil, result.RequeueAfter, err
 	}
 
 	if vm == nil {
 		// Create a new AzureStackHCIVirtualMachine if we couldn't find a running VM.
+		// Resolve the image first so that no network interface or disk is left behind when it's missing.
+		found, err := r.reconcileImage(virtualMachineScope, ams)
+		if err != nil {
+			// a missing image that timed out is already terminal, other errors are those of MOC
+			result, err := reconcileResultForError(azurestackhci.ClassifyError(err, infrav1.VMProvisionFailedReason), err)
+			return nil, result.RequeueAfter, err
+		}
+		if !found {
+			return nil, imageImportPollInterval, nil
+		}
+
+		// Likewise, wait for a host to have the capacity for the VM.
+		if delay, err := r.reconcileCapacity(virtualMachineScope, ams); err != nil || delay > 0 {
+			return nil, delay, err
+		}
+
 		virtualMachineScope.Info("No VM found, creating VM", "Name", virtualMachineScope.Name())
 		vm, err = ams.Create()
 		if err != nil {
-			switch mocerrors.GetErrorCode(err) {
-			case mocerrors.OutOfMemory.Error():
-				setVMProvisionFailure(virtualMachineScope, infrav1.OutOfMemoryReason, err.Error())
-			case mocerrors.OutOfCapacity.Error():
-				setVMProvisionFailure(virtualMachineScope, infrav1.OutOfCapacityReason, err.Error())
-			case mocerrors.OutOfNodeCapacity.Error():
-				setVMProvisionFailure(virtualMachineScope, infrav1.OutOfNodeCapacityReason, err.Error())
-			case mocerrors.NotFound.Error(): // "NotFound"
-				fallthrough
-			// Internally, NotFound is a legacy error and returns the error string instead.
-			case moccodes.NotFound.String(): // "Not Found"
-				if mocerrors.IsPathNotFound(err) {
-					setVMProvisionFailure(virtualMachineScope, infrav1.PathNotFoundReason, err.Error())
+			class := azurestackhci.ClassifyError(err, infrav1.VMProvisionFailedReason)
+			setVMProvisionFailure(virtualMachineScope, class.Reason, err.Error())
+			var failure capierrors.MachineStatusError
+			if class.Terminal {
+				failure = capierrors.InvalidConfigurationMachineError
+			}
+			if class.Capacity {
+				// Capacity can be freed up by other workloads, so only give up after a number of attempts.
+				if retries := virtualMachineScope.AddCapacityRetry(); retries >= azurestackhci.DefaultMaxCapacityRetries {
+					failure = capierrors.InsufficientResourcesMachineError
+					class.Terminal = true
 				} else {
-					setVMProvisionFailure(virtualMachineScope, infrav1.NotFoundReason, err.Error())
-				}
-			default:
-				// MOC unreachable (gRPC codes.Unavailable — e.g. a DNS/transport dial failure to
-				// the MOC agent) lands in this generic bucket because GetErrorCode does not inspect
-				// gRPC status codes. It is deterministic at this call-site (we are dialing MOC), so
-				// surface a typed reason here instead of leaving it as a generic provisioning
-				// failure that downstream consumers would otherwise have to pattern-match from the
-				// error message.
-				reason := infrav1.VMProvisionFailedReason
-				if mocerrors.IsGRPCUnavailable(err) {
-					reason = infrav1.MOCUnreachableReason
+					class.RequeueAfter = virtualMachineScope.BackOffCapacityCheck(retries)
 				}
-				setVMProvisionFailure(virtualMachineScope, reason, err.Error())
+			}
+			if failure != "" {
+				r.setVMTerminalFailure(virtualMachineScope, failure, class.Reason, err)
 			}
 
 			wrappedErr := errors.Wrapf(err, "failed to create AzureStackHCIVirtualMachine")
 			r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeWarning, "FailureCreateVM", wrappedErr.Error())
 
-			return nil, wrappedErr
+			// a terminal failure isn't retried, the Machine is replaced
+			result, err := reconcileResultForError(class, wrappedErr)
+			return nil, result.RequeueAfter, err
 		}
 		r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeNormal, "SuccessfulCreateVM", "Success creating AzureStackHCIVirtualMachine %s/%s", virtualMachineScope.Namespace(), virtualMachineScope.Name())
 	}
 
-	return vm, nil
+	return vm, 0, nil
 }
 
 func (r *AzureStackHCIVirtualMachineReconciler) reconcileDelete(virtualMachineScope *scope.VirtualMachineScope) (_ reconcile.Result, reterr error) {
@@ -304,15 +491,16 @@ func (r *AzureStackHCIVirtualMachineReconciler) reconcileDelete(virtualMachineSc
 	})
 
 	if err := newAzureStackHCIVirtualMachineService(virtualMachineScope).Delete(); err != nil {
+		class := azurestackhci.ClassifyError(err, infrav1.DeletionFailedReason)
 		wrappedErr := errors.Wrapf(err, "error deleting AzureStackHCIVirtualMachine %s/%s", virtualMachineScope.Namespace(), virtualMachineScope.Name())
 		r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeWarning, "FailureDeleteVM", wrappedErr.Error())
 		conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
 			Type:    infrav1.VMRunningCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  infrav1.DeletionFailedReason,
+			Reason:  class.Reason,
 			Message: err.Error(),
 		})
-		return reconcile.Result{}, wrappedErr
+		return reconcileResultForError(class, wrappedErr)
 	}
 	r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeNormal, "SuccessfulDeleteVM", "Success deleting AzureStackHCIVirtualMachine %s/%s", virtualMachineScope.Namespace(), virtualMachineScope.Name())
 
@@ -327,3 +515,28 @@ func (r *AzureStackHCIVirtualMachineReconciler) validateUpdate(spec *infrav1.Azu
 	// TODO: Add comparison logic for immutable fields
 	return errs
 }
+
+// ClusterToAzureStackHCIVirtualMachines is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// of the AzureStackHCIVirtualMachines of a Cluster.
+func (r *AzureStackHCIVirtualMachineReconciler) ClusterToAzureStackHCIVirtualMachines(ctx context.Context, o client.Object) []ctrl.Request {
+	c, ok := o.(*clusterv1.Cluster)
+	if !ok {
+		r.Log.Error(errors.Errorf("expected a Cluster but got a %T", o), "failed to get AzureStackHCIVirtualMachines for Cluster")
+		return nil
+	}
+
+	vmList := &infrav1.AzureStackHCIVirtualMachineList{}
+	if err := r.List(ctx, vmList, client.InNamespace(c.Namespace)); err != nil {
+		r.Log.Error(err, "failed to list AzureStackHCIVirtualMachines", "Cluster", c.Name, "Namespace", c.Namespace)
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for _, vm := range vmList.Items {
+		if vm.Spec.ClusterName != c.Name {
+			continue
+		}
+		result = append(result, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: vm.Namespace, Name: vm.Name}})
+	}
+	return result
+}
@@ -17,15 +17,25 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
 )
 
@@ -58,3 +68,292 @@ func TestSetVMProvisionFailure(t *testing.T) {
 	g.Expect(ready.Reason).To(Equal(infrav1.OutOfCapacityReason))
 	g.Expect(ready.Message).To(Equal(message))
 }
+
+// TestValidatePowerOperation verifies that power operations which would be reverted right away to match
+// spec.powerState are rejected, as well as unknown operations.
+func TestValidatePowerOperation(t *testing.T) {
+	running := infrav1.VMPowerStateRunning
+	stopped := infrav1.VMPowerStateStopped
+	tests := []struct {
+		name      string
+		operation infrav1.VMPowerOperation
+		desired   *infrav1.VMPowerState
+		wantErr   bool
+	}{
+		{name: "restart without desired state", operation: infrav1.VMPowerOperationRestart},
+		{name: "shutdown without desired state", operation: infrav1.VMPowerOperationShutdown},
+		{name: "powercycle while running", operation: infrav1.VMPowerOperationPowerCycle, desired: &running},
+		{name: "start while stopped", operation: infrav1.VMPowerOperationStart, desired: &stopped, wantErr: true},
+		{name: "stop while running", operation: infrav1.VMPowerOperationStop, desired: &running, wantErr: true},
+		{name: "stop while stopped", operation: infrav1.VMPowerOperationStop, desired: &stopped},
+		{name: "unknown operation", operation: infrav1.VMPowerOperation("hibernate"), wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := validatePowerOperation(tc.operation, tc.desired)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+// fakeGalleryImagesService returns err from Get and records whether it was called.
+type fakeGalleryImagesService struct {
+	err    error
+	called bool
+}
+
+func (f *fakeGalleryImagesService) Get(ctx context.Context, spec interface{}) (interface{}, error) {
+	f.called = true
+	return nil, f.err
+}
+
+func (f *fakeGalleryImagesService) Reconcile(ctx context.Context, spec interface{}) error {
+	return nil
+}
+
+func (f *fakeGalleryImagesService) Delete(ctx context.Context, spec interface{}) error {
+	return nil
+}
+
+// TestReconcileImage verifies that a missing gallery image is reported on the ImageAvailable condition
+// instead of creating the network interface and the virtual machine, and that the virtual machine fails
+// terminally once the image is missing for longer than DefaultImageNotFoundTimeout.
+func TestReconcileImage(t *testing.T) {
+	imageNotFound := func(since time.Duration) metav1.Condition {
+		return metav1.Condition{
+			Type:               infrav1.ImageAvailableCondition,
+			Status:             metav1.ConditionFalse,
+			Reason:             infrav1.ImageNotFoundReason,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		image         *infrav1.Image
+		existing      []metav1.Condition
+		getErr        error
+		wantFound     bool
+		wantErr       bool
+		wantTerminal  bool
+		wantCalled    bool
+		wantCondition metav1.ConditionStatus
+		wantReason    string
+		wantEvents    int
+	}{
+		{
+			name:          "image found",
+			image:         &infrav1.Image{Name: ptr.To("Linux_k8s_1-30-4"), OSType: infrav1.OSTypeLinux},
+			wantFound:     true,
+			wantCalled:    true,
+			wantCondition: metav1.ConditionTrue,
+			wantReason:    infrav1.ImageFoundReason,
+		},
+		{
+			name:          "image imported after it was not found",
+			image:         &infrav1.Image{Name: ptr.To("Linux_k8s_1-30-4"), OSType: infrav1.OSTypeLinux},
+			existing:      []metav1.Condition{imageNotFound(time.Minute)},
+			wantFound:     true,
+			wantCalled:    true,
+			wantCondition: metav1.ConditionTrue,
+			wantReason:    infrav1.ImageFoundReason,
+		},
+		{
+			name:          "image not found",
+			image:         &infrav1.Image{Name: ptr.To("Linux_k8s_1-30-4"), OSType: infrav1.OSTypeLinux},
+			getErr:        status.Error(codes.NotFound, "gallery image Linux_k8s_1-30-4 not found"),
+			wantCalled:    true,
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.ImageNotFoundReason,
+			wantEvents:    1,
+		},
+		{
+			name:          "image still not found",
+			image:         &infrav1.Image{Name: ptr.To("Linux_k8s_1-30-4"), OSType: infrav1.OSTypeLinux},
+			existing:      []metav1.Condition{imageNotFound(time.Minute)},
+			getErr:        status.Error(codes.NotFound, "gallery image Linux_k8s_1-30-4 not found"),
+			wantCalled:    true,
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.ImageNotFoundReason,
+		},
+		{
+			name:          "image not found after the timeout",
+			image:         &infrav1.Image{Name: ptr.To("Linux_k8s_1-30-4"), OSType: infrav1.OSTypeLinux},
+			existing:      []metav1.Condition{imageNotFound(azurestackhci.DefaultImageNotFoundTimeout + time.Minute)},
+			getErr:        status.Error(codes.NotFound, "gallery image Linux_k8s_1-30-4 not found"),
+			wantErr:       true,
+			wantTerminal:  true,
+			wantCalled:    true,
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.ImageNotFoundReason,
+			wantEvents:    1,
+		},
+		{
+			name:       "gallery unreachable",
+			image:      &infrav1.Image{Name: ptr.To("Linux_k8s_1-30-4"), OSType: infrav1.OSTypeLinux},
+			getErr:     status.Error(codes.Unavailable, "connection refused"),
+			wantErr:    true,
+			wantCalled: true,
+		},
+		{
+			name:          "gallery unreachable after the image was not found",
+			image:         &infrav1.Image{Name: ptr.To("Linux_k8s_1-30-4"), OSType: infrav1.OSTypeLinux},
+			existing:      []metav1.Condition{imageNotFound(azurestackhci.DefaultImageNotFoundTimeout + time.Minute)},
+			getErr:        status.Error(codes.Unavailable, "connection refused"),
+			wantErr:       true,
+			wantCalled:    true,
+			wantCondition: metav1.ConditionFalse,
+			wantReason:    infrav1.ImageNotFoundReason,
+		},
+		{
+			name:          "no image name",
+			image:         &infrav1.Image{OSType: infrav1.OSTypeLinux},
+			wantFound:     true,
+			wantCondition: metav1.ConditionTrue,
+			wantReason:    infrav1.ImageFoundReason,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			vmScope := &scope.VirtualMachineScope{
+				Logger:  logr.Discard(),
+				Context: context.Background(),
+				AzureStackHCIVirtualMachine: &infrav1.AzureStackHCIVirtualMachine{
+					Spec:   infrav1.AzureStackHCIVirtualMachineSpec{Image: tc.image, Location: "westus"},
+					Status: infrav1.AzureStackHCIVirtualMachineStatus{Conditions: tc.existing},
+				},
+			}
+			galleryImages := &fakeGalleryImagesService{err: tc.getErr}
+			ams := &azureStackHCIVirtualMachineService{vmScope: vmScope, galleryImagesSvc: galleryImages}
+			recorder := record.NewFakeRecorder(10)
+			r := &AzureStackHCIVirtualMachineReconciler{Recorder: recorder}
+
+			found, err := r.reconcileImage(vmScope, ams)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(Equal(tc.wantTerminal))
+			g.Expect(vmScope.AzureStackHCIVirtualMachine.Status.FailureReason != nil).To(Equal(tc.wantTerminal))
+			g.Expect(conditions.IsTrue(vmScope.AzureStackHCIVirtualMachine, infrav1.TerminalFailureCondition)).To(Equal(tc.wantTerminal))
+			g.Expect(found).To(Equal(tc.wantFound))
+			g.Expect(galleryImages.called).To(Equal(tc.wantCalled))
+			g.Expect(recorder.Events).To(HaveLen(tc.wantEvents))
+
+			condition := conditions.Get(vmScope.AzureStackHCIVirtualMachine, infrav1.ImageAvailableCondition)
+			if tc.wantCondition == "" {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.wantCondition))
+			g.Expect(condition.Reason).To(Equal(tc.wantReason))
+			if tc.wantCondition == metav1.ConditionFalse && len(tc.existing) == 0 {
+				g.Expect(condition.Message).To(ContainSubstring("Linux_k8s_1-30-4"))
+				// the reason is surfaced to CAPI through the Ready condition too
+				g.Expect(conditions.GetReason(vmScope.AzureStackHCIVirtualMachine, clusterv1.ReadyCondition)).To(Equal(infrav1.ImageNotFoundReason))
+			}
+		})
+	}
+}
+
+// fakePrecheckService returns fits, reason and err from Precheck and records whether it was called.
+type fakePrecheckService struct {
+	fits   bool
+	reason string
+	err    error
+	called bool
+}
+
+func (f *fakePrecheckService) Precheck(ctx context.Context, spec interface{}) (bool, string, error) {
+	f.called = true
+	return f.fits, f.reason, f.err
+}
+
+// TestReconcileCapacity verifies that a virtual machine that doesn't fit on any host waits with the
+// WaitingForCapacity reason, that the next check is backed off, and that the checks don't count as
+// creations that failed for lack of capacity.
+func TestReconcileCapacity(t *testing.T) {
+	tests := []struct {
+		name       string
+		precheck   *fakePrecheckService
+		status     infrav1.AzureStackHCIVirtualMachineStatus
+		wantDelay  time.Duration
+		wantCalled bool
+		wantChecks int32
+		wantReason string
+	}{
+		{
+			name:       "fits",
+			precheck:   &fakePrecheckService{fits: true},
+			wantCalled: true,
+		},
+		{
+			name:       "does not fit",
+			precheck:   &fakePrecheckService{reason: "insufficient memory"},
+			wantDelay:  30 * time.Second,
+			wantCalled: true,
+			wantChecks: 1,
+			wantReason: infrav1.WaitingForCapacityReason,
+		},
+		{
+			name:       "does not fit again after a failed creation",
+			precheck:   &fakePrecheckService{reason: "insufficient memory"},
+			status:     infrav1.AzureStackHCIVirtualMachineStatus{CapacityRetries: 1, CapacityChecks: 2},
+			wantDelay:  2 * time.Minute,
+			wantCalled: true,
+			wantChecks: 3,
+			wantReason: infrav1.WaitingForCapacityReason,
+		},
+		{
+			name:       "check pending",
+			precheck:   &fakePrecheckService{},
+			status:     infrav1.AzureStackHCIVirtualMachineStatus{NextCapacityCheckTime: &metav1.Time{Time: time.Now().Add(time.Minute)}},
+			wantDelay:  time.Minute,
+			wantCalled: false,
+		},
+		{
+			name:       "precheck failed",
+			precheck:   &fakePrecheckService{err: status.Error(codes.Unavailable, "connection refused")},
+			wantCalled: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			vmScope := &scope.VirtualMachineScope{
+				Logger:  logr.Discard(),
+				Context: context.Background(),
+				AzureStackHCIVirtualMachine: &infrav1.AzureStackHCIVirtualMachine{
+					Spec:   infrav1.AzureStackHCIVirtualMachineSpec{VMSize: "Standard_A4_v2"},
+					Status: tc.status,
+				},
+			}
+			ams := &azureStackHCIVirtualMachineService{vmScope: vmScope, precheckSvc: tc.precheck}
+			r := &AzureStackHCIVirtualMachineReconciler{Recorder: record.NewFakeRecorder(10)}
+
+			delay, err := r.reconcileCapacity(vmScope, ams)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(delay).To(BeNumerically("~", tc.wantDelay, time.Second))
+			g.Expect(tc.precheck.called).To(Equal(tc.wantCalled))
+			g.Expect(vmScope.AzureStackHCIVirtualMachine.Status.CapacityChecks).To(Equal(tc.wantChecks))
+			// only creations that failed for lack of capacity make it terminal
+			g.Expect(vmScope.AzureStackHCIVirtualMachine.Status.CapacityRetries).To(Equal(tc.status.CapacityRetries))
+			g.Expect(conditions.GetReason(vmScope.AzureStackHCIVirtualMachine, infrav1.VMRunningCondition)).To(Equal(tc.wantReason))
+			if tc.wantReason != "" {
+				g.Expect(vmScope.AzureStackHCIVirtualMachine.Status.NextCapacityCheckTime).NotTo(BeNil())
+				g.Expect(conditions.GetMessage(vmScope.AzureStackHCIVirtualMachine, infrav1.VMRunningCondition)).To(ContainSubstring("insufficient memory"))
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcilePowerState applies the power operation requested with the power-operation annotation, then starts or stops
+// the virtual machine to match spec.powerState. The power state reported by the cloud agent is kept in the status.
+func (r *AzureStackHCIVirtualMachineReconciler) reconcilePowerState(virtualMachineScope *scope.VirtualMachineScope, ams *azureStackHCIVirtualMachineService, vm *infrav1.VM) (reconcile.Result, error) {
+	virtualMachineScope.SetPowerState(vm.PowerState)
+
+	desired := virtualMachineScope.DesiredPowerState()
+	operation, requested := virtualMachineScope.PowerOperation()
+	if requested {
+		return r.reconcilePowerOperation(virtualMachineScope, ams, operation, desired)
+	}
+	if desired == nil {
+		conditions.Delete(virtualMachineScope.AzureStackHCIVirtualMachine, infrav1.VMPowerStateSyncedCondition)
+		return reconcile.Result{}, nil
+	}
+
+	switch vm.PowerState {
+	case *desired:
+		conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+			Type:   infrav1.VMPowerStateSyncedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: string(*desired),
+		})
+		return reconcile.Result{}, nil
+	case infrav1.VMPowerStateUnknown:
+		// don't act on a power state the cloud agent didn't report
+		conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+			Type:    infrav1.VMPowerStateSyncedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.VMPowerStateChangingReason,
+			Message: "Waiting for the power state of the virtual machine to be reported",
+		})
+		return reconcile.Result{RequeueAfter: 20 * time.Second}, nil
+	}
+
+	operation = infrav1.VMPowerOperationStart
+	if *desired == infrav1.VMPowerStateStopped {
+		operation = infrav1.VMPowerOperationStop
+	}
+	if err := ams.ApplyPowerOperation(operation); err != nil {
+		return reconcileResultForError(r.setPowerOperationFailed(virtualMachineScope, operation, err), err)
+	}
+
+	r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeNormal, "PowerStateChanged",
+		"Changed power state of AzureStackHCIVirtualMachine %s/%s from %s to %s", virtualMachineScope.Namespace(), virtualMachineScope.Name(), vm.PowerState, *desired)
+	conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+		Type:    infrav1.VMPowerStateSyncedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  infrav1.VMPowerStateChangingReason,
+		Message: fmt.Sprintf("Changing power state from %s to %s", vm.PowerState, *desired),
+	})
+	return reconcile.Result{RequeueAfter: 20 * time.Second}, nil
+}
+
+// reconcilePowerOperation applies a one-off power operation and removes its annotation. A failed operation keeps the
+// annotation, so that it is retried.
+func (r *AzureStackHCIVirtualMachineReconciler) reconcilePowerOperation(virtualMachineScope *scope.VirtualMachineScope, ams *azureStackHCIVirtualMachineService, operation infrav1.VMPowerOperation, desired *infrav1.VMPowerState) (reconcile.Result, error) {
+	if err := validatePowerOperation(operation, desired); err != nil {
+		r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeWarning, "InvalidPowerOperation", err.Error())
+		conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+			Type:    infrav1.VMPowerStateSyncedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  infrav1.VMInvalidPowerOperationReason,
+			Message: err.Error(),
+		})
+		virtualMachineScope.ClearPowerOperation()
+		return reconcile.Result{}, nil
+	}
+
+	if err := ams.ApplyPowerOperation(operation); err != nil {
+		class := r.setPowerOperationFailed(virtualMachineScope, operation, err)
+		if class.Terminal {
+			// retrying the operation would fail the same way
+			virtualMachineScope.ClearPowerOperation()
+		}
+		return reconcileResultForError(class, err)
+	}
+
+	virtualMachineScope.ClearPowerOperation()
+	r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeNormal, "PowerOperationApplied",
+		"Applied power operation %s to AzureStackHCIVirtualMachine %s/%s", operation, virtualMachineScope.Namespace(), virtualMachineScope.Name())
+	conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+		Type:    infrav1.VMPowerStateSyncedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "PowerOperationApplied",
+		Message: fmt.Sprintf("Applied power operation %s", operation),
+	})
+	// refresh the power state once the operation took effect
+	return reconcile.Result{RequeueAfter: 20 * time.Second}, nil
+}
+
+// setPowerOperationFailed reports a power operation that failed on the virtual machine and returns the class of the error.
+func (r *AzureStackHCIVirtualMachineReconciler) setPowerOperationFailed(virtualMachineScope *scope.VirtualMachineScope, operation infrav1.VMPowerOperation, err error) azurestackhci.ErrorClass {
+	class := azurestackhci.ClassifyError(err, infrav1.VMPowerOperationFailedReason)
+	r.Recorder.Eventf(virtualMachineScope.AzureStackHCIVirtualMachine, corev1.EventTypeWarning, "FailurePowerOperation",
+		"Failed power operation %s on AzureStackHCIVirtualMachine %s/%s: %s", operation, virtualMachineScope.Namespace(), virtualMachineScope.Name(), err.Error())
+	conditions.Set(virtualMachineScope.AzureStackHCIVirtualMachine, metav1.Condition{
+		Type:    infrav1.VMPowerStateSyncedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  class.Reason,
+		Message: err.Error(),
+	})
+	return class
+}
+
+// validatePowerOperation returns an error for unknown power operations and for operations that would be reverted
+// right away to match the desired power state.
+func validatePowerOperation(operation infrav1.VMPowerOperation, desired *infrav1.VMPowerState) error {
+	var conflicting infrav1.VMPowerState
+	switch operation {
+	case infrav1.VMPowerOperationStart, infrav1.VMPowerOperationRestart, infrav1.VMPowerOperationPowerCycle:
+		conflicting = infrav1.VMPowerStateStopped
+	case infrav1.VMPowerOperationStop, infrav1.VMPowerOperationShutdown:
+		conflicting = infrav1.VMPowerStateRunning
+	default:
+		return errors.Errorf("unknown power operation %q, expected one of start, stop, shutdown, restart or powercycle", operation)
+	}
+	if desired != nil && *desired == conflicting {
+		return errors.Errorf("power operation %s conflicts with the desired power state %s", operation, *desired)
+	}
+	return nil
+}
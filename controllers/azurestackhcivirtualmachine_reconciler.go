@@ -25,11 +25,14 @@ import (
 	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/scope"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/disks"
+	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/galleryimages"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/networkinterfaces"
 	"github.com/microsoft/cluster-api-provider-azurestackhci/cloud/services/virtualmachines"
 	infrav1util "github.com/microsoft/cluster-api-provider-azurestackhci/pkg/util"
 	sdk_compute "github.com/microsoft/moc-sdk-for-go/services/compute"
+	sdk_network "github.com/microsoft/moc-sdk-for-go/services/network"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -43,18 +46,25 @@ const (
 // TODO: We should decide if we want to keep this
 type azureStackHCIVirtualMachineService struct {
 	vmScope              *scope.VirtualMachineScope
-	networkInterfacesSvc azurestackhci.Service
+	networkInterfacesSvc azurestackhci.GetterService
 	virtualMachinesSvc   azurestackhci.GetterService
+	powerSvc             azurestackhci.PowerService
+	precheckSvc          azurestackhci.PrecheckService
 	disksSvc             azurestackhci.GetterService
+	galleryImagesSvc     azurestackhci.GetterService
 }
 
 // newAzureStackHCIMachineService populates all the services based on input scope
 func newAzureStackHCIVirtualMachineService(vmScope *scope.VirtualMachineScope) *azureStackHCIVirtualMachineService {
+	virtualMachinesSvc := virtualmachines.NewService(vmScope)
 	return &azureStackHCIVirtualMachineService{
 		vmScope:              vmScope,
 		networkInterfacesSvc: networkinterfaces.NewService(vmScope),
-		virtualMachinesSvc:   virtualmachines.NewService(vmScope),
+		virtualMachinesSvc:   virtualMachinesSvc,
+		powerSvc:             virtualMachinesSvc,
+		precheckSvc:          virtualMachinesSvc,
 		disksSvc:             disks.NewService(vmScope),
+		galleryImagesSvc:     galleryimages.NewService(vmScope),
 	}
 }
 
@@ -75,6 +85,9 @@ func (s *azureStackHCIVirtualMachineService) Create() (*infrav1.VM, error) {
 				Name:    ipconfigName,
 				Primary: ipconfigSpec.Primary,
 			}
+			if ipconfigSpec.IPVersion == infrav1.IPv6 {
+				ipconfig.IPVersion = sdk_network.IPv6
+			}
 
 			ipconfigs = append(ipconfigs, ipconfig)
 		}
@@ -93,6 +106,85 @@ func (s *azureStackHCIVirtualMachineService) Create() (*infrav1.VM, error) {
 	return vm, nil
 }
 
+// ImageExists returns false when the gallery image of the machine doesn't exist in its location.
+// Machines without an image name are left to MOC to validate.
+func (s *azureStackHCIVirtualMachineService) ImageExists() (bool, error) {
+	image := s.vmScope.AzureStackHCIVirtualMachine.Spec.Image
+	if image == nil || image.Name == nil || *image.Name == "" {
+		return true, nil
+	}
+
+	imageSpec := &galleryimages.Spec{
+		Name:     *image.Name,
+		Location: s.vmScope.Location(),
+	}
+	if _, err := s.galleryImagesSvc.Get(s.vmScope.Context, imageSpec); err != nil {
+		if azurestackhci.ResourceNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get gallery image %s", *image.Name)
+	}
+	return true, nil
+}
+
+// Precheck returns false with the reason reported by MOC when no host has the capacity for the machine.
+func (s *azureStackHCIVirtualMachineService) Precheck() (bool, string, error) {
+	vmSpec := &virtualmachines.Spec{
+		Name:                s.vmScope.Name(),
+		Size:                s.vmScope.AzureStackHCIVirtualMachine.Spec.VMSize,
+		CustomSize:          s.vmScope.AzureStackHCIVirtualMachine.Spec.CustomSize,
+		GpuCount:            s.vmScope.AzureStackHCIVirtualMachine.Spec.GpuCount,
+		VMType:              s.vmType(),
+		StorageContainer:    s.vmScope.StorageContainer(),
+		AvailabilitySetName: s.vmScope.AzureStackHCIVirtualMachine.Spec.AvailabilitySetName,
+		PlacementGroupName:  s.vmScope.AzureStackHCIVirtualMachine.Spec.PlacementGroupName,
+	}
+	if s.vmScope.AzureStackHCIVirtualMachine.Spec.OSDisk != nil {
+		vmSpec.OSDisk = *s.vmScope.AzureStackHCIVirtualMachine.Spec.OSDisk
+	}
+	if s.vmScope.AzureStackHCIVirtualMachine.Spec.Image != nil {
+		vmSpec.Image = *s.vmScope.AzureStackHCIVirtualMachine.Spec.Image
+	}
+	return s.precheckSvc.Precheck(s.vmScope.Context, vmSpec)
+}
+
+// vmType returns the MOC type of the virtual machine.
+func (s *azureStackHCIVirtualMachineService) vmType() sdk_compute.VMType {
+	if s.vmScope.AzureStackHCILoadBalancerVM() {
+		return sdk_compute.LoadBalancer
+	}
+	return sdk_compute.Tenant
+}
+
+// GetAddresses returns the internal addresses of the ip configurations of the machine network interface.
+func (s *azureStackHCIVirtualMachineService) GetAddresses() ([]corev1.NodeAddress, error) {
+	nicName := azurestackhci.GenerateNICName(s.vmScope.Name())
+	nicInterface, err := s.networkInterfacesSvc.Get(s.vmScope.Context, &networkinterfaces.Spec{Name: nicName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get nic %s", nicName)
+	}
+
+	nic, ok := nicInterface.(sdk_network.Interface)
+	if !ok {
+		return nil, errors.Errorf("invalid network interface %s", nicName)
+	}
+
+	addresses := []corev1.NodeAddress{}
+	if nic.InterfacePropertiesFormat == nil || nic.IPConfigurations == nil {
+		return addresses, nil
+	}
+	for _, ipconfig := range *nic.IPConfigurations {
+		if ipconfig.InterfaceIPConfigurationPropertiesFormat == nil || ipconfig.PrivateIPAddress == nil || *ipconfig.PrivateIPAddress == "" {
+			continue
+		}
+		addresses = append(addresses, corev1.NodeAddress{
+			Type:    corev1.NodeInternalIP,
+			Address: *ipconfig.PrivateIPAddress,
+		})
+	}
+	return addresses, nil
+}
+
 // Delete reconciles all the services in pre determined order
 func (s *azureStackHCIVirtualMachineService) Delete() error {
 	vmSpec := &virtualmachines.Spec{
@@ -208,10 +300,29 @@ func (s *azureStackHCIVirtualMachineService) VMIfExists() (*infrav1.VM, error) {
 	return vm, nil
 }
 
-// getVirtualMachineZone gets a random availability zones from available set,
-// this will hopefully be an input from upstream machinesets so all the vms are balanced
-func (s *azureStackHCIVirtualMachineService) getVirtualMachineZone() (string, error) {
-	return "", nil
+// ApplyPowerOperation applies the power operation to the virtual machine.
+func (s *azureStackHCIVirtualMachineService) ApplyPowerOperation(operation infrav1.VMPowerOperation) error {
+	vmSpec := &virtualmachines.Spec{
+		Name: s.vmScope.Name(),
+	}
+	if image := s.vmScope.AzureStackHCIVirtualMachine.Spec.Image; image != nil {
+		vmSpec.Image = *image
+	}
+
+	switch operation {
+	case infrav1.VMPowerOperationStart:
+		return s.powerSvc.Start(s.vmScope.Context, vmSpec)
+	case infrav1.VMPowerOperationStop:
+		return s.powerSvc.Stop(s.vmScope.Context, vmSpec)
+	case infrav1.VMPowerOperationShutdown:
+		return s.powerSvc.Shutdown(s.vmScope.Context, vmSpec)
+	case infrav1.VMPowerOperationRestart:
+		return s.powerSvc.Restart(s.vmScope.Context, vmSpec)
+	case infrav1.VMPowerOperationPowerCycle:
+		return s.powerSvc.PowerCycle(s.vmScope.Context, vmSpec)
+	default:
+		return errors.Errorf("unknown power operation %q", operation)
+	}
 }
 
 func (s *azureStackHCIVirtualMachineService) reconcileDisk(disk infrav1.OSDisk) error {
@@ -267,31 +378,7 @@ func (s *azureStackHCIVirtualMachineService) createVirtualMachine(nicName string
 
 	vmInterface, err := s.virtualMachinesSvc.Get(s.vmScope.Context, vmSpec)
 	if err != nil && vmInterface == nil {
-		var vmZone string
-
-		azSupported := s.isAvailabilityZoneSupported()
-
-		if azSupported {
-			useAZ := true
-
-			if s.vmScope.AzureStackHCIVirtualMachine.Spec.AvailabilityZone.Enabled != nil {
-				useAZ = *s.vmScope.AzureStackHCIVirtualMachine.Spec.AvailabilityZone.Enabled
-			}
-
-			if useAZ {
-				var zoneErr error
-				vmZone, zoneErr = s.getVirtualMachineZone()
-				if zoneErr != nil {
-					return nil, errors.Wrap(zoneErr, "failed to get availability zone")
-				}
-			}
-		}
-
-		vmType := sdk_compute.Tenant
-		if s.vmScope.AzureStackHCILoadBalancerVM() {
-			vmType = sdk_compute.LoadBalancer
-		}
-
+		vmType := s.vmType()
 		s.vmScope.Info("VM type is:", "vmType", vmType)
 
 		vmSpec = &virtualmachines.Spec{
@@ -299,9 +386,9 @@ func (s *azureStackHCIVirtualMachineService) createVirtualMachine(nicName string
 			NICName:             nicName,
 			SSHKeyData:          decodedKeys,
 			Size:                s.vmScope.AzureStackHCIVirtualMachine.Spec.VMSize,
+			CustomSize:          s.vmScope.AzureStackHCIVirtualMachine.Spec.CustomSize,
 			GpuCount:            s.vmScope.AzureStackHCIVirtualMachine.Spec.GpuCount,
 			CustomData:          *s.vmScope.AzureStackHCIVirtualMachine.Spec.BootstrapData,
-			Zone:                vmZone,
 			VMType:              vmType,
 			StorageContainer:    s.vmScope.StorageContainer(),
 			AvailabilitySetName: s.vmScope.AzureStackHCIVirtualMachine.Spec.AvailabilitySetName,
@@ -348,20 +435,3 @@ func (s *azureStackHCIVirtualMachineService) createVirtualMachine(nicName string
 
 	return vm, nil
 }
-
-// isAvailabilityZoneSupported determines if Availability Zones are supported in a selected location
-// based on SupportedAvailabilityZoneLocations. Returns true if supported.
-func (s *azureStackHCIVirtualMachineService) isAvailabilityZoneSupported() bool {
-	azSupported := false
-
-	for _, supportedLocation := range azurestackhci.SupportedAvailabilityZoneLocations {
-		if s.vmScope.Location() == supportedLocation {
-			azSupported = true
-
-			return azSupported
-		}
-	}
-
-	s.vmScope.V(2).Info("Availability Zones are not supported in the selected location", "location", s.vmScope.Location())
-	return azSupported
-}
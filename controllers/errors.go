@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+Portions Copyright © Microsoft Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+)
+
+// capacityRetrier counts the consecutive failures of a resource for lack of capacity.
+type capacityRetrier interface {
+	AddCapacityRetry() int32
+}
+
+// classifyError classifies an error returned by MOC with azurestackhci.ClassifyError. Capacity errors are backed off
+// with the consecutive failures counted by retrier, a nil retrier leaves them to the rate limited backoff.
+func classifyError(err error, defaultReason string, retrier capacityRetrier) azurestackhci.ErrorClass {
+	class := azurestackhci.ClassifyError(err, defaultReason)
+	if class.Capacity && retrier != nil {
+		class.RequeueAfter = azurestackhci.CapacityBackoff(retrier.AddCapacityRetry())
+	}
+	return class
+}
+
+// reconcileResultForError returns the result of a reconciliation that failed with an error classified by
+// azurestackhci.ClassifyError. Terminal errors aren't retried until the object changes, errors with a suggested delay
+// are requeued after it instead of the rate limited backoff.
+func reconcileResultForError(class azurestackhci.ErrorClass, err error) (reconcile.Result, error) {
+	switch {
+	case class.Terminal:
+		return reconcile.Result{}, reconcile.TerminalError(err)
+	case class.RequeueAfter > 0:
+		return reconcile.Result{RequeueAfter: class.RequeueAfter}, nil
+	default:
+		return reconcile.Result{}, err
+	}
+}
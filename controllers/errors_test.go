@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	mocerrors "github.com/microsoft/moc/pkg/errors"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/microsoft/cluster-api-provider-azurestackhci/api/v1beta2"
+	azurestackhci "github.com/microsoft/cluster-api-provider-azurestackhci/cloud"
+)
+
+// fakeCapacityRetrier counts the capacity retries it was asked to add.
+type fakeCapacityRetrier struct {
+	retries int32
+}
+
+func (f *fakeCapacityRetrier) AddCapacityRetry() int32 {
+	f.retries++
+	return f.retries
+}
+
+// TestClassifyError verifies that capacity errors are backed off with the consecutive failures of the resource, and
+// that other errors don't count as capacity retries.
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		retries     int32
+		wantReason  string
+		wantRetries int32
+		wantDelay   time.Duration
+	}{
+		{
+			name:       "retryable",
+			err:        errors.New("failure"),
+			wantReason: infrav1.ClusterReconciliationFailedReason,
+		},
+		{
+			name:        "first lack of capacity",
+			err:         errors.Wrap(mocerrors.OutOfCapacity, "failed to create"),
+			wantReason:  infrav1.OutOfCapacityReason,
+			wantRetries: 1,
+			wantDelay:   azurestackhci.DefaultCapacityBackoff,
+		},
+		{
+			name:        "repeated lack of capacity",
+			err:         errors.Wrap(mocerrors.OutOfMemory, "failed to create"),
+			retries:     2,
+			wantReason:  infrav1.OutOfMemoryReason,
+			wantRetries: 3,
+			wantDelay:   4 * azurestackhci.DefaultCapacityBackoff,
+		},
+		{
+			name:       "invalid configuration",
+			err:        errors.Wrap(mocerrors.InvalidConfiguration, "failed to create"),
+			wantReason: infrav1.InvalidConfigurationReason,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			retrier := &fakeCapacityRetrier{retries: tc.retries}
+			class := classifyError(tc.err, infrav1.ClusterReconciliationFailedReason, retrier)
+			g.Expect(class.Reason).To(Equal(tc.wantReason))
+			g.Expect(class.RequeueAfter).To(Equal(tc.wantDelay))
+			if tc.wantRetries == 0 {
+				tc.wantRetries = tc.retries
+			}
+			g.Expect(retrier.retries).To(Equal(tc.wantRetries))
+
+			// without a retrier, capacity errors are left to the rate limited backoff
+			g.Expect(classifyError(tc.err, infrav1.ClusterReconciliationFailedReason, nil).RequeueAfter).To(BeZero())
+		})
+	}
+}
+
+// TestReconcileResultForError verifies that terminal errors fail the reconciliation for good, and that errors with a
+// suggested delay are requeued after it.
+func TestReconcileResultForError(t *testing.T) {
+	failure := errors.New("failure")
+	tests := []struct {
+		name         string
+		class        azurestackhci.ErrorClass
+		wantResult   reconcile.Result
+		wantErr      bool
+		wantTerminal bool
+	}{
+		{
+			name:    "retryable",
+			class:   azurestackhci.ErrorClass{Reason: "Failed"},
+			wantErr: true,
+		},
+		{
+			name:       "delayed",
+			class:      azurestackhci.ErrorClass{Reason: "MOCUnreachable", RequeueAfter: 30 * time.Second},
+			wantResult: reconcile.Result{RequeueAfter: 30 * time.Second},
+		},
+		{
+			name:         "terminal",
+			class:        azurestackhci.ErrorClass{Reason: "InvalidConfiguration", Terminal: true},
+			wantErr:      true,
+			wantTerminal: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := reconcileResultForError(tc.class, failure)
+			g.Expect(result).To(Equal(tc.wantResult))
+			if !tc.wantErr {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(errors.Is(err, failure)).To(BeTrue())
+			g.Expect(errors.Is(err, reconcile.TerminalError(nil))).To(Equal(tc.wantTerminal))
+		})
+	}
+}
@@ -25,3 +25,5 @@ flavors_dir="${root}/templates/flavors/"
 
 kustomize build ${flavors_dir}/mgmt > ${templates_dir}/cluster-template-mgmt.yaml
 kustomize build ${flavors_dir}/default > ${templates_dir}/cluster-template.yaml
+kustomize build ${flavors_dir}/clusterclass > ${templates_dir}/clusterclass-azurestackhci.yaml
+kustomize build ${flavors_dir}/topology > ${templates_dir}/cluster-template-topology.yaml
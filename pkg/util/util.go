@@ -50,6 +50,22 @@ func GetAzureStackHCIMachinesInCluster(ctx context.Context, controllerClient cli
 	return machines, nil
 }
 
+// GetAzureStackHCIImageByGalleryImageName returns the AzureStackHCIImage that imports the gallery image with the given name,
+// nil if the gallery image isn't managed with an AzureStackHCIImage.
+func GetAzureStackHCIImageByGalleryImageName(ctx context.Context, controllerClient client.Client, namespace, galleryImageName string) (*infrav1.AzureStackHCIImage, error) {
+	imageList := &infrav1.AzureStackHCIImageList{}
+	if err := controllerClient.List(ctx, imageList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	for i := range imageList.Items {
+		if imageList.Items[i].GalleryImageName() == galleryImageName {
+			return &imageList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
 // Create a target cluster config based on the secret in the management cluster
 func NewTargetClusterConfig(ctx context.Context, controllerClient client.Reader, clusterKey client.ObjectKey) (*rest.Config, error) {
 	kubeconfig, err := kubeconfig.FromSecret(ctx, controllerClient, clusterKey)